@@ -47,6 +47,11 @@
 //	a.EltEncoder.Decode(bs)
 //
 // An array of specific type such as "U32" does not requires additional alloc.
+//
+// # 64-bit index
+//
+// Base64 is the counterpart of Array with int64 indexes, for indexes that do
+// not fit in an int32, such as file offsets beyond 2GB.
 package array
 
 import (
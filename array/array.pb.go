@@ -38,7 +38,13 @@ type Array32 struct {
 	// BMElts is optimized for elt itself is a bitmap.
 	//
 	// Since 0.5.4
-	BMElts               *Bits    `protobuf:"bytes,30,opt,name=BMElts,proto3" json:"BMElts,omitempty"`
+	BMElts *Bits `protobuf:"bytes,30,opt,name=BMElts,proto3" json:"BMElts,omitempty"`
+	// EltOffsets is the byte offset in Elts of every element, plus a last
+	// one which is len(Elts).
+	// It is empty if elements are fixed-size.
+	//
+	// Since 0.5.13
+	EltOffsets           []int32  `protobuf:"varint,40,rep,packed,name=EltOffsets,proto3" json:"EltOffsets,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -48,7 +54,7 @@ func (m *Array32) Reset()         { *m = Array32{} }
 func (m *Array32) String() string { return proto.CompactTextString(m) }
 func (*Array32) ProtoMessage()    {}
 func (*Array32) Descriptor() ([]byte, []int) {
-	return fileDescriptor_array_a888684a17223a9c, []int{0}
+	return fileDescriptor_array_613c5b849f2f370d, []int{0}
 }
 func (m *Array32) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Array32.Unmarshal(m, b)
@@ -117,17 +123,119 @@ func (m *Array32) GetBMElts() *Bits {
 	return nil
 }
 
+func (m *Array32) GetEltOffsets() []int32 {
+	if m != nil {
+		return m.EltOffsets
+	}
+	return nil
+}
+
+// Array64 is the same as Array32 except it uses int64 index.
+// Fields with the same name have the same field number as Array32, thus an
+// Array32 can be loaded into an Array64.
+//
+// Since 0.5.13
+type Array64 struct {
+	Cnt     int64    `protobuf:"varint,1,opt,name=Cnt,proto3" json:"Cnt,omitempty"`
+	Bitmaps []uint64 `protobuf:"varint,2,rep,packed,name=Bitmaps,proto3" json:"Bitmaps,omitempty"`
+	Offsets []int64  `protobuf:"varint,3,rep,packed,name=Offsets,proto3" json:"Offsets,omitempty"`
+	Elts    []byte   `protobuf:"bytes,4,opt,name=Elts,proto3" json:"Elts,omitempty"`
+	// WordIndexes is the index of every word in Bitmaps if Bitmaps contains
+	// only non-empty words. Otherwise it is empty and Bitmaps[i] is the i-th
+	// word.
+	//
+	// Since 0.5.13
+	WordIndexes []int64 `protobuf:"varint,5,rep,packed,name=WordIndexes,proto3" json:"WordIndexes,omitempty"`
+	// EltOffsets is the byte offset in Elts of every element, plus a last
+	// one which is len(Elts).
+	// It is empty if elements are fixed-size.
+	// It has the same encoding as Array32.EltOffsets.
+	//
+	// Since 0.5.13
+	EltOffsets           []int64  `protobuf:"varint,40,rep,packed,name=EltOffsets,proto3" json:"EltOffsets,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Array64) Reset()         { *m = Array64{} }
+func (m *Array64) String() string { return proto.CompactTextString(m) }
+func (*Array64) ProtoMessage()    {}
+func (*Array64) Descriptor() ([]byte, []int) {
+	return fileDescriptor_array_613c5b849f2f370d, []int{1}
+}
+func (m *Array64) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Array64.Unmarshal(m, b)
+}
+func (m *Array64) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Array64.Marshal(b, m, deterministic)
+}
+func (dst *Array64) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Array64.Merge(dst, src)
+}
+func (m *Array64) XXX_Size() int {
+	return xxx_messageInfo_Array64.Size(m)
+}
+func (m *Array64) XXX_DiscardUnknown() {
+	xxx_messageInfo_Array64.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Array64 proto.InternalMessageInfo
+
+func (m *Array64) GetCnt() int64 {
+	if m != nil {
+		return m.Cnt
+	}
+	return 0
+}
+
+func (m *Array64) GetBitmaps() []uint64 {
+	if m != nil {
+		return m.Bitmaps
+	}
+	return nil
+}
+
+func (m *Array64) GetOffsets() []int64 {
+	if m != nil {
+		return m.Offsets
+	}
+	return nil
+}
+
+func (m *Array64) GetElts() []byte {
+	if m != nil {
+		return m.Elts
+	}
+	return nil
+}
+
+func (m *Array64) GetWordIndexes() []int64 {
+	if m != nil {
+		return m.WordIndexes
+	}
+	return nil
+}
+
+func (m *Array64) GetEltOffsets() []int64 {
+	if m != nil {
+		return m.EltOffsets
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Array32)(nil), "Array32")
+	proto.RegisterType((*Array64)(nil), "Array64")
 }
 
-func init() { proto.RegisterFile("array.proto", fileDescriptor_array_a888684a17223a9c) }
+func init() { proto.RegisterFile("array.proto", fileDescriptor_array_613c5b849f2f370d) }
 
-var fileDescriptor_array_a888684a17223a9c = []byte{
-	// 194 bytes of a gzipped FileDescriptorProto
+var fileDescriptor_array_613c5b849f2f370d = []byte{
+	// 247 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xe2, 0x4e, 0x2c, 0x2a, 0x4a,
 	0xac, 0xd4, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x97, 0xe2, 0x49, 0xca, 0x2c, 0xc9, 0x4d, 0x2c, 0x80,
-	0xf0, 0x94, 0xb6, 0x30, 0x72, 0xb1, 0x3b, 0x82, 0x64, 0x8d, 0x8d, 0x84, 0x04, 0xb8, 0x98, 0x9d,
+	0xf0, 0x94, 0xae, 0x30, 0x72, 0xb1, 0x3b, 0x82, 0x64, 0x8d, 0x8d, 0x84, 0x04, 0xb8, 0x98, 0x9d,
 	0xf3, 0x4a, 0x24, 0x18, 0x15, 0x18, 0x35, 0x58, 0x83, 0x40, 0x4c, 0x21, 0x09, 0x2e, 0x76, 0x27,
 	0xb0, 0xea, 0x62, 0x09, 0x26, 0x05, 0x66, 0x0d, 0x96, 0x20, 0x18, 0x17, 0x24, 0xe3, 0x9f, 0x96,
 	0x56, 0x9c, 0x5a, 0x52, 0x2c, 0xc1, 0xac, 0xc0, 0xac, 0xc1, 0x1a, 0x04, 0xe3, 0x0a, 0x09, 0x71,
@@ -135,7 +243,10 @@ var fileDescriptor_array_a888684a17223a9c = []byte{
 	0x5c, 0xac, 0x6e, 0x39, 0x89, 0xe9, 0xc5, 0x12, 0x5c, 0x0a, 0x8c, 0x1a, 0xbc, 0x41, 0x10, 0x8e,
 	0x90, 0x14, 0x17, 0x87, 0x6b, 0x4e, 0x49, 0x78, 0x66, 0x4a, 0x49, 0x86, 0x84, 0x08, 0xd8, 0x52,
 	0x38, 0x5f, 0x48, 0x96, 0x8b, 0xcd, 0xc9, 0x17, 0x6c, 0x8e, 0x9c, 0x02, 0xa3, 0x06, 0xb7, 0x11,
-	0xab, 0x9e, 0x53, 0x66, 0x49, 0x71, 0x10, 0x54, 0xd0, 0x89, 0x3d, 0x8a, 0x15, 0xec, 0xa7, 0x24,
-	0x36, 0xb0, 0x37, 0x8c, 0x01, 0x01, 0x00, 0x00, 0xff, 0xff, 0x24, 0x26, 0xfa, 0x74, 0xe3, 0x00,
-	0x00, 0x00,
+	0xab, 0x9e, 0x53, 0x66, 0x49, 0x71, 0x10, 0x54, 0x50, 0x48, 0x8e, 0x8b, 0xcb, 0x35, 0xa7, 0x04,
+	0xe6, 0x02, 0x0d, 0xb0, 0x0b, 0x90, 0x44, 0x94, 0x96, 0xc2, 0xbc, 0x65, 0x66, 0x82, 0xec, 0x2d,
+	0x66, 0x12, 0xbd, 0xc5, 0x8c, 0xdf, 0x5b, 0x0a, 0x5c, 0xdc, 0xe1, 0xf9, 0x45, 0x29, 0x9e, 0x79,
+	0x29, 0xa9, 0x15, 0xa9, 0xc5, 0x12, 0xac, 0x60, 0x1d, 0xc8, 0x42, 0x58, 0xdc, 0xc9, 0x8c, 0xec,
+	0x4e, 0x27, 0xf6, 0x28, 0x56, 0x70, 0xdc, 0x24, 0xb1, 0x81, 0xa3, 0xc3, 0x18, 0x30, 0x00, 0x90,
+	0x7c, 0xd9, 0xb8, 0xab, 0x01, 0x00, 0x00,
 }
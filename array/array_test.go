@@ -1,12 +1,15 @@
 package array_test
 
 import (
+	"compress/flate"
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 
 	proto "github.com/golang/protobuf/proto"
 	"github.com/openacid/slim/array"
+	"github.com/openacid/slim/encode"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -163,3 +166,108 @@ func BenchmarkArrayGet(b *testing.B) {
 		a.Get(5)
 	}
 }
+
+func TestArray_GetInto(t *testing.T) {
+
+	type D struct {
+		X int32
+		Y uint16
+	}
+
+	a := &array.Array{}
+
+	err := a.Init([]int32{10, 12, 13}, []D{{1, 2}, {3, 4}, {5, 6}})
+	if err != nil {
+		t.Fatalf("expect no err but: %v", err)
+	}
+
+	v := new(D)
+
+	found := a.GetInto(12, v)
+	if !found {
+		t.Fatalf("expect: %v; but: %v", true, false)
+	}
+	if *v != (D{3, 4}) {
+		t.Fatalf("expect: %v; but: %v", D{3, 4}, *v)
+	}
+
+	found = a.GetInto(11, v)
+	if found {
+		t.Fatalf("expect: %v; but: %v", false, true)
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		a.GetInto(13, v)
+	})
+	if allocs != 0 {
+		t.Fatalf("expect: %v allocs; but: %v", 0, allocs)
+	}
+}
+
+func TestArray_varLength(t *testing.T) {
+
+	cases := []struct {
+		enc  encode.Encoder
+		elts interface{}
+	}{
+		{encode.UVarint{}, []uint64{1, 300, 0, 1 << 40}},
+		{encode.StringVarint{}, []string{"a", "", "foo", "bar"}},
+		{encode.Compressed(encode.StringVarint{}, flate.BestCompression),
+			[]string{"a", strings.Repeat("abc", 100), "", "foo"}},
+	}
+
+	indexes := []int32{1, 5, 64, 300}
+
+	for _, c := range cases {
+
+		a := &array.Array{}
+		a.EltEncoder = c.enc
+
+		err := a.Init(indexes, c.elts)
+		if err != nil {
+			t.Fatalf("expect no err but: %v", err)
+		}
+		if len(a.EltOffsets) != len(indexes)+1 {
+			t.Fatalf("expect: %v EltOffsets; but: %v", len(indexes)+1, a.EltOffsets)
+		}
+
+		buf, err := proto.Marshal(a)
+		if err != nil {
+			t.Fatalf("expect no err but: %v", err)
+		}
+
+		b := &array.Array{}
+		b.EltEncoder = c.enc
+		err = proto.Unmarshal(buf, b)
+		if err != nil {
+			t.Fatalf("expect no err but: %v", err)
+		}
+
+		for _, x := range []*array.Array{a, b} {
+			for i, idx := range indexes {
+				v, found := x.Get(idx)
+				if !found {
+					t.Fatalf("expect: %v; but: %v", true, false)
+				}
+				want := reflect.ValueOf(c.elts).Index(i).Interface()
+				if !reflect.DeepEqual(want, v) {
+					t.Fatalf("expect: %v; but: %v", want, v)
+				}
+			}
+
+			_, found := x.Get(2)
+			if found {
+				t.Fatalf("expect: %v; but: %v", false, true)
+			}
+		}
+	}
+
+	// fixed size elements do not need EltOffsets
+	a, err := array.NewU32([]int32{1, 2}, []uint32{1, 2})
+	if err != nil {
+		t.Fatalf("expect no err but: %v", err)
+	}
+	if a.EltOffsets != nil {
+		t.Fatalf("expect: nil EltOffsets; but: %v", a.EltOffsets)
+	}
+}
@@ -2,12 +2,14 @@ package array
 
 import (
 	"encoding/binary"
+	"io"
 	"reflect"
 
 	proto "github.com/golang/protobuf/proto"
 	"github.com/openacid/errors"
 	"github.com/openacid/low/bitmap"
 	"github.com/openacid/slim/encode"
+	"github.com/openacid/slim/internal/pbstream"
 )
 
 // endian is the default endian for array
@@ -35,6 +37,43 @@ func (a *Base) Unmarshal(buf []byte) error {
 	return proto.Unmarshal(buf, &a.Array32)
 }
 
+// WriteTo writes the underlying Array32 to w field by field.
+// The output is the same as Marshal, but the entire marshaled bytes are never
+// built in memory.
+//
+// It implements io.WriterTo.
+//
+// Since 0.5.13
+func (a *Base) WriteTo(w io.Writer) (int64, error) {
+	return pbstream.Write(w, &a.Array32, nil)
+}
+
+// ReadFrom reads the underlying Array32 from r until EOF, record by record.
+//
+// It implements io.ReaderFrom.
+//
+// Since 0.5.13
+func (a *Base) ReadFrom(r io.Reader) (int64, error) {
+	a.Array32.Reset()
+	return pbstream.Read(r, -1, &a.Array32)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+// It is the same as Marshal.
+//
+// Since 0.5.13
+func (a *Base) MarshalBinary() ([]byte, error) {
+	return a.Marshal()
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+// It is the same as Unmarshal.
+//
+// Since 0.5.13
+func (a *Base) UnmarshalBinary(data []byte) error {
+	return a.Unmarshal(data)
+}
+
 const (
 	bmShift = uint(6) // log₂64
 	bmMask  = int32(63)
@@ -82,6 +121,12 @@ func (a *Base) InitIndex(index []int32) error {
 // otherwise, return the ErrIndexNotAscending error.
 // The "elts" is a slice.
 //
+// If a.EltEncoder is set before Init, elements can be var-length, such as
+// those encoded by encode.UVarint or encode.Compressed.
+// Elements are stored as fixed-size if every encoded element has the size
+// the encoder reports, by encode.FixedSizer, or by GetEncodedSize(nil) if
+// the encoder does not implement it.
+//
 // Since 0.2.0
 func (a *Base) Init(indexes []int32, elts interface{}) error {
 
@@ -132,20 +177,66 @@ func (a *Base) InitElts(elts interface{}, encoder encode.Encoder) (int, error) {
 
 	rElts := reflect.ValueOf(elts)
 	n := rElts.Len()
-	eltsize := encoder.GetEncodedSize(nil)
-	sz := eltsize * n
+	eltsize := fixedSizeOf(encoder)
+
+	b := make([]byte, 0)
+	if eltsize > 0 {
+		b = make([]byte, 0, eltsize*n)
+	}
 
-	b := make([]byte, 0, sz)
+	offsets := make([]int32, 0, n+1)
+	isFixed := eltsize >= 0
 	for i := 0; i < n; i++ {
+		offsets = append(offsets, int32(len(b)))
 		ee := rElts.Index(i).Interface()
-		bs := encoder.Encode(ee)
-		b = append(b, bs...)
+		b = encode.AppendEncode(encoder, b, ee)
+		if len(b)-int(offsets[i]) != eltsize {
+			isFixed = false
+		}
 	}
+	offsets = append(offsets, int32(len(b)))
 	a.Elts = b
 
+	if isFixed {
+		a.EltOffsets = nil
+	} else {
+		a.EltOffsets = offsets
+	}
+
 	return n, nil
 }
 
+// fixedSizeOf returns the size of every value encoded by encoder, or -1 if
+// values are var-length.
+// An encoder not implementing encode.FixedSizer is asked with
+// GetEncodedSize(nil), in which a panic means var-length.
+func fixedSizeOf(encoder encode.Encoder) (size int) {
+
+	if _, ok := encoder.(encode.FixedSizer); ok {
+		return encode.FixedSize(encoder)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			size = -1
+		}
+	}()
+
+	size = encoder.GetEncodedSize(nil)
+	if size < 0 {
+		size = -1
+	}
+	return size
+}
+
+// eltSize returns the fixed element size, or 0 if elements are var-length.
+func (a *Base) eltSize() int {
+	if len(a.EltOffsets) > 0 {
+		return 0
+	}
+	return a.EltEncoder.GetEncodedSize(nil)
+}
+
 // Get retrieves the value at "idx" and return it.
 // If this array has a value at "idx" it returns the value and "true",
 // otherwise it returns "nil" and "false".
@@ -153,7 +244,7 @@ func (a *Base) InitElts(elts interface{}, encoder encode.Encoder) (int, error) {
 // Since 0.2.0
 func (a *Base) Get(idx int32) (interface{}, bool) {
 
-	bs, ok := a.GetBytes(idx, a.EltEncoder.GetEncodedSize(nil))
+	bs, ok := a.GetBytes(idx, a.eltSize())
 	if ok {
 		_, v := a.EltEncoder.Decode(bs)
 		return v, true
@@ -162,6 +253,26 @@ func (a *Base) Get(idx int32) (interface{}, bool) {
 	return nil, false
 }
 
+// GetInto retrieves the value at "idx" and stores it into "dst", which is a
+// pointer to the element type, e.g., a *uint32 for an array of uint32.
+// It returns true if this array has a value at "idx", otherwise "dst" is not
+// changed.
+//
+// If a.EltEncoder implements encode.AppendDecoder, such as all built-in
+// encoders, it involves 0 alloc.
+//
+// Since 0.5.13
+func (a *Base) GetInto(idx int32, dst interface{}) bool {
+
+	bs, ok := a.GetBytes(idx, a.eltSize())
+	if ok {
+		encode.DecodeInto(a.EltEncoder, bs, dst)
+		return true
+	}
+
+	return false
+}
+
 // GetBytes retrieves the raw data of value in []byte at "idx" and return it.
 //
 // # Performance note
@@ -173,6 +284,9 @@ func (a *Base) Get(idx int32) (interface{}, bool) {
 //
 // # Involves 0 alloc
 //
+// If elements are var-length, i.e., a.EltOffsets is not empty, "eltsize" is
+// ignored.
+//
 // Since 0.2.0
 func (a *Base) GetBytes(idx int32, eltsize int) ([]byte, bool) {
 	r, b := bitmap.Rank64(a.Bitmaps, a.Offsets, idx)
@@ -180,6 +294,10 @@ func (a *Base) GetBytes(idx int32, eltsize int) ([]byte, bool) {
 		return nil, false
 	}
 
+	if len(a.EltOffsets) > 0 {
+		return a.Elts[a.EltOffsets[r]:a.EltOffsets[r+1]], true
+	}
+
 	stIdx := int32(eltsize) * r
 	return a.Elts[stIdx : stIdx+int32(eltsize)], true
 }
@@ -0,0 +1,426 @@
+package array
+
+import (
+	"io"
+	"math/bits"
+	"reflect"
+	"sort"
+
+	proto "github.com/golang/protobuf/proto"
+	"github.com/openacid/errors"
+	"github.com/openacid/slim/encode"
+	"github.com/openacid/slim/internal/pbstream"
+)
+
+// Base64 is the same as Array except it accepts int64 indexes.
+// It is used when indexes exceed the range of int32, such as a byte offset
+// beyond 2GB or a 64-bit id.
+//
+// Like Array it allocates 1 bit for every absent or present element.
+// But if indexes are very sparse, it stores only the non-empty bitmap words,
+// thus the memory overhead is decided by the number of elements instead of
+// the max index.
+//
+// The underlying Array64 has the same protobuf field numbers as Array32, thus
+// data marshaled from an Array32 can be loaded into a Base64, including
+// var-length elements with EltOffsets.
+//
+// Since 0.5.13
+type Base64 struct {
+	Array64
+	EltEncoder encode.Encoder
+}
+
+// New64 creates a Base64 from specified indexes and elts.
+// The length of indexes and the length of elts must be the same.
+// "elts" must be a slice of fixed-size values.
+// To store var-length values, set EltEncoder before calling Init.
+//
+// Since 0.5.13
+func New64(indexes []int64, elts interface{}) (*Base64, error) {
+	a := &Base64{}
+	err := a.Init(indexes, elts)
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// NewEmpty64 creates an empty Base64 with element of type of "v".
+// If v is a pointer, the value type it points to is used.
+//
+// Since 0.5.13
+func NewEmpty64(v interface{}) (*Base64, error) {
+	m, err := encode.NewTypeEncoder(v)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &Base64{}
+	a.EltEncoder = m
+	return a, nil
+}
+
+// Marshal serializes the underlying Array64 to protobuf bytes.
+//
+// Since 0.5.13
+func (a *Base64) Marshal() ([]byte, error) {
+	return proto.Marshal(&a.Array64)
+}
+
+// Unmarshal deserializes protobuf bytes into the underlying Array64.
+//
+// Since 0.5.13
+func (a *Base64) Unmarshal(buf []byte) error {
+	return proto.Unmarshal(buf, &a.Array64)
+}
+
+// WriteTo writes the underlying Array64 to w field by field.
+// The output is the same as Marshal, but the entire marshaled bytes are never
+// built in memory.
+//
+// It implements io.WriterTo.
+//
+// Since 0.5.13
+func (a *Base64) WriteTo(w io.Writer) (int64, error) {
+	return pbstream.Write(w, &a.Array64, nil)
+}
+
+// ReadFrom reads the underlying Array64 from r until EOF, record by record.
+//
+// It implements io.ReaderFrom.
+//
+// Since 0.5.13
+func (a *Base64) ReadFrom(r io.Reader) (int64, error) {
+	a.Array64.Reset()
+	return pbstream.Read(r, -1, &a.Array64)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+// It is the same as Marshal.
+//
+// Since 0.5.13
+func (a *Base64) MarshalBinary() ([]byte, error) {
+	return a.Marshal()
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+// It is the same as Unmarshal.
+//
+// Since 0.5.13
+func (a *Base64) UnmarshalBinary(data []byte) error {
+	return a.Unmarshal(data)
+}
+
+// bmBit64 calculates bitamp word index and the bit index in the word.
+func bmBit64(idx int64) (int64, uint) {
+	return idx >> bmShift, uint(idx & int64(bmMask))
+}
+
+// InitIndex initializes index bitmap for an array.
+// Index must be an ascending int64 slice, otherwise, it return
+// the ErrIndexNotAscending error
+//
+// If most of the bitmap words are empty, only non-empty words are stored and
+// a.WordIndexes records the position of every word.
+//
+// Since 0.5.13
+func (a *Base64) InitIndex(index []int64) error {
+
+	for i := 0; i < len(index)-1; i++ {
+		if index[i] >= index[i+1] {
+			return ErrIndexNotAscending
+		}
+	}
+
+	if len(index) > 0 && index[0] < 0 {
+		return ErrIndexNegative
+	}
+
+	a.Cnt = int64(len(index))
+	a.Bitmaps = []uint64{}
+	a.Offsets = []int64{}
+	a.WordIndexes = nil
+
+	if len(index) == 0 {
+		return nil
+	}
+
+	wordIndexes := make([]int64, 0)
+	for _, idx := range index {
+		iBm, iBit := bmBit64(idx)
+		if len(wordIndexes) == 0 || wordIndexes[len(wordIndexes)-1] != iBm {
+			wordIndexes = append(wordIndexes, iBm)
+			a.Bitmaps = append(a.Bitmaps, 0)
+		}
+		a.Bitmaps[len(a.Bitmaps)-1] |= 1 << iBit
+	}
+
+	nWords := wordIndexes[len(wordIndexes)-1] + 1
+
+	// A sparse word costs Bitmaps, Offsets and WordIndexes: 3 int64.
+	// A dense word costs Bitmaps and Offsets: 2 int64.
+	if 3*int64(len(wordIndexes)) < 2*nWords {
+		a.WordIndexes = wordIndexes
+	} else {
+		dense := make([]uint64, nWords)
+		for i, iBm := range wordIndexes {
+			dense[iBm] = a.Bitmaps[i]
+		}
+		a.Bitmaps = dense
+	}
+
+	// Same as Base.InitIndex: the offset of an empty word is 0.
+	a.Offsets = make([]int64, len(a.Bitmaps))
+	cnt := int64(0)
+	for i, word := range a.Bitmaps {
+		if word != 0 {
+			a.Offsets[i] = cnt
+		}
+		cnt += int64(bits.OnesCount64(word))
+	}
+
+	return nil
+}
+
+// wordOf returns the position in a.Bitmaps of the word containing "idx".
+// It returns -1 if there is no such word.
+func (a *Base64) wordOf(idx int64) int64 {
+
+	iBm, _ := bmBit64(idx)
+	if idx < 0 {
+		return -1
+	}
+
+	if len(a.WordIndexes) == 0 {
+		if iBm >= int64(len(a.Bitmaps)) {
+			return -1
+		}
+		return iBm
+	}
+
+	wis := a.WordIndexes
+	i := sort.Search(len(wis), func(i int) bool { return wis[i] >= iBm })
+	if i == len(wis) || wis[i] != iBm {
+		return -1
+	}
+	return int64(i)
+}
+
+// Init initializes an array from the "indexes" and "elts".
+// The indexes must be an ascending int64 slice,
+// otherwise, return the ErrIndexNotAscending error.
+// The "elts" is a slice.
+//
+// By default it encodes an element with binary.Write(), in binary.LittenEndian.
+//
+// If a.EltEncoder is set before Init, elements can be var-length, such as
+// those encoded by encode.UVarint or encode.String16.
+//
+// Since 0.5.13
+func (a *Base64) Init(indexes []int64, elts interface{}) error {
+
+	rElts := reflect.ValueOf(elts)
+	if rElts.Kind() != reflect.Slice {
+		panic("elts is not a slice")
+	}
+
+	n := rElts.Len()
+	if len(indexes) != n {
+		return ErrIndexLen
+	}
+
+	err := a.InitIndex(indexes)
+	if err != nil {
+		return err
+	}
+
+	if len(indexes) == 0 {
+		return nil
+	}
+
+	if a.EltEncoder == nil {
+		a.EltEncoder, err = encode.NewTypeEncoderEndian(rElts.Index(0).Interface(), endian)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = a.InitElts(elts, a.EltEncoder)
+	if err != nil {
+		return errors.Wrapf(err, "failure Init Array64")
+	}
+
+	return nil
+}
+
+// InitElts initialized a.Elts, by encoding elements in to bytes.
+//
+// Since 0.5.13
+func (a *Base64) InitElts(elts interface{}, encoder encode.Encoder) (int, error) {
+
+	rElts := reflect.ValueOf(elts)
+	n := rElts.Len()
+	eltsize := fixedSizeOf(encoder)
+
+	b := make([]byte, 0)
+	if eltsize > 0 {
+		b = make([]byte, 0, eltsize*n)
+	}
+
+	offsets := make([]int64, 0, n+1)
+	isFixed := eltsize >= 0
+	for i := 0; i < n; i++ {
+		offsets = append(offsets, int64(len(b)))
+		ee := rElts.Index(i).Interface()
+		b = encode.AppendEncode(encoder, b, ee)
+		if len(b)-int(offsets[i]) != eltsize {
+			isFixed = false
+		}
+	}
+	offsets = append(offsets, int64(len(b)))
+	a.Elts = b
+
+	if isFixed {
+		a.EltOffsets = nil
+	} else {
+		a.EltOffsets = offsets
+	}
+
+	return n, nil
+}
+
+// eltSize returns the fixed element size, or 0 if elements are var-length.
+func (a *Base64) eltSize() int {
+	if len(a.EltOffsets) > 0 {
+		return 0
+	}
+	return a.EltEncoder.GetEncodedSize(nil)
+}
+
+// Has returns true if there is an element at "idx".
+//
+// Since 0.5.13
+func (a *Base64) Has(idx int64) bool {
+	w := a.wordOf(idx)
+	if w == -1 {
+		return false
+	}
+	_, iBit := bmBit64(idx)
+	return a.Bitmaps[w]>>iBit&1 == 1
+}
+
+// Get retrieves the value at "idx" and return it.
+// If this array has a value at "idx" it returns the value and "true",
+// otherwise it returns "nil" and "false".
+//
+// Since 0.5.13
+func (a *Base64) Get(idx int64) (interface{}, bool) {
+
+	bs, ok := a.GetBytes(idx, a.eltSize())
+	if ok {
+		_, v := a.EltEncoder.Decode(bs)
+		return v, true
+	}
+
+	return nil, false
+}
+
+// GetInto retrieves the value at "idx" and stores it into "dst", which is a
+// pointer to the element type.
+// It returns true if this array has a value at "idx", otherwise "dst" is not
+// changed.
+//
+// Since 0.5.13
+func (a *Base64) GetInto(idx int64, dst interface{}) bool {
+
+	bs, ok := a.GetBytes(idx, a.eltSize())
+	if ok {
+		encode.DecodeInto(a.EltEncoder, bs, dst)
+		return true
+	}
+
+	return false
+}
+
+// GetBytes retrieves the raw data of value in []byte at "idx" and return it.
+//
+// # Performance note
+//
+// Involves 3 memory access:
+//
+//	a.Bitmaps
+//	a.Offsets
+//	a.Elts
+//
+// With sparse words, locating the word requires a binary search in
+// a.WordIndexes.
+//
+// # Involves 0 alloc
+//
+// If elements are var-length, i.e., a.EltOffsets is not empty, "eltsize" is
+// ignored.
+//
+// Since 0.5.13
+func (a *Base64) GetBytes(idx int64, eltsize int) ([]byte, bool) {
+
+	w := a.wordOf(idx)
+	if w == -1 {
+		return nil, false
+	}
+
+	_, iBit := bmBit64(idx)
+	n := a.Bitmaps[w]
+	if n>>iBit&1 == 0 {
+		return nil, false
+	}
+
+	r := a.Offsets[w] + int64(bits.OnesCount64(n&(1<<iBit-1)))
+
+	if len(a.EltOffsets) > 0 {
+		return a.Elts[a.EltOffsets[r]:a.EltOffsets[r+1]], true
+	}
+
+	stIdx := int64(eltsize) * r
+	return a.Elts[stIdx : stIdx+int64(eltsize)], true
+}
+
+// Range calls fn for every present element in ascending index order,
+// with the index and the raw bytes of the element.
+// The iteration stops if fn returns false.
+//
+// Since 0.5.13
+func (a *Base64) Range(fn func(idx int64, b []byte) bool) {
+
+	eltsize := int64(0)
+	if len(a.EltOffsets) == 0 {
+		eltsize = int64(a.EltEncoder.GetEncodedSize(nil))
+	}
+	r := int64(0)
+
+	for i, word := range a.Bitmaps {
+
+		iBm := int64(i)
+		if len(a.WordIndexes) > 0 {
+			iBm = a.WordIndexes[i]
+		}
+
+		for word != 0 {
+			iBit := int64(bits.TrailingZeros64(word))
+			word &= word - 1
+
+			var b []byte
+			if len(a.EltOffsets) > 0 {
+				b = a.Elts[a.EltOffsets[r]:a.EltOffsets[r+1]]
+			} else {
+				stIdx := eltsize * r
+				b = a.Elts[stIdx : stIdx+eltsize]
+			}
+
+			if !fn(iBm<<bmShift+iBit, b) {
+				return
+			}
+			r++
+		}
+	}
+}
@@ -0,0 +1,333 @@
+package array_test
+
+import (
+	"bytes"
+	"testing"
+
+	proto "github.com/golang/protobuf/proto"
+	"github.com/openacid/slim/array"
+	"github.com/openacid/slim/encode"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBase64_New(t *testing.T) {
+
+	ta := require.New(t)
+
+	indexes := []int64{1, 5, 9, 203, 1<<31 + 3}
+	elts := []uint32{12, 15, 19, 120, 300}
+
+	a, err := array.New64(indexes, elts)
+	ta.NoError(err)
+	ta.Equal(int64(len(indexes)), a.Cnt)
+
+	for i, idx := range indexes {
+		v, found := a.Get(idx)
+		ta.True(found, "Get(%d)", idx)
+		ta.Equal(elts[i], v, "Get(%d)", idx)
+
+		ta.True(a.Has(idx), "Has(%d)", idx)
+		ta.False(a.Has(idx+1), "Has(%d+1)", idx)
+
+		v, found = a.Get(idx + 1)
+		ta.False(found, "Get(%d+1)", idx)
+		ta.Nil(v, "Get(%d+1)", idx)
+	}
+
+	ta.False(a.Has(-1))
+	ta.False(a.Has(1 << 40))
+}
+
+func TestBase64_sparse(t *testing.T) {
+
+	ta := require.New(t)
+
+	// dense
+
+	a, err := array.New64([]int64{1, 64, 129}, []uint16{1, 2, 3})
+	ta.NoError(err)
+	ta.Nil(a.WordIndexes)
+	ta.Equal([]uint64{2, 1, 2}, a.Bitmaps)
+
+	// sparse
+
+	indexes := []int64{1, 3, 1 << 40, 1<<40 + 65, 1 << 62}
+	elts := []uint16{1, 2, 3, 4, 5}
+
+	a, err = array.New64(indexes, elts)
+	ta.NoError(err)
+	ta.Equal([]int64{0, 1 << 34, 1<<34 + 1, 1 << 56}, a.WordIndexes)
+	ta.Equal([]uint64{0x0a, 1, 2, 1}, a.Bitmaps)
+	ta.Equal([]int64{0, 2, 3, 4}, a.Offsets)
+
+	for i, idx := range indexes {
+		v, found := a.Get(idx)
+		ta.True(found, "Get(%d)", idx)
+		ta.Equal(elts[i], v, "Get(%d)", idx)
+
+		_, found = a.Get(idx + 1)
+		ta.False(found, "Get(%d+1)", idx)
+	}
+
+	_, found := a.Get(1 << 50)
+	ta.False(found)
+
+	got := []int64{}
+	a.Range(func(idx int64, b []byte) bool {
+		got = append(got, idx)
+		return true
+	})
+	ta.Equal(indexes, got)
+}
+
+func TestBase64_New_error(t *testing.T) {
+
+	ta := require.New(t)
+
+	_, err := array.New64([]int64{1, 5}, []uint32{1})
+	ta.Equal(array.ErrIndexLen, err)
+
+	_, err = array.New64([]int64{5, 5}, []uint32{1, 2})
+	ta.Equal(array.ErrIndexNotAscending, err)
+
+	_, err = array.New64([]int64{-1, 5}, []uint32{1, 2})
+	ta.Equal(array.ErrIndexNegative, err)
+}
+
+func TestBase64_Range(t *testing.T) {
+
+	ta := require.New(t)
+
+	indexes := []int64{0, 63, 64, 1000, 1 << 33}
+	elts := []uint16{1, 2, 3, 4, 5}
+
+	a, err := array.New64(indexes, elts)
+	ta.NoError(err)
+
+	gotIdx := []int64{}
+	gotElts := []uint16{}
+	a.Range(func(idx int64, b []byte) bool {
+		_, v := a.EltEncoder.Decode(b)
+		gotIdx = append(gotIdx, idx)
+		gotElts = append(gotElts, v.(uint16))
+		return true
+	})
+
+	ta.Equal(indexes, gotIdx)
+	ta.Equal(elts, gotElts)
+
+	// stop early
+
+	n := 0
+	a.Range(func(idx int64, b []byte) bool {
+		n++
+		return n < 2
+	})
+	ta.Equal(2, n)
+}
+
+func TestBase64_MarshalUnmarshal(t *testing.T) {
+
+	ta := require.New(t)
+
+	indexes := []int64{1, 5, 9, 203, 1 << 45}
+	elts := []uint64{12, 15, 19, 120, 300}
+
+	a, err := array.New64(indexes, elts)
+	ta.NoError(err)
+
+	buf, err := a.Marshal()
+	ta.NoError(err)
+
+	b, err := array.NewEmpty64(uint64(0))
+	ta.NoError(err)
+
+	err = b.Unmarshal(buf)
+	ta.NoError(err)
+
+	for i, idx := range indexes {
+		v, found := b.Get(idx)
+		ta.True(found)
+		ta.Equal(elts[i], v)
+	}
+}
+
+func TestBase64_WriteToReadFrom(t *testing.T) {
+
+	ta := require.New(t)
+
+	indexes := []int64{1, 5, 9, 203, 1 << 45}
+	elts := []uint64{12, 15, 19, 120, 300}
+
+	a, err := array.New64(indexes, elts)
+	ta.NoError(err)
+
+	want, err := a.Marshal()
+	ta.NoError(err)
+
+	w := bytes.NewBuffer(nil)
+	n, err := a.WriteTo(w)
+	ta.NoError(err)
+	ta.Equal(int64(len(want)), n)
+	ta.Equal(want, w.Bytes())
+
+	b, err := array.NewEmpty64(uint64(0))
+	ta.NoError(err)
+
+	n, err = b.ReadFrom(bytes.NewReader(want))
+	ta.NoError(err)
+	ta.Equal(int64(len(want)), n)
+
+	for i, idx := range indexes {
+		v, found := b.Get(idx)
+		ta.True(found)
+		ta.Equal(elts[i], v)
+	}
+
+	// binary marshaler
+
+	bin, err := a.MarshalBinary()
+	ta.NoError(err)
+	ta.Equal(want, bin)
+
+	c, err := array.NewEmpty64(uint64(0))
+	ta.NoError(err)
+	err = c.UnmarshalBinary(bin)
+	ta.NoError(err)
+	ta.Equal(b.Array64.WordIndexes, c.Array64.WordIndexes)
+}
+
+func TestBase64_loadArray32(t *testing.T) {
+
+	ta := require.New(t)
+
+	indexes := []int32{1, 5, 9, 203, 400}
+	elts := []uint32{12, 15, 19, 120, 300}
+
+	a32, err := array.NewU32(indexes, elts)
+	ta.NoError(err)
+
+	buf, err := proto.Marshal(a32)
+	ta.NoError(err)
+
+	a64 := &array.Base64{EltEncoder: encode.U32{}}
+	err = a64.Unmarshal(buf)
+	ta.NoError(err)
+
+	ta.Equal(int64(a32.Cnt), a64.Cnt)
+
+	for i, idx := range indexes {
+		v, found := a64.Get(int64(idx))
+		ta.True(found)
+		ta.Equal(elts[i], v)
+
+		_, found = a64.Get(int64(idx) + 1)
+		ta.False(found)
+	}
+}
+
+func TestBase64_varLength(t *testing.T) {
+
+	ta := require.New(t)
+
+	indexes := []int64{1, 5, 64, 1 << 40}
+	elts := []string{"a", "", "foo", "barbaz"}
+
+	a := &array.Base64{EltEncoder: encode.StringVarint{}}
+	err := a.Init(indexes, elts)
+	ta.NoError(err)
+	ta.Equal([]int64{0, 2, 3, 7, 14}, a.EltOffsets)
+
+	check := func(a *array.Base64) {
+		for i, idx := range indexes {
+			v, found := a.Get(idx)
+			ta.True(found)
+			ta.Equal(elts[i], v)
+
+			s := ""
+			ta.True(a.GetInto(idx, &s))
+			ta.Equal(elts[i], s)
+		}
+
+		_, found := a.Get(2)
+		ta.False(found)
+
+		got := []string{}
+		a.Range(func(idx int64, b []byte) bool {
+			_, v := a.EltEncoder.Decode(b)
+			got = append(got, v.(string))
+			return true
+		})
+		ta.Equal(elts, got)
+	}
+
+	check(a)
+
+	buf, err := a.Marshal()
+	ta.NoError(err)
+
+	b := &array.Base64{EltEncoder: encode.StringVarint{}}
+	ta.NoError(b.Unmarshal(buf))
+	check(b)
+
+	w := bytes.NewBuffer(nil)
+	_, err = a.WriteTo(w)
+	ta.NoError(err)
+	ta.Equal(buf, w.Bytes())
+}
+
+func TestBase64_loadArray32_varLength(t *testing.T) {
+
+	ta := require.New(t)
+
+	indexes := []int32{1, 5, 9, 203, 400}
+	elts := []uint64{1, 300, 0, 1 << 40, 5}
+
+	a32 := &array.Array{}
+	a32.EltEncoder = encode.UVarint{}
+	err := a32.Init(indexes, elts)
+	ta.NoError(err)
+	ta.NotEmpty(a32.EltOffsets)
+
+	buf, err := proto.Marshal(a32)
+	ta.NoError(err)
+
+	a64 := &array.Base64{EltEncoder: encode.UVarint{}}
+	err = a64.Unmarshal(buf)
+	ta.NoError(err)
+
+	for i, idx := range indexes {
+		v, found := a64.Get(int64(idx))
+		ta.True(found)
+		ta.Equal(elts[i], v)
+	}
+}
+
+func BenchmarkBase64_GetBytes(b *testing.B) {
+	a, err := array.New64([]int64{1, 2, 3}, []uint32{1, 2, 3})
+	if err != nil {
+		panic(err)
+	}
+
+	s := 0
+	for i := 0; i < b.N; i++ {
+		v, _ := a.GetBytes(int64(Input), 4)
+		s += int(v[0])
+	}
+	Output = int64(s)
+}
+
+func TestBase64_GetInto(t *testing.T) {
+
+	ta := require.New(t)
+
+	a, err := array.New64([]int64{1, 1 << 40}, []uint32{12, 15})
+	ta.NoError(err)
+
+	v := new(uint32)
+	ta.True(a.GetInto(1<<40, v))
+	ta.Equal(uint32(15), *v)
+
+	ta.False(a.GetInto(2, v))
+	ta.Equal(uint32(15), *v)
+}
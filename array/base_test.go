@@ -1,6 +1,8 @@
 package array_test
 
 import (
+	"bytes"
+	"encoding/gob"
 	"math/rand"
 	"reflect"
 	"testing"
@@ -242,6 +244,26 @@ func TestBaseInitWithEncoder(t *testing.T) {
 	if !reflect.DeepEqual(wantelts, ab.Elts) {
 		t.Fatalf("not equal %v", pretty.Diff(wantelts, ab.Elts))
 	}
+
+	// intEncoder does not implement encode.FixedSizer, but it is fixed-size.
+	if ab.EltOffsets != nil {
+		t.Fatalf("expected no EltOffsets but: %v", ab.EltOffsets)
+	}
+
+	v, found := ab.Get(2)
+	if !found || v != 4 {
+		t.Fatalf("expected 4 but: %v %v", v, found)
+	}
+
+	ab64 := &array.Base64{}
+	ab64.EltEncoder = intEncoder{}
+	err = ab64.Init([]int64{1, 2}, []int{3, 4})
+	if err != nil {
+		t.Fatalf("expected no error but: %#v", err)
+	}
+	if ab64.EltOffsets != nil {
+		t.Fatalf("expected no EltOffsets but: %v", ab64.EltOffsets)
+	}
 }
 
 func TestBase_Get(t *testing.T) {
@@ -271,6 +293,101 @@ func TestBase_Get(t *testing.T) {
 	}
 }
 
+func TestBase_WriteToReadFrom(t *testing.T) {
+
+	ta := require.New(t)
+
+	indexes := []int32{1, 3, 100, 1000}
+
+	cases := []struct {
+		name    string
+		encoder encode.Encoder
+		elts    interface{}
+	}{
+		{"fixed-size", encode.U16{}, []uint16{1, 3, 100, 1000}},
+		{"var-len", encode.String16{}, []string{"a", "", "bcd", "efghijk"}},
+	}
+
+	for _, c := range cases {
+
+		a := &array.Base{EltEncoder: c.encoder}
+		err := a.Init(indexes, c.elts)
+		ta.NoError(err, c.name)
+
+		want, err := a.Marshal()
+		ta.NoError(err, c.name)
+
+		w := bytes.NewBuffer(nil)
+		n, err := a.WriteTo(w)
+		ta.NoError(err, c.name)
+		ta.Equal(int64(len(want)), n, c.name)
+		ta.Equal(want, w.Bytes(), c.name)
+
+		// ReadFrom resets existing content
+		b := &array.Base{EltEncoder: c.encoder}
+		err = b.Init([]int32{5}, sliceHead(c.elts))
+		ta.NoError(err, c.name)
+
+		n, err = b.ReadFrom(bytes.NewReader(want))
+		ta.NoError(err, c.name)
+		ta.Equal(int64(len(want)), n, c.name)
+
+		ta.Equal(a.Array32.Bitmaps, b.Array32.Bitmaps, c.name)
+		ta.Equal(a.Array32.Elts, b.Array32.Elts, c.name)
+		ta.Equal(a.Array32.EltOffsets, b.Array32.EltOffsets, c.name)
+
+		_, found := b.Get(5)
+		ta.False(found, c.name)
+
+		// truncated
+		b = &array.Base{EltEncoder: c.encoder}
+		_, err = b.ReadFrom(bytes.NewReader(want[:len(want)-1]))
+		ta.Error(err, c.name)
+	}
+}
+
+// sliceHead returns a slice with only the first element of elts.
+func sliceHead(elts interface{}) interface{} {
+	switch v := elts.(type) {
+	case []uint16:
+		return v[:1]
+	case []string:
+		return v[:1]
+	}
+	panic("unknown type")
+}
+
+func TestBase_gob(t *testing.T) {
+
+	ta := require.New(t)
+
+	indexes := []int32{1, 3, 100}
+	elts := []uint16{1, 3, 100}
+
+	type doc struct {
+		Name string
+		Arr  *array.U16
+	}
+
+	a, err := array.NewU16(indexes, elts)
+	ta.NoError(err)
+
+	buf := bytes.NewBuffer(nil)
+	err = gob.NewEncoder(buf).Encode(&doc{Name: "foo", Arr: a})
+	ta.NoError(err)
+
+	got := &doc{}
+	err = gob.NewDecoder(buf).Decode(got)
+	ta.NoError(err)
+
+	ta.Equal("foo", got.Name)
+	for i, idx := range indexes {
+		v, found := got.Arr.Get(idx)
+		ta.True(found)
+		ta.Equal(elts[i], v)
+	}
+}
+
 var OutputBool bool
 
 func BenchmarkBaseGet(b *testing.B) {
@@ -0,0 +1,95 @@
+package array
+
+import (
+	"math/bits"
+)
+
+// Bool is an implementation of Base with bool element.
+// Elements are bit-packed: every element takes 1 bit in Elts.
+// Thus EltWidth is 1 and Elts is not compatible with other fixed-size arrays.
+//
+// It is not generated like other fixed-size arrays, and it has no
+// EltEncoder: Base.GetBytes does not apply to a bit-packed element.
+//
+// Since 0.5.13
+type Bool struct {
+	Base
+}
+
+// NewBool creates a Bool
+//
+// Since 0.5.13
+func NewBool(index []int32, elts []bool) (a *Bool, err error) {
+	a = &Bool{}
+	err = a.Init(index, elts)
+	if err != nil {
+		a = nil
+	}
+	return a, err
+}
+
+// Init initializes a Bool from the "indexes" and "elts".
+// The indexes must be an ascending int32 slice,
+// otherwise, return the ErrIndexNotAscending error.
+//
+// Since 0.5.13
+func (a *Bool) Init(indexes []int32, elts []bool) error {
+
+	if len(indexes) != len(elts) {
+		return ErrIndexLen
+	}
+
+	err := a.InitIndex(indexes)
+	if err != nil {
+		return err
+	}
+
+	a.EltWidth = 1
+	a.Elts = make([]byte, (len(elts)+7)>>3)
+	for i, v := range elts {
+		if v {
+			a.Elts[i>>3] |= 1 << uint(i&7)
+		}
+	}
+
+	return nil
+}
+
+// Get returns value at "idx" and a bool indicating if the value is
+// found.
+//
+// Since 0.5.13
+func (a *Bool) Get(idx int32) (bool, bool) {
+
+	iBm, iBit := bmBit(idx)
+
+	var n = a.Bitmaps[iBm]
+
+	if ((n >> uint(iBit)) & 1) == 0 {
+		return false, false
+	}
+
+	cnt1 := bits.OnesCount64(n & ((uint64(1) << uint(iBit)) - 1))
+
+	r := a.Offsets[iBm] + int32(cnt1)
+
+	return (a.Elts[r>>3]>>uint(r&7))&1 == 1, true
+}
+
+// GetInto retrieves the value at "idx" and stores it into "dst", which must be
+// a *bool.
+// It returns true if this array has a value at "idx", otherwise "dst" is not
+// changed.
+//
+// It overrides Base.GetInto, which requires an EltEncoder.
+//
+// Since 0.5.13
+func (a *Bool) GetInto(idx int32, dst interface{}) bool {
+
+	v, found := a.Get(idx)
+	if found {
+		*dst.(*bool) = v
+	}
+
+	return found
+}
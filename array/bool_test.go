@@ -0,0 +1,130 @@
+package array_test
+
+import (
+	"testing"
+
+	proto "github.com/golang/protobuf/proto"
+	"github.com/openacid/slim/array"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBool_New(t *testing.T) {
+
+	ta := require.New(t)
+
+	_, err := array.NewBool([]int32{1, 5}, []bool{true})
+	ta.Equal(array.ErrIndexLen, err)
+
+	_, err = array.NewBool([]int32{5, 5}, []bool{true, false})
+	ta.Equal(array.ErrIndexNotAscending, err)
+
+	a, err := array.NewBool([]int32{}, []bool{})
+	ta.NoError(err)
+	ta.Equal(int32(0), a.Cnt)
+	ta.Equal([]byte{}, a.Elts)
+}
+
+func TestBool_Get(t *testing.T) {
+
+	ta := require.New(t)
+
+	index := []int32{0, 1, 5, 9, 63, 64, 203, 400, 401, 402}
+	elts := []bool{true, false, true, true, false, true, false, false, true, true}
+
+	a, err := array.NewBool(index, elts)
+	ta.NoError(err)
+	ta.Equal(int32(len(index)), a.Cnt)
+	ta.Equal(int32(1), a.EltWidth)
+
+	// 10 elts are packed into 2 bytes
+	ta.Equal([]byte{0x2d, 0x03}, a.Elts)
+
+	for i, idx := range index {
+		v, found := a.Get(idx)
+		ta.True(found, "Get(%d)", idx)
+		ta.Equal(elts[i], v, "Get(%d)", idx)
+	}
+
+	for _, idx := range []int32{2, 3, 62, 65, 399} {
+		v, found := a.Get(idx)
+		ta.False(found, "Get(%d)", idx)
+		ta.False(v, "Get(%d)", idx)
+	}
+}
+
+func TestBool_GetInto(t *testing.T) {
+
+	ta := require.New(t)
+
+	index := []int32{0, 1, 5, 9, 63, 64, 203}
+	elts := []bool{true, false, true, true, false, true, false}
+
+	a, err := array.NewBool(index, elts)
+	ta.NoError(err)
+
+	for i, idx := range index {
+		v := !elts[i]
+		found := a.GetInto(idx, &v)
+		ta.True(found, "GetInto(%d)", idx)
+		ta.Equal(elts[i], v, "GetInto(%d)", idx)
+	}
+
+	// not found: dst is not changed
+
+	for _, idx := range []int32{2, 62, 65, 204} {
+		v := true
+		found := a.GetInto(idx, &v)
+		ta.False(found, "GetInto(%d)", idx)
+		ta.True(v, "GetInto(%d)", idx)
+	}
+
+	// through an interface as arrays of other types are used
+
+	var g interface {
+		GetInto(int32, interface{}) bool
+	} = a
+
+	v := false
+	ta.True(g.GetInto(5, &v))
+	ta.True(v)
+}
+
+func TestBool_MarshalUnmarshal(t *testing.T) {
+
+	ta := require.New(t)
+
+	index := []int32{1, 5, 9, 203, 400}
+	elts := []bool{true, false, true, false, true}
+
+	a, err := array.NewBool(index, elts)
+	ta.NoError(err)
+
+	buf, err := proto.Marshal(a)
+	ta.NoError(err)
+
+	b := &array.Bool{}
+	err = proto.Unmarshal(buf, b)
+	ta.NoError(err)
+
+	for i, idx := range index {
+		v, found := b.Get(idx)
+		ta.True(found)
+		ta.Equal(elts[i], v)
+	}
+}
+
+func BenchmarkBool_Get(b *testing.B) {
+	a, err := array.NewBool([]int32{1, 2, 3}, []bool{true, false, true})
+	if err != nil {
+		panic(err)
+	}
+
+	s := 0
+	for i := 0; i < b.N; i++ {
+		v, _ := a.Get(Input)
+		if v {
+			s++
+		}
+	}
+	Output = int64(s)
+}
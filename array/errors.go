@@ -16,4 +16,10 @@ var (
 	//
 	// Since 0.2.0
 	ErrIndexLen = errors.New("the length of indexes and elts must be equal")
+
+	// ErrIndexNegative indicates that there is a negative index when
+	// initializing an Array64.
+	//
+	// Since 0.5.13
+	ErrIndexNegative = errors.New("index must not be negative")
 )
@@ -0,0 +1,88 @@
+// Code generated 'by go generate ./...'; DO NOT EDIT.
+
+package array
+
+import (
+	"math"
+	"math/bits"
+)
+
+// F32 is an implementation of Base with float32 element
+//
+// Since 0.5.13
+type F32 struct {
+	Base
+}
+
+// NewF32 creates a F32
+//
+// Since 0.5.13
+func NewF32(index []int32, elts []float32) (a *F32, err error) {
+	a = &F32{}
+	err = a.Init(index, elts)
+	if err != nil {
+		a = nil
+	}
+	return a, err
+}
+
+// Get returns value at "idx" and a bool indicating if the value is
+// found.
+//
+// Since 0.5.13
+func (a *F32) Get(idx int32) (float32, bool) {
+
+	iBm, iBit := bmBit(idx)
+
+	var n = a.Bitmaps[iBm]
+
+	if ((n >> uint(iBit)) & 1) == 0 {
+		return 0, false
+	}
+
+	cnt1 := bits.OnesCount64(n & ((uint64(1) << uint(iBit)) - 1))
+
+	stIdx := a.Offsets[iBm]*4 + int32(cnt1)*4
+
+	return math.Float32frombits(endian.Uint32(a.Elts[stIdx:])), true
+}
+
+// F64 is an implementation of Base with float64 element
+//
+// Since 0.5.13
+type F64 struct {
+	Base
+}
+
+// NewF64 creates a F64
+//
+// Since 0.5.13
+func NewF64(index []int32, elts []float64) (a *F64, err error) {
+	a = &F64{}
+	err = a.Init(index, elts)
+	if err != nil {
+		a = nil
+	}
+	return a, err
+}
+
+// Get returns value at "idx" and a bool indicating if the value is
+// found.
+//
+// Since 0.5.13
+func (a *F64) Get(idx int32) (float64, bool) {
+
+	iBm, iBit := bmBit(idx)
+
+	var n = a.Bitmaps[iBm]
+
+	if ((n >> uint(iBit)) & 1) == 0 {
+		return 0, false
+	}
+
+	cnt1 := bits.OnesCount64(n & ((uint64(1) << uint(iBit)) - 1))
+
+	stIdx := a.Offsets[iBm]*8 + int32(cnt1)*8
+
+	return math.Float64frombits(endian.Uint64(a.Elts[stIdx:])), true
+}
@@ -0,0 +1,490 @@
+// Code generated 'by go generate ./...'; DO NOT EDIT.
+
+package array_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+
+	proto "github.com/golang/protobuf/proto"
+	"github.com/kr/pretty"
+	"github.com/openacid/slim/array"
+)
+
+func TestF32NewErrorArgments(t *testing.T) {
+	var index []int32
+	eltsData := []float32{12, 15, 19, 120, 300}
+
+	var err error
+
+	index = []int32{1, 5, 9, 203}
+	_, err = array.NewF32(index, eltsData)
+	if err == nil {
+		t.Fatalf("new with wrong index length must error")
+	}
+
+	index = []int32{1, 5, 5, 203, 400}
+	_, err = array.NewF32(index, eltsData)
+	if err == nil {
+		t.Fatalf("new with unsorted index must error")
+	}
+}
+
+func TestF32New(t *testing.T) {
+	var cases = []struct {
+		index    []int32
+		eltsData []float32
+	}{
+		{
+			[]int32{}, []float32{},
+		},
+		{
+			[]int32{0, 5, 9, 203, 400}, []float32{12, 15, 19, 120, 300},
+		},
+	}
+
+	for _, c := range cases {
+		index, eltsData := c.index, c.eltsData
+		cnt := int32(len(index))
+
+		a, err := array.NewF32(index, eltsData)
+		if err != nil {
+			t.Fatalf("failed new compacted array, err: %s", err)
+		}
+
+		if a.Cnt != cnt {
+			t.Fatalf("cnt is not equal expect: %d, act: %d", cnt, a.Cnt)
+		}
+
+		buf := new(bytes.Buffer)
+		_ = binary.Write(buf, binary.LittleEndian, eltsData)
+
+		expElts := buf.Bytes()
+		if expElts == nil {
+			expElts = []byte{}
+		}
+
+		if !reflect.DeepEqual(a.Elts, expElts) && len(a.Elts) != 0 && len(expElts) != 0 {
+			fmt.Println(pretty.Diff(a.Elts, expElts))
+			t.Fatalf("elts is not equal expect: %d, act: %d", expElts, a.Elts)
+		}
+	}
+
+}
+
+func TestF32Get(t *testing.T) {
+	index, eltsData := []int32{}, []float32{}
+	rnd := rand.New(rand.NewSource(time.Now().Unix()))
+
+	keysMap := map[int32]bool{}
+	num, idx, cnt := int32(0), int32(0), int32(1024)
+	for {
+		if rnd.Intn(2) == 1 {
+			index = append(index, idx)
+			eltsData = append(eltsData, float32(rnd.Uint64()))
+			num++
+			keysMap[idx] = true
+		}
+		idx++
+		if num == cnt {
+			break
+		}
+	}
+
+	a, err := array.NewF32(index, eltsData)
+	if err != nil {
+		t.Fatalf("failed new compacted array, err: %s", err)
+	}
+
+	dataIdx := int32(0)
+	for ii := int32(0); ii < idx; ii++ {
+
+		actByte, found := a.Get(ii)
+		_, present := keysMap[ii]
+		if found != present {
+			t.Fatalf("Get i:%d present:%t but:%t", ii, present, found)
+		}
+
+		if found {
+			if eltsData[dataIdx] != actByte {
+				t.Fatalf("Get i:%d is not equal expect: %v, act: %v", ii, eltsData[dataIdx], actByte)
+			}
+		}
+
+		if _, ok := keysMap[ii]; ok {
+			dataIdx++
+		}
+	}
+}
+
+func TestF32EncodeDecode(t *testing.T) {
+
+	indexes := []int32{1, 5, 9, 203}
+	elts := []float32{12, 15, 19, 120}
+
+	cases := []struct {
+		n    int
+		want []byte
+	}{
+		{
+			0,
+			[]byte{},
+		},
+		{
+			1,
+			[]byte{8, 1, 18, 1, 2, 26, 1, 0, 34},
+		},
+		{
+			2,
+			[]byte{8, 2, 18, 1, 34, 26, 1, 0, 34},
+		},
+	}
+
+	for i, c := range cases {
+
+		a, err := array.NewF32(indexes[:c.n], elts[:c.n])
+		if err != nil {
+			t.Errorf("expect no error but: %s", err)
+		}
+
+		rst, err := proto.Marshal(a)
+		if err != nil {
+			t.Errorf("expect no error but: %s", err)
+		}
+
+		// build Elts part for template generated test codes
+		var want []byte = c.want
+		if c.n > 0 {
+			want = append(c.want, byte(c.n*4))
+			for i := 0; i < c.n; i++ {
+				b := make([]byte, 4)
+				binary.LittleEndian.PutUint32(b, math.Float32bits(elts[i]))
+				want = append(want, b...)
+			}
+		}
+
+		if !reflect.DeepEqual(rst, want) {
+			t.Fatalf("%d-th: n: %v; want: %v; actual: %v",
+				i+1, c.n, want, rst)
+		}
+
+		// Decode
+
+		b := &array.F32{}
+		err = proto.Unmarshal(rst, b)
+
+		if err != nil {
+			t.Errorf("expect no error but: %s", err)
+		}
+
+		if !reflect.DeepEqual(a.Elts, b.Elts) {
+			t.Fatalf("%d-th: n: %v; compare Elts: a: %v; b: %v",
+				i+1, c.n, a.Elts, b.Elts)
+		}
+
+		// protobuf handles empty structure specially.
+		if c.n == 0 {
+			continue
+		}
+
+		// ignore proto's field when compare
+		a.XXX_sizecache = 0
+
+		if !reflect.DeepEqual(a, b) {
+			t.Fatalf("%d-th: n: %v; compare a b: %v",
+				i+1, c.n, pretty.Diff(a, b))
+		}
+
+	}
+}
+
+func TestF32EncodeDecodeBig(t *testing.T) {
+
+	n := 102400
+	step := 2
+	indexes := []int32{}
+	elts := []float32{}
+
+	for i := 0; i < n; i += step {
+		indexes = append(indexes, int32(i))
+		elts = append(elts, float32(i))
+	}
+
+	a, err := array.NewF32(indexes, elts)
+	if err != nil {
+		t.Errorf("expect no error but: %s", err)
+	}
+
+	rst, err := proto.Marshal(a)
+	if err != nil {
+		t.Errorf("expect no error but: %s", err)
+	}
+
+	b := &array.F32{}
+	err = proto.Unmarshal(rst, b)
+	if err != nil {
+		t.Errorf("expect no error but: %s", err)
+	}
+
+	// proto pollute this field
+	a.XXX_sizecache = 0
+	if !reflect.DeepEqual(a, b) {
+		t.Fatalf("compare: a b: %v", pretty.Diff(a, b))
+	}
+}
+
+func BenchmarkF32Get(b *testing.B) {
+	a, err := array.NewF32([]int32{1, 2, 3}, []float32{1, 2, 3})
+	if err != nil {
+		panic(err)
+	}
+
+	s := float32(0)
+	for i := 0; i < b.N; i++ {
+		r, _ := a.Get(Input)
+		s += r
+	}
+	Output = int64(s)
+}
+
+func TestF64NewErrorArgments(t *testing.T) {
+	var index []int32
+	eltsData := []float64{12, 15, 19, 120, 300}
+
+	var err error
+
+	index = []int32{1, 5, 9, 203}
+	_, err = array.NewF64(index, eltsData)
+	if err == nil {
+		t.Fatalf("new with wrong index length must error")
+	}
+
+	index = []int32{1, 5, 5, 203, 400}
+	_, err = array.NewF64(index, eltsData)
+	if err == nil {
+		t.Fatalf("new with unsorted index must error")
+	}
+}
+
+func TestF64New(t *testing.T) {
+	var cases = []struct {
+		index    []int32
+		eltsData []float64
+	}{
+		{
+			[]int32{}, []float64{},
+		},
+		{
+			[]int32{0, 5, 9, 203, 400}, []float64{12, 15, 19, 120, 300},
+		},
+	}
+
+	for _, c := range cases {
+		index, eltsData := c.index, c.eltsData
+		cnt := int32(len(index))
+
+		a, err := array.NewF64(index, eltsData)
+		if err != nil {
+			t.Fatalf("failed new compacted array, err: %s", err)
+		}
+
+		if a.Cnt != cnt {
+			t.Fatalf("cnt is not equal expect: %d, act: %d", cnt, a.Cnt)
+		}
+
+		buf := new(bytes.Buffer)
+		_ = binary.Write(buf, binary.LittleEndian, eltsData)
+
+		expElts := buf.Bytes()
+		if expElts == nil {
+			expElts = []byte{}
+		}
+
+		if !reflect.DeepEqual(a.Elts, expElts) && len(a.Elts) != 0 && len(expElts) != 0 {
+			fmt.Println(pretty.Diff(a.Elts, expElts))
+			t.Fatalf("elts is not equal expect: %d, act: %d", expElts, a.Elts)
+		}
+	}
+
+}
+
+func TestF64Get(t *testing.T) {
+	index, eltsData := []int32{}, []float64{}
+	rnd := rand.New(rand.NewSource(time.Now().Unix()))
+
+	keysMap := map[int32]bool{}
+	num, idx, cnt := int32(0), int32(0), int32(1024)
+	for {
+		if rnd.Intn(2) == 1 {
+			index = append(index, idx)
+			eltsData = append(eltsData, float64(rnd.Uint64()))
+			num++
+			keysMap[idx] = true
+		}
+		idx++
+		if num == cnt {
+			break
+		}
+	}
+
+	a, err := array.NewF64(index, eltsData)
+	if err != nil {
+		t.Fatalf("failed new compacted array, err: %s", err)
+	}
+
+	dataIdx := int32(0)
+	for ii := int32(0); ii < idx; ii++ {
+
+		actByte, found := a.Get(ii)
+		_, present := keysMap[ii]
+		if found != present {
+			t.Fatalf("Get i:%d present:%t but:%t", ii, present, found)
+		}
+
+		if found {
+			if eltsData[dataIdx] != actByte {
+				t.Fatalf("Get i:%d is not equal expect: %v, act: %v", ii, eltsData[dataIdx], actByte)
+			}
+		}
+
+		if _, ok := keysMap[ii]; ok {
+			dataIdx++
+		}
+	}
+}
+
+func TestF64EncodeDecode(t *testing.T) {
+
+	indexes := []int32{1, 5, 9, 203}
+	elts := []float64{12, 15, 19, 120}
+
+	cases := []struct {
+		n    int
+		want []byte
+	}{
+		{
+			0,
+			[]byte{},
+		},
+		{
+			1,
+			[]byte{8, 1, 18, 1, 2, 26, 1, 0, 34},
+		},
+		{
+			2,
+			[]byte{8, 2, 18, 1, 34, 26, 1, 0, 34},
+		},
+	}
+
+	for i, c := range cases {
+
+		a, err := array.NewF64(indexes[:c.n], elts[:c.n])
+		if err != nil {
+			t.Errorf("expect no error but: %s", err)
+		}
+
+		rst, err := proto.Marshal(a)
+		if err != nil {
+			t.Errorf("expect no error but: %s", err)
+		}
+
+		// build Elts part for template generated test codes
+		var want []byte = c.want
+		if c.n > 0 {
+			want = append(c.want, byte(c.n*8))
+			for i := 0; i < c.n; i++ {
+				b := make([]byte, 8)
+				binary.LittleEndian.PutUint64(b, math.Float64bits(elts[i]))
+				want = append(want, b...)
+			}
+		}
+
+		if !reflect.DeepEqual(rst, want) {
+			t.Fatalf("%d-th: n: %v; want: %v; actual: %v",
+				i+1, c.n, want, rst)
+		}
+
+		// Decode
+
+		b := &array.F64{}
+		err = proto.Unmarshal(rst, b)
+
+		if err != nil {
+			t.Errorf("expect no error but: %s", err)
+		}
+
+		if !reflect.DeepEqual(a.Elts, b.Elts) {
+			t.Fatalf("%d-th: n: %v; compare Elts: a: %v; b: %v",
+				i+1, c.n, a.Elts, b.Elts)
+		}
+
+		// protobuf handles empty structure specially.
+		if c.n == 0 {
+			continue
+		}
+
+		// ignore proto's field when compare
+		a.XXX_sizecache = 0
+
+		if !reflect.DeepEqual(a, b) {
+			t.Fatalf("%d-th: n: %v; compare a b: %v",
+				i+1, c.n, pretty.Diff(a, b))
+		}
+
+	}
+}
+
+func TestF64EncodeDecodeBig(t *testing.T) {
+
+	n := 102400
+	step := 2
+	indexes := []int32{}
+	elts := []float64{}
+
+	for i := 0; i < n; i += step {
+		indexes = append(indexes, int32(i))
+		elts = append(elts, float64(i))
+	}
+
+	a, err := array.NewF64(indexes, elts)
+	if err != nil {
+		t.Errorf("expect no error but: %s", err)
+	}
+
+	rst, err := proto.Marshal(a)
+	if err != nil {
+		t.Errorf("expect no error but: %s", err)
+	}
+
+	b := &array.F64{}
+	err = proto.Unmarshal(rst, b)
+	if err != nil {
+		t.Errorf("expect no error but: %s", err)
+	}
+
+	// proto pollute this field
+	a.XXX_sizecache = 0
+	if !reflect.DeepEqual(a, b) {
+		t.Fatalf("compare: a b: %v", pretty.Diff(a, b))
+	}
+}
+
+func BenchmarkF64Get(b *testing.B) {
+	a, err := array.NewF64([]int32{1, 2, 3}, []float64{1, 2, 3})
+	if err != nil {
+		panic(err)
+	}
+
+	s := float64(0)
+	for i := 0; i < b.N; i++ {
+		r, _ := a.Get(Input)
+		s += r
+	}
+	Output = int64(s)
+}
@@ -10,17 +10,24 @@ import (
 )
 `
 
+var floatImplHead = `package array
+import (
+	"math"
+	"math/bits"
+)
+`
+
 var implTemplate = `
 // {{.Name}} is an implementation of Base with {{.ValType}} element
 //
-// Since 0.2.0
+// Since {{.Since}}
 type {{.Name}} struct {
 	Base
 }
 
 // New{{.Name}} creates a {{.Name}}
 //
-// Since 0.2.0
+// Since {{.Since}}
 func New{{.Name}}(index []int32, elts []{{.ValType}}) (a *{{.Name}}, err error) {
 	a = &{{.Name}}{}
 	err = a.Init(index, elts)
@@ -33,7 +40,7 @@ func New{{.Name}}(index []int32, elts []{{.ValType}}) (a *{{.Name}}, err error)
 // Get returns value at "idx" and a bool indicating if the value is
 // found.
 //
-// Since 0.2.0
+// Since {{.Since}}
 func (a *{{.Name}}) Get(idx int32) ({{.ValType}}, bool) {
 
 	iBm, iBit := bmBit(idx)
@@ -48,7 +55,7 @@ func (a *{{.Name}}) Get(idx int32) ({{.ValType}}, bool) {
 
 	stIdx := a.Offsets[iBm]*{{.ValLen}} + int32(cnt1)*{{.ValLen}}
 
-	return {{.ValType}}(endian.{{.Codec}}(a.Elts[stIdx:])), true
+	return {{.Decode}}, true
 }
 `
 
@@ -72,10 +79,28 @@ var Input int32 = 2
 var Output int64
 `
 
+var floatTestHead = `package array_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+
+	proto "github.com/golang/protobuf/proto"
+	"github.com/kr/pretty"
+	"github.com/openacid/slim/array"
+)
+`
+
 var testTemplate = `
 func Test{{.Name}}NewErrorArgments(t *testing.T) {
 	var index []int32
-	eltsData := []{{.ValType}}{12, 15, 19, 120, 300}
+	eltsData := []{{.ValType}}{12, 15, 19, 120, {{.Big}}}
 
 	var err error
 
@@ -101,7 +126,7 @@ func Test{{.Name}}New(t *testing.T) {
 			[]int32{}, []{{.ValType}}{},
 		},
 		{
-			[]int32{0, 5, 9, 203, 400}, []{{.ValType}}{12, 15, 19, 120, 300},
+			[]int32{0, 5, 9, 203, 400}, []{{.ValType}}{12, 15, 19, 120, {{.Big}}},
 		},
 	}
 
@@ -169,7 +194,7 @@ func Test{{.Name}}Get(t *testing.T) {
 
 		if found {
 			if eltsData[dataIdx] != actByte {
-				t.Fatalf("Get i:%d is not equal expect: %d, act: %d", ii, eltsData[dataIdx], actByte)
+				t.Fatalf("Get i:%d is not equal expect: %v, act: %v", ii, eltsData[dataIdx], actByte)
 			}
 		}
 
@@ -220,7 +245,7 @@ func Test{{.Name}}EncodeDecode(t *testing.T) {
 			want = append(c.want, byte(c.n*{{.ValLen}}))
 			for i := 0; i < c.n; i++ {
 				b := make([]byte, {{.ValLen}})
-				binary.LittleEndian.Put{{.Codec}}(b, {{.EncodeCast}}(elts[i]))
+				{{.Put}}
 				want = append(want, b...)
 			}
 		}
@@ -310,21 +335,82 @@ func Benchmark{{.Name}}Get(b *testing.B) {
 }
 `
 
+// eltConfig extends genr.IntConfig with the code snippets that differ between
+// element types.
+type eltConfig struct {
+	*genr.IntConfig
+
+	// Decode is the expression to load an elt from a.Elts[stIdx:].
+	Decode string
+
+	// Put is the statement in test to encode elts[i] into b.
+	Put string
+
+	// Big is the max value in test that fits in this type.
+	Big string
+
+	// Since is the version this type is introduced.
+	Since string
+}
+
+func newIntConfig(typeName, valueType, since string) *eltConfig {
+	c := &eltConfig{
+		IntConfig: genr.NewIntConfig(typeName, valueType),
+		Big:       "300",
+		Since:     since,
+	}
+
+	if c.ValLen == 1 {
+		c.Decode = valueType + "(a.Elts[stIdx])"
+		c.Put = "b[0] = byte(elts[i])"
+		c.Big = "127"
+	} else {
+		c.Decode = valueType + "(endian." + c.Codec + "(a.Elts[stIdx:]))"
+		c.Put = "binary.LittleEndian.Put" + c.Codec + "(b, " + c.EncodeCast + "(elts[i]))"
+	}
+	return c
+}
+
+func newFloatConfig(typeName, valueType string, valLen int) *eltConfig {
+	bits := map[int]string{4: "32", 8: "64"}[valLen]
+	return &eltConfig{
+		IntConfig: &genr.IntConfig{
+			Name:       typeName,
+			ValType:    valueType,
+			ValLen:     valLen,
+			Codec:      "Uint" + bits,
+			EncodeCast: "math.Float" + bits + "bits",
+		},
+		Decode: "math.Float" + bits + "frombits(endian.Uint" + bits + "(a.Elts[stIdx:]))",
+		Put:    "binary.LittleEndian.PutUint" + bits + "(b, math.Float" + bits + "bits(elts[i]))",
+		Big:    "300",
+		Since:  "0.5.13",
+	}
+}
+
 func main() {
 
-	pref := "int"
-	implfn := pref + ".go"
-	testfn := pref + "_test.go"
-
-	impls := []interface{}{
-		genr.NewIntConfig("U16", "uint16"),
-		genr.NewIntConfig("U32", "uint32"),
-		genr.NewIntConfig("U64", "uint64"),
-		genr.NewIntConfig("I16", "int16"),
-		genr.NewIntConfig("I32", "int32"),
-		genr.NewIntConfig("I64", "int64"),
+	linters := []string{"gofmt", "unconvert"}
+
+	ints := []interface{}{
+		newIntConfig("U16", "uint16", "0.2.0"),
+		newIntConfig("U32", "uint32", "0.2.0"),
+		newIntConfig("U64", "uint64", "0.2.0"),
+		newIntConfig("I16", "int16", "0.2.0"),
+		newIntConfig("I32", "int32", "0.2.0"),
+		newIntConfig("I64", "int64", "0.2.0"),
+		newIntConfig("U8", "uint8", "0.5.13"),
+		newIntConfig("I8", "int8", "0.5.13"),
+	}
+
+	genr.Render("int.go", implHead, implTemplate, ints, linters)
+	genr.Render("int_test.go", testHead, testTemplate, ints, linters)
+
+	floats := []interface{}{
+		newFloatConfig("F32", "float32", 4),
+		newFloatConfig("F64", "float64", 8),
 	}
 
-	genr.Render(implfn, implHead, implTemplate, impls, []string{"gofmt", "unconvert"})
-	genr.Render(testfn, testHead, testTemplate, impls, []string{"gofmt", "unconvert"})
+	genr.Render("float.go", floatImplHead, implTemplate, floats, linters)
+	genr.Render("float_test.go", floatTestHead, testTemplate, floats, linters)
 }
@@ -245,3 +245,83 @@ func (a *I64) Get(idx int32) (int64, bool) {
 
 	return int64(endian.Uint64(a.Elts[stIdx:])), true
 }
+
+// U8 is an implementation of Base with uint8 element
+//
+// Since 0.5.13
+type U8 struct {
+	Base
+}
+
+// NewU8 creates a U8
+//
+// Since 0.5.13
+func NewU8(index []int32, elts []uint8) (a *U8, err error) {
+	a = &U8{}
+	err = a.Init(index, elts)
+	if err != nil {
+		a = nil
+	}
+	return a, err
+}
+
+// Get returns value at "idx" and a bool indicating if the value is
+// found.
+//
+// Since 0.5.13
+func (a *U8) Get(idx int32) (uint8, bool) {
+
+	iBm, iBit := bmBit(idx)
+
+	var n = a.Bitmaps[iBm]
+
+	if ((n >> uint(iBit)) & 1) == 0 {
+		return 0, false
+	}
+
+	cnt1 := bits.OnesCount64(n & ((uint64(1) << uint(iBit)) - 1))
+
+	stIdx := a.Offsets[iBm]*1 + int32(cnt1)*1
+
+	return a.Elts[stIdx], true
+}
+
+// I8 is an implementation of Base with int8 element
+//
+// Since 0.5.13
+type I8 struct {
+	Base
+}
+
+// NewI8 creates a I8
+//
+// Since 0.5.13
+func NewI8(index []int32, elts []int8) (a *I8, err error) {
+	a = &I8{}
+	err = a.Init(index, elts)
+	if err != nil {
+		a = nil
+	}
+	return a, err
+}
+
+// Get returns value at "idx" and a bool indicating if the value is
+// found.
+//
+// Since 0.5.13
+func (a *I8) Get(idx int32) (int8, bool) {
+
+	iBm, iBit := bmBit(idx)
+
+	var n = a.Bitmaps[iBm]
+
+	if ((n >> uint(iBit)) & 1) == 0 {
+		return 0, false
+	}
+
+	cnt1 := bits.OnesCount64(n & ((uint64(1) << uint(iBit)) - 1))
+
+	stIdx := a.Offsets[iBm]*1 + int32(cnt1)*1
+
+	return int8(a.Elts[stIdx]), true
+}
@@ -115,7 +115,7 @@ func TestU16Get(t *testing.T) {
 
 		if found {
 			if eltsData[dataIdx] != actByte {
-				t.Fatalf("Get i:%d is not equal expect: %d, act: %d", ii, eltsData[dataIdx], actByte)
+				t.Fatalf("Get i:%d is not equal expect: %v, act: %v", ii, eltsData[dataIdx], actByte)
 			}
 		}
 
@@ -351,7 +351,7 @@ func TestU32Get(t *testing.T) {
 
 		if found {
 			if eltsData[dataIdx] != actByte {
-				t.Fatalf("Get i:%d is not equal expect: %d, act: %d", ii, eltsData[dataIdx], actByte)
+				t.Fatalf("Get i:%d is not equal expect: %v, act: %v", ii, eltsData[dataIdx], actByte)
 			}
 		}
 
@@ -587,7 +587,7 @@ func TestU64Get(t *testing.T) {
 
 		if found {
 			if eltsData[dataIdx] != actByte {
-				t.Fatalf("Get i:%d is not equal expect: %d, act: %d", ii, eltsData[dataIdx], actByte)
+				t.Fatalf("Get i:%d is not equal expect: %v, act: %v", ii, eltsData[dataIdx], actByte)
 			}
 		}
 
@@ -823,7 +823,7 @@ func TestI16Get(t *testing.T) {
 
 		if found {
 			if eltsData[dataIdx] != actByte {
-				t.Fatalf("Get i:%d is not equal expect: %d, act: %d", ii, eltsData[dataIdx], actByte)
+				t.Fatalf("Get i:%d is not equal expect: %v, act: %v", ii, eltsData[dataIdx], actByte)
 			}
 		}
 
@@ -1059,7 +1059,7 @@ func TestI32Get(t *testing.T) {
 
 		if found {
 			if eltsData[dataIdx] != actByte {
-				t.Fatalf("Get i:%d is not equal expect: %d, act: %d", ii, eltsData[dataIdx], actByte)
+				t.Fatalf("Get i:%d is not equal expect: %v, act: %v", ii, eltsData[dataIdx], actByte)
 			}
 		}
 
@@ -1295,7 +1295,7 @@ func TestI64Get(t *testing.T) {
 
 		if found {
 			if eltsData[dataIdx] != actByte {
-				t.Fatalf("Get i:%d is not equal expect: %d, act: %d", ii, eltsData[dataIdx], actByte)
+				t.Fatalf("Get i:%d is not equal expect: %v, act: %v", ii, eltsData[dataIdx], actByte)
 			}
 		}
 
@@ -1434,3 +1434,475 @@ func BenchmarkI64Get(b *testing.B) {
 	}
 	Output = s
 }
+
+func TestU8NewErrorArgments(t *testing.T) {
+	var index []int32
+	eltsData := []uint8{12, 15, 19, 120, 127}
+
+	var err error
+
+	index = []int32{1, 5, 9, 203}
+	_, err = array.NewU8(index, eltsData)
+	if err == nil {
+		t.Fatalf("new with wrong index length must error")
+	}
+
+	index = []int32{1, 5, 5, 203, 400}
+	_, err = array.NewU8(index, eltsData)
+	if err == nil {
+		t.Fatalf("new with unsorted index must error")
+	}
+}
+
+func TestU8New(t *testing.T) {
+	var cases = []struct {
+		index    []int32
+		eltsData []uint8
+	}{
+		{
+			[]int32{}, []uint8{},
+		},
+		{
+			[]int32{0, 5, 9, 203, 400}, []uint8{12, 15, 19, 120, 127},
+		},
+	}
+
+	for _, c := range cases {
+		index, eltsData := c.index, c.eltsData
+		cnt := int32(len(index))
+
+		a, err := array.NewU8(index, eltsData)
+		if err != nil {
+			t.Fatalf("failed new compacted array, err: %s", err)
+		}
+
+		if a.Cnt != cnt {
+			t.Fatalf("cnt is not equal expect: %d, act: %d", cnt, a.Cnt)
+		}
+
+		buf := new(bytes.Buffer)
+		_ = binary.Write(buf, binary.LittleEndian, eltsData)
+
+		expElts := buf.Bytes()
+		if expElts == nil {
+			expElts = []byte{}
+		}
+
+		if !reflect.DeepEqual(a.Elts, expElts) && len(a.Elts) != 0 && len(expElts) != 0 {
+			fmt.Println(pretty.Diff(a.Elts, expElts))
+			t.Fatalf("elts is not equal expect: %d, act: %d", expElts, a.Elts)
+		}
+	}
+
+}
+
+func TestU8Get(t *testing.T) {
+	index, eltsData := []int32{}, []uint8{}
+	rnd := rand.New(rand.NewSource(time.Now().Unix()))
+
+	keysMap := map[int32]bool{}
+	num, idx, cnt := int32(0), int32(0), int32(1024)
+	for {
+		if rnd.Intn(2) == 1 {
+			index = append(index, idx)
+			eltsData = append(eltsData, uint8(rnd.Uint64()))
+			num++
+			keysMap[idx] = true
+		}
+		idx++
+		if num == cnt {
+			break
+		}
+	}
+
+	a, err := array.NewU8(index, eltsData)
+	if err != nil {
+		t.Fatalf("failed new compacted array, err: %s", err)
+	}
+
+	dataIdx := int32(0)
+	for ii := int32(0); ii < idx; ii++ {
+
+		actByte, found := a.Get(ii)
+		_, present := keysMap[ii]
+		if found != present {
+			t.Fatalf("Get i:%d present:%t but:%t", ii, present, found)
+		}
+
+		if found {
+			if eltsData[dataIdx] != actByte {
+				t.Fatalf("Get i:%d is not equal expect: %v, act: %v", ii, eltsData[dataIdx], actByte)
+			}
+		}
+
+		if _, ok := keysMap[ii]; ok {
+			dataIdx++
+		}
+	}
+}
+
+func TestU8EncodeDecode(t *testing.T) {
+
+	indexes := []int32{1, 5, 9, 203}
+	elts := []uint8{12, 15, 19, 120}
+
+	cases := []struct {
+		n    int
+		want []byte
+	}{
+		{
+			0,
+			[]byte{},
+		},
+		{
+			1,
+			[]byte{8, 1, 18, 1, 2, 26, 1, 0, 34},
+		},
+		{
+			2,
+			[]byte{8, 2, 18, 1, 34, 26, 1, 0, 34},
+		},
+	}
+
+	for i, c := range cases {
+
+		a, err := array.NewU8(indexes[:c.n], elts[:c.n])
+		if err != nil {
+			t.Errorf("expect no error but: %s", err)
+		}
+
+		rst, err := proto.Marshal(a)
+		if err != nil {
+			t.Errorf("expect no error but: %s", err)
+		}
+
+		// build Elts part for template generated test codes
+		var want []byte = c.want
+		if c.n > 0 {
+			want = append(c.want, byte(c.n*1))
+			for i := 0; i < c.n; i++ {
+				b := make([]byte, 1)
+				b[0] = elts[i]
+				want = append(want, b...)
+			}
+		}
+
+		if !reflect.DeepEqual(rst, want) {
+			t.Fatalf("%d-th: n: %v; want: %v; actual: %v",
+				i+1, c.n, want, rst)
+		}
+
+		// Decode
+
+		b := &array.U8{}
+		err = proto.Unmarshal(rst, b)
+
+		if err != nil {
+			t.Errorf("expect no error but: %s", err)
+		}
+
+		if !reflect.DeepEqual(a.Elts, b.Elts) {
+			t.Fatalf("%d-th: n: %v; compare Elts: a: %v; b: %v",
+				i+1, c.n, a.Elts, b.Elts)
+		}
+
+		// protobuf handles empty structure specially.
+		if c.n == 0 {
+			continue
+		}
+
+		// ignore proto's field when compare
+		a.XXX_sizecache = 0
+
+		if !reflect.DeepEqual(a, b) {
+			t.Fatalf("%d-th: n: %v; compare a b: %v",
+				i+1, c.n, pretty.Diff(a, b))
+		}
+
+	}
+}
+
+func TestU8EncodeDecodeBig(t *testing.T) {
+
+	n := 102400
+	step := 2
+	indexes := []int32{}
+	elts := []uint8{}
+
+	for i := 0; i < n; i += step {
+		indexes = append(indexes, int32(i))
+		elts = append(elts, uint8(i))
+	}
+
+	a, err := array.NewU8(indexes, elts)
+	if err != nil {
+		t.Errorf("expect no error but: %s", err)
+	}
+
+	rst, err := proto.Marshal(a)
+	if err != nil {
+		t.Errorf("expect no error but: %s", err)
+	}
+
+	b := &array.U8{}
+	err = proto.Unmarshal(rst, b)
+	if err != nil {
+		t.Errorf("expect no error but: %s", err)
+	}
+
+	// proto pollute this field
+	a.XXX_sizecache = 0
+	if !reflect.DeepEqual(a, b) {
+		t.Fatalf("compare: a b: %v", pretty.Diff(a, b))
+	}
+}
+
+func BenchmarkU8Get(b *testing.B) {
+	a, err := array.NewU8([]int32{1, 2, 3}, []uint8{1, 2, 3})
+	if err != nil {
+		panic(err)
+	}
+
+	s := uint8(0)
+	for i := 0; i < b.N; i++ {
+		r, _ := a.Get(Input)
+		s += r
+	}
+	Output = int64(s)
+}
+
+func TestI8NewErrorArgments(t *testing.T) {
+	var index []int32
+	eltsData := []int8{12, 15, 19, 120, 127}
+
+	var err error
+
+	index = []int32{1, 5, 9, 203}
+	_, err = array.NewI8(index, eltsData)
+	if err == nil {
+		t.Fatalf("new with wrong index length must error")
+	}
+
+	index = []int32{1, 5, 5, 203, 400}
+	_, err = array.NewI8(index, eltsData)
+	if err == nil {
+		t.Fatalf("new with unsorted index must error")
+	}
+}
+
+func TestI8New(t *testing.T) {
+	var cases = []struct {
+		index    []int32
+		eltsData []int8
+	}{
+		{
+			[]int32{}, []int8{},
+		},
+		{
+			[]int32{0, 5, 9, 203, 400}, []int8{12, 15, 19, 120, 127},
+		},
+	}
+
+	for _, c := range cases {
+		index, eltsData := c.index, c.eltsData
+		cnt := int32(len(index))
+
+		a, err := array.NewI8(index, eltsData)
+		if err != nil {
+			t.Fatalf("failed new compacted array, err: %s", err)
+		}
+
+		if a.Cnt != cnt {
+			t.Fatalf("cnt is not equal expect: %d, act: %d", cnt, a.Cnt)
+		}
+
+		buf := new(bytes.Buffer)
+		_ = binary.Write(buf, binary.LittleEndian, eltsData)
+
+		expElts := buf.Bytes()
+		if expElts == nil {
+			expElts = []byte{}
+		}
+
+		if !reflect.DeepEqual(a.Elts, expElts) && len(a.Elts) != 0 && len(expElts) != 0 {
+			fmt.Println(pretty.Diff(a.Elts, expElts))
+			t.Fatalf("elts is not equal expect: %d, act: %d", expElts, a.Elts)
+		}
+	}
+
+}
+
+func TestI8Get(t *testing.T) {
+	index, eltsData := []int32{}, []int8{}
+	rnd := rand.New(rand.NewSource(time.Now().Unix()))
+
+	keysMap := map[int32]bool{}
+	num, idx, cnt := int32(0), int32(0), int32(1024)
+	for {
+		if rnd.Intn(2) == 1 {
+			index = append(index, idx)
+			eltsData = append(eltsData, int8(rnd.Uint64()))
+			num++
+			keysMap[idx] = true
+		}
+		idx++
+		if num == cnt {
+			break
+		}
+	}
+
+	a, err := array.NewI8(index, eltsData)
+	if err != nil {
+		t.Fatalf("failed new compacted array, err: %s", err)
+	}
+
+	dataIdx := int32(0)
+	for ii := int32(0); ii < idx; ii++ {
+
+		actByte, found := a.Get(ii)
+		_, present := keysMap[ii]
+		if found != present {
+			t.Fatalf("Get i:%d present:%t but:%t", ii, present, found)
+		}
+
+		if found {
+			if eltsData[dataIdx] != actByte {
+				t.Fatalf("Get i:%d is not equal expect: %v, act: %v", ii, eltsData[dataIdx], actByte)
+			}
+		}
+
+		if _, ok := keysMap[ii]; ok {
+			dataIdx++
+		}
+	}
+}
+
+func TestI8EncodeDecode(t *testing.T) {
+
+	indexes := []int32{1, 5, 9, 203}
+	elts := []int8{12, 15, 19, 120}
+
+	cases := []struct {
+		n    int
+		want []byte
+	}{
+		{
+			0,
+			[]byte{},
+		},
+		{
+			1,
+			[]byte{8, 1, 18, 1, 2, 26, 1, 0, 34},
+		},
+		{
+			2,
+			[]byte{8, 2, 18, 1, 34, 26, 1, 0, 34},
+		},
+	}
+
+	for i, c := range cases {
+
+		a, err := array.NewI8(indexes[:c.n], elts[:c.n])
+		if err != nil {
+			t.Errorf("expect no error but: %s", err)
+		}
+
+		rst, err := proto.Marshal(a)
+		if err != nil {
+			t.Errorf("expect no error but: %s", err)
+		}
+
+		// build Elts part for template generated test codes
+		var want []byte = c.want
+		if c.n > 0 {
+			want = append(c.want, byte(c.n*1))
+			for i := 0; i < c.n; i++ {
+				b := make([]byte, 1)
+				b[0] = byte(elts[i])
+				want = append(want, b...)
+			}
+		}
+
+		if !reflect.DeepEqual(rst, want) {
+			t.Fatalf("%d-th: n: %v; want: %v; actual: %v",
+				i+1, c.n, want, rst)
+		}
+
+		// Decode
+
+		b := &array.I8{}
+		err = proto.Unmarshal(rst, b)
+
+		if err != nil {
+			t.Errorf("expect no error but: %s", err)
+		}
+
+		if !reflect.DeepEqual(a.Elts, b.Elts) {
+			t.Fatalf("%d-th: n: %v; compare Elts: a: %v; b: %v",
+				i+1, c.n, a.Elts, b.Elts)
+		}
+
+		// protobuf handles empty structure specially.
+		if c.n == 0 {
+			continue
+		}
+
+		// ignore proto's field when compare
+		a.XXX_sizecache = 0
+
+		if !reflect.DeepEqual(a, b) {
+			t.Fatalf("%d-th: n: %v; compare a b: %v",
+				i+1, c.n, pretty.Diff(a, b))
+		}
+
+	}
+}
+
+func TestI8EncodeDecodeBig(t *testing.T) {
+
+	n := 102400
+	step := 2
+	indexes := []int32{}
+	elts := []int8{}
+
+	for i := 0; i < n; i += step {
+		indexes = append(indexes, int32(i))
+		elts = append(elts, int8(i))
+	}
+
+	a, err := array.NewI8(indexes, elts)
+	if err != nil {
+		t.Errorf("expect no error but: %s", err)
+	}
+
+	rst, err := proto.Marshal(a)
+	if err != nil {
+		t.Errorf("expect no error but: %s", err)
+	}
+
+	b := &array.I8{}
+	err = proto.Unmarshal(rst, b)
+	if err != nil {
+		t.Errorf("expect no error but: %s", err)
+	}
+
+	// proto pollute this field
+	a.XXX_sizecache = 0
+	if !reflect.DeepEqual(a, b) {
+		t.Fatalf("compare: a b: %v", pretty.Diff(a, b))
+	}
+}
+
+func BenchmarkI8Get(b *testing.B) {
+	a, err := array.NewI8([]int32{1, 2, 3}, []int8{1, 2, 3})
+	if err != nil {
+		panic(err)
+	}
+
+	s := int8(0)
+	for i := 0; i < b.N; i++ {
+		r, _ := a.Get(Input)
+		s += r
+	}
+	Output = int64(s)
+}
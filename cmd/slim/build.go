@@ -0,0 +1,171 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/openacid/slim/trie"
+)
+
+// metaFlag collects repeated -meta key=value flags.
+type metaFlag map[string][]byte
+
+func (m metaFlag) String() string {
+	var kvs []string
+	for k, v := range m {
+		kvs = append(kvs, k+"="+string(v))
+	}
+	return strings.Join(kvs, ",")
+}
+
+func (m metaFlag) Set(s string) error {
+	i := strings.IndexByte(s, '=')
+	if i <= 0 {
+		return fmt.Errorf("expect key=value but: %q", s)
+	}
+	m[s[:i]] = []byte(s[i+1:])
+	return nil
+}
+
+func newFlagSet(e *env, name, args string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(e.stderr, "Usage: slim %s %s\n", name, args)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseFlags parses args and converts errors to errUsage or flag.ErrHelp.
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	err := fs.Parse(args)
+	if err == nil || err == flag.ErrHelp {
+		return err
+	}
+	return errUsage
+}
+
+// addInputFlags defines flags to read key-value lines, shared by build and
+// verify.
+func addInputFlags(fs *flag.FlagSet, opt *inputOpt) {
+	fs.StringVar(&opt.format, "format", "tsv", "input format: tsv or csv")
+	fs.BoolVar(&opt.skipHeader, "skip-header", false, "skip the first line of input")
+}
+
+// loadRecords reads and sorts records from a file or stdin.
+func loadRecords(e *env, path string, opt inputOpt) ([]record, error) {
+
+	r, closeInput, err := openInput(path, e.stdin)
+	if err != nil {
+		return nil, err
+	}
+	defer closeInput()
+
+	recs, err := readRecords(r, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	err = sortRecords(recs)
+	if err != nil {
+		return nil, err
+	}
+
+	return recs, nil
+}
+
+func runBuild(e *env, args []string) error {
+
+	var (
+		inOpt       inputOpt
+		output      string
+		encoderName string
+		complete    bool
+		innerPrefix bool
+		leafPrefix  bool
+		noDedup     bool
+		compact     bool
+		meta        = metaFlag{}
+	)
+
+	fs := newFlagSet(e, "build", "[flags] -o out.slim [input|-]")
+	addInputFlags(fs, &inOpt)
+	fs.StringVar(&output, "o", "", "output file, required")
+	fs.StringVar(&encoderName, "encoder", "string", "value type, one of: "+strings.Join(valueTypeNames(), ", "))
+	fs.BoolVar(&complete, "complete", false, "store complete keys, required by scan and exact queries")
+	fs.BoolVar(&innerPrefix, "inner-prefix", false, "store prefixes of inner nodes")
+	fs.BoolVar(&leafPrefix, "leaf-prefix", false, "store prefixes of leaf nodes")
+	fs.BoolVar(&noDedup, "no-dedup", false, "keep keys whose value equals the previous one")
+	fs.BoolVar(&compact, "compact", false, "write with MarshalCompact")
+	fs.Var(meta, "meta", "add metadata key=value, can be repeated")
+
+	err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if output == "" {
+		return usagef("-o is required")
+	}
+	if fs.NArg() > 1 {
+		return usagef("too many arguments: %v", fs.Args())
+	}
+
+	vt, err := findValueType(encoderName)
+	if err != nil {
+		return usagef("%v", err)
+	}
+	inOpt.noValue = vt.parse == nil
+
+	recs, err := loadRecords(e, fs.Arg(0), inOpt)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, len(recs))
+	for i, rec := range recs {
+		keys[i] = rec.key
+	}
+
+	values, err := parseValues(recs, vt)
+	if err != nil {
+		return err
+	}
+
+	opt := trie.Opt{
+		Complete:    trie.Bool(complete),
+		InnerPrefix: trie.Bool(innerPrefix),
+		LeafPrefix:  trie.Bool(leafPrefix),
+		DedupValue:  trie.Bool(!noDedup),
+	}
+
+	st, err := trie.NewSlimTrie(vt.encoder, keys, values, opt)
+	if err != nil {
+		return err
+	}
+
+	if len(meta) > 0 {
+		st.Metadata = meta
+	}
+
+	var b []byte
+	if compact {
+		b, err = st.MarshalCompact()
+	} else {
+		b, err = st.Marshal()
+	}
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(output, b, 0644)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(e.stderr, "built %s: %d keys, %d bytes\n", output, len(keys), len(b))
+	return nil
+}
@@ -0,0 +1,255 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/openacid/slim/encode"
+)
+
+// valueType describes how to parse a value from text and how to encode it in
+// a SlimTrie.
+type valueType struct {
+	name    string
+	encoder encode.Encoder
+	parse   func(s string) (interface{}, error)
+}
+
+func parseInt(bits int, conv func(int64) interface{}) func(string) (interface{}, error) {
+	return func(s string) (interface{}, error) {
+		v, err := strconv.ParseInt(s, 10, bits)
+		if err != nil {
+			return nil, err
+		}
+		return conv(v), nil
+	}
+}
+
+func parseUint(bits int, conv func(uint64) interface{}) func(string) (interface{}, error) {
+	return func(s string) (interface{}, error) {
+		v, err := strconv.ParseUint(s, 10, bits)
+		if err != nil {
+			return nil, err
+		}
+		return conv(v), nil
+	}
+}
+
+func parseString(s string) (interface{}, error) {
+	return s, nil
+}
+
+// valueTypes are value types supported by build, in the order they are
+// listed in usage.
+var valueTypes = []*valueType{
+	{"string", encode.String16{}, parseString},
+	{"varstring", encode.StringVarint{}, parseString},
+	{"int", encode.Int{}, parseInt(64, func(v int64) interface{} { return int(v) })},
+	{"i8", encode.I8{}, parseInt(8, func(v int64) interface{} { return int8(v) })},
+	{"i16", encode.I16{}, parseInt(16, func(v int64) interface{} { return int16(v) })},
+	{"i32", encode.I32{}, parseInt(32, func(v int64) interface{} { return int32(v) })},
+	{"i64", encode.I64{}, parseInt(64, func(v int64) interface{} { return v })},
+	{"u8", encode.U8{}, parseUint(8, func(v uint64) interface{} { return uint8(v) })},
+	{"u16", encode.U16{}, parseUint(16, func(v uint64) interface{} { return uint16(v) })},
+	{"u32", encode.U32{}, parseUint(32, func(v uint64) interface{} { return uint32(v) })},
+	{"u64", encode.U64{}, parseUint(64, func(v uint64) interface{} { return v })},
+	{"varint", encode.Varint{}, parseInt(64, func(v int64) interface{} { return v })},
+	{"uvarint", encode.UVarint{}, parseUint(64, func(v uint64) interface{} { return v })},
+	{"f32", encode.F32{}, func(s string) (interface{}, error) {
+		v, err := strconv.ParseFloat(s, 32)
+		return float32(v), err
+	}},
+	{"f64", encode.F64{}, func(s string) (interface{}, error) {
+		return strconv.ParseFloat(s, 64)
+	}},
+	{"bool", encode.Bool{}, func(s string) (interface{}, error) {
+		return strconv.ParseBool(s)
+	}},
+	// A trie without values works as a filter.
+	{"none", nil, nil},
+}
+
+func valueTypeNames() []string {
+	var names []string
+	for _, vt := range valueTypes {
+		names = append(names, vt.name)
+	}
+	return names
+}
+
+func findValueType(name string) (*valueType, error) {
+	for _, vt := range valueTypes {
+		if vt.name == name {
+			return vt, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown value type: %q, supported: %s", name, strings.Join(valueTypeNames(), ", "))
+}
+
+// valueTypeOf finds the value type by the encoder of a SlimTrie.
+func valueTypeOf(e encode.Encoder) (*valueType, error) {
+	if e == nil {
+		return findValueType("none")
+	}
+
+	name, params, err := encode.Describe(e)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, vt := range valueTypes {
+		if vt.encoder == nil {
+			continue
+		}
+		n, p, _ := encode.Describe(vt.encoder)
+		if n == name && string(p) == string(params) {
+			return vt, nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported encoder: %s", name)
+}
+
+// record is a line of input.
+type record struct {
+	key   string
+	value string
+	line  int
+}
+
+// inputOpt specifies how to read records.
+type inputOpt struct {
+	format     string
+	skipHeader bool
+	noValue    bool
+}
+
+// openInput opens a file or stdin if path is "-" or empty.
+func openInput(path string, stdin io.Reader) (io.Reader, func(), error) {
+	if path == "" || path == "-" {
+		return stdin, func() {}, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return f, func() { f.Close() }, nil
+}
+
+// readRecords reads key-value records in TSV or CSV.
+// A TSV line is a key, a tab and a value.
+// A CSV row has the key in the first column and the value in the second.
+func readRecords(r io.Reader, opt inputOpt) ([]record, error) {
+
+	var recs []record
+
+	switch opt.format {
+	case "tsv":
+		sc := bufio.NewScanner(r)
+		sc.Buffer(make([]byte, 64*1024), 64*1024*1024)
+		line := 0
+		for sc.Scan() {
+			line++
+			if line == 1 && opt.skipHeader {
+				continue
+			}
+
+			s := strings.TrimSuffix(sc.Text(), "\r")
+			k, v := s, ""
+			if i := strings.IndexByte(s, '\t'); i >= 0 {
+				k, v = s[:i], s[i+1:]
+			} else if !opt.noValue {
+				return nil, fmt.Errorf("line %d: no tab separated value", line)
+			}
+			recs = append(recs, record{key: k, value: v, line: line})
+		}
+		if err := sc.Err(); err != nil {
+			return nil, err
+		}
+
+	case "csv":
+		cr := csv.NewReader(r)
+		cr.FieldsPerRecord = -1
+		line := 0
+		for {
+			row, err := cr.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			line++
+			if line == 1 && opt.skipHeader {
+				continue
+			}
+
+			if len(row) < 2 && !opt.noValue {
+				return nil, fmt.Errorf("line %d: no value column", line)
+			}
+			rec := record{key: row[0], line: line}
+			if len(row) >= 2 {
+				rec.value = row[1]
+			}
+			recs = append(recs, rec)
+		}
+
+	default:
+		return nil, fmt.Errorf("unknown format: %q, supported: tsv, csv", opt.format)
+	}
+
+	return recs, nil
+}
+
+// sortRecords sorts records by key and returns an error if there are
+// duplicated keys.
+func sortRecords(recs []record) error {
+
+	sort.SliceStable(recs, func(i, j int) bool {
+		return recs[i].key < recs[j].key
+	})
+
+	for i := 1; i < len(recs); i++ {
+		if recs[i].key == recs[i-1].key {
+			return fmt.Errorf("duplicated key: %q at line %d and %d", recs[i].key, recs[i-1].line, recs[i].line)
+		}
+	}
+
+	return nil
+}
+
+// parseValues parses values of records into a slice of the type of vt, which
+// can be used to create a SlimTrie.
+func parseValues(recs []record, vt *valueType) (interface{}, error) {
+
+	if vt.parse == nil {
+		return nil, nil
+	}
+
+	var values reflect.Value
+
+	for i, rec := range recs {
+		v, err := vt.parse(rec.value)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid %s value: %q: %v", rec.line, vt.name, rec.value, err)
+		}
+
+		if i == 0 {
+			values = reflect.MakeSlice(reflect.SliceOf(reflect.TypeOf(v)), 0, len(recs))
+		}
+		values = reflect.Append(values, reflect.ValueOf(v))
+	}
+
+	if len(recs) == 0 {
+		return nil, nil
+	}
+
+	return values.Interface(), nil
+}
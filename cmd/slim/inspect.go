@@ -0,0 +1,315 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/openacid/low/pbcmpl"
+	"github.com/openacid/low/size"
+	"github.com/openacid/slim/encode"
+	"github.com/openacid/slim/trie"
+)
+
+// section is a top level field of trie.Slim.
+type section struct {
+	name string
+	msg  func(ns *trie.Slim) (interface{}, proto.Message)
+}
+
+var sections = []section{
+	{"NodeTypeBM", func(ns *trie.Slim) (interface{}, proto.Message) {
+		return ns.NodeTypeBM, &trie.Slim{NodeTypeBM: ns.NodeTypeBM}
+	}},
+	{"Inners", func(ns *trie.Slim) (interface{}, proto.Message) {
+		return ns.Inners, &trie.Slim{Inners: ns.Inners}
+	}},
+	{"ShortBM", func(ns *trie.Slim) (interface{}, proto.Message) {
+		return ns.ShortBM, &trie.Slim{ShortBM: ns.ShortBM}
+	}},
+	{"ShortTable", func(ns *trie.Slim) (interface{}, proto.Message) {
+		return ns.ShortTable, &trie.Slim{ShortTable: ns.ShortTable}
+	}},
+	{"InnerPrefixes", func(ns *trie.Slim) (interface{}, proto.Message) {
+		return ns.InnerPrefixes, &trie.Slim{InnerPrefixes: ns.InnerPrefixes}
+	}},
+	{"LeafPrefixes", func(ns *trie.Slim) (interface{}, proto.Message) {
+		return ns.LeafPrefixes, &trie.Slim{LeafPrefixes: ns.LeafPrefixes}
+	}},
+	{"Leaves", func(ns *trie.Slim) (interface{}, proto.Message) {
+		return ns.Leaves, &trie.Slim{Leaves: ns.Leaves}
+	}},
+}
+
+// readSlim reads the header and the body of marshaled SlimTrie.
+func readSlim(b []byte) (*trie.SlimHeader, *trie.Slim, error) {
+
+	r := bytes.NewReader(b)
+
+	h := &trie.SlimHeader{}
+	_, _, err := pbcmpl.Unmarshal(r, h)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ns := &trie.Slim{}
+	_, _, err = pbcmpl.Unmarshal(r, ns)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return h, ns, nil
+}
+
+func runStat(e *env, args []string) error {
+
+	fs := newFlagSet(e, "stat", "[-absent keys.txt] file.slim")
+	absentPath := fs.String("absent", "", "measure false positive rate with keys not in the trie, one per line")
+	err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return usagef("expect one trie file but: %v", fs.Args())
+	}
+
+	path := fs.Arg(0)
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	st, err := trie.Load(b)
+	if err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+
+	var absentKeys []string
+	if *absentPath != "" {
+		absentKeys, err = readKeyFile(*absentPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	h, _, err := readSlim(b)
+	if err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+
+	w := bufio.NewWriter(e.stdout)
+	defer w.Flush()
+
+	fmt.Fprintf(w, "file:      %s\n", path)
+	fmt.Fprintf(w, "file size: %d\n", len(b))
+	fmt.Fprintf(w, "version:   %s\n", st.GetVersion())
+	fmt.Fprintf(w, "encoder:   %s\n", encoderString(st.Encoder()))
+	fmt.Fprintf(w, "compact:   %t\n", h.Compact)
+	fmt.Fprintf(w, "opt:       %s\n", optString(st.Opt()))
+
+	if len(st.Metadata) > 0 {
+		fmt.Fprintf(w, "metadata:\n")
+		keys := make([]string, 0, len(st.Metadata))
+		for k := range st.Metadata {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(w, "    %s=%s\n", k, st.Metadata[k])
+		}
+	}
+
+	stat := st.Stat()
+	fmt.Fprintf(w, "keys:      %d\n", stat.KeyCnt)
+	fmt.Fprintf(w, "nodes:     %d\n", stat.NodeCnt)
+	fmt.Fprintf(w, "levels:    %d\n", stat.LevelCnt)
+	fmt.Fprintf(w, "fpr:       %.6f expected", stat.ExpectedFPR)
+	if *absentPath != "" {
+		fmt.Fprintf(w, ", %.6f measured with %d keys", st.EstimateFPR(absentKeys), len(absentKeys))
+	}
+	fmt.Fprintf(w, "\n")
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(tw, "level\ttotal\tinner\tleaf\t\n")
+	for i, l := range stat.Levels {
+		fmt.Fprintf(tw, "%d\t%d\t%d\t%d\t\n", i, l.Total, l.Inner, l.Leaf)
+	}
+	tw.Flush()
+
+	memSize := size.Of(st)
+	fmt.Fprintf(w, "memory:    %d (%.1f bytes/key)\n", memSize, perKey(memSize, stat.KeyCnt))
+
+	return writeBreakdown(w, st, stat.KeyCnt)
+}
+
+// readKeyFile reads keys from a file, one per line.
+func readKeyFile(path string) ([]string, error) {
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	s := strings.TrimSuffix(string(b), "\n")
+	if s == "" {
+		return nil, nil
+	}
+
+	keys := strings.Split(s, "\n")
+	for i, k := range keys {
+		keys[i] = strings.TrimSuffix(k, "\r")
+	}
+	return keys, nil
+}
+
+// writeBreakdown prints the in-memory size and the marshaled size of every
+// section.
+// The marshaled size is of the output of Marshal, which differs from the file
+// size if the file is written by MarshalCompact.
+func writeBreakdown(w io.Writer, st *trie.SlimTrie, keyCnt int32) error {
+
+	b, err := st.Marshal()
+	if err != nil {
+		return err
+	}
+
+	_, ns, err := readSlim(b)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(tw, "section\tmemory\tmarshaled\tbytes/key\t\n")
+	for _, sec := range sections {
+		v, msg := sec.msg(ns)
+		m := size.Of(v)
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.2f\t\n", sec.name, m, proto.Size(msg), perKey(m, keyCnt))
+	}
+	return tw.Flush()
+}
+
+func perKey(n int, keyCnt int32) float64 {
+	if keyCnt == 0 {
+		return 0
+	}
+	return float64(n) / float64(keyCnt)
+}
+
+func encoderString(e encode.Encoder) string {
+	if e == nil {
+		return "none"
+	}
+
+	name, params, err := encode.Describe(e)
+	if err != nil {
+		return fmt.Sprintf("%T (unregistered)", e)
+	}
+	if len(params) > 0 {
+		return fmt.Sprintf("%s %q", name, params)
+	}
+	return name
+}
+
+func optString(o trie.Opt) string {
+	f := func(b *bool) string {
+		if b == nil {
+			return "unknown"
+		}
+		return fmt.Sprintf("%t", *b)
+	}
+	return fmt.Sprintf("DedupValue=%s InnerPrefix=%s LeafPrefix=%s Complete=%s",
+		f(o.DedupValue), f(o.InnerPrefix), f(o.LeafPrefix), f(o.Complete))
+}
+
+func runVerify(e *env, args []string) error {
+
+	var inOpt inputOpt
+
+	fs := newFlagSet(e, "verify", "[flags] file.slim [input|-]")
+	addInputFlags(fs, &inOpt)
+
+	err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if fs.NArg() < 1 || fs.NArg() > 2 {
+		return usagef("expect a trie file and an input but: %v", fs.Args())
+	}
+
+	st, err := loadTrie(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	vt, err := valueTypeOf(st.Encoder())
+	if err != nil {
+		return err
+	}
+	inOpt.noValue = vt.parse == nil
+
+	recs, err := loadRecords(e, fs.Arg(1), inOpt)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(e.stdout)
+	defer w.Flush()
+
+	bad := 0
+	for _, rec := range recs {
+
+		// A key whose value equals the previous one is removed if the trie
+		// is built with DedupValue, thus look it up with RangeGet.
+		got, found := st.RangeGet(rec.key)
+
+		var want interface{}
+		if vt.parse != nil {
+			want, err = vt.parse(rec.value)
+			if err != nil {
+				return fmt.Errorf("line %d: invalid %s value: %q: %v", rec.line, vt.name, rec.value, err)
+			}
+		}
+
+		if !found || !reflect.DeepEqual(got, want) {
+			bad++
+			fmt.Fprintf(w, "mismatch: line %d key %q: want %s got %s\n",
+				rec.line, rec.key, formatFound(want, true), formatFound(got, found))
+		}
+	}
+
+	if bad > 0 {
+		fmt.Fprintf(w, "FAIL: %d of %d keys mismatch\n", bad, len(recs))
+		return errFailed
+	}
+
+	fmt.Fprintf(w, "OK: %d keys\n", len(recs))
+	return nil
+}
+
+func runDump(e *env, args []string) error {
+
+	fs := newFlagSet(e, "dump", "file.slim")
+	err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return usagef("expect one trie file but: %v", fs.Args())
+	}
+
+	st, err := loadTrie(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(e.stdout, st.String())
+	return err
+}
@@ -0,0 +1,130 @@
+// Command slim builds, inspects and queries SlimTrie files.
+//
+// Usage:
+//
+//	slim build [flags] -o out.slim [input|-]
+//	slim stat [-absent keys.txt] file.slim
+//	slim get file.slim [key...]
+//	slim range file.slim [key...]
+//	slim search file.slim [key...]
+//	slim scan [-from key] [-limit n] file.slim
+//	slim verify [flags] file.slim [input|-]
+//	slim dump file.slim
+//
+// Input of build and verify is key-value lines: a key, a tab and a value in
+// each line, or CSV with -format csv.
+// Query commands read keys from stdin, one per line, if no key is given.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+type command struct {
+	name  string
+	usage string
+	run   func(env *env, args []string) error
+}
+
+// env is the environment a command runs in.
+type env struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// errFailed is returned by a command that has already reported what failed.
+// It makes the process exit with 1 without printing more.
+var errFailed = fmt.Errorf("failed")
+
+// errUsage is returned if flags can not be parsed. The flag package has
+// already printed the error and usage.
+var errUsage = fmt.Errorf("usage")
+
+var commands = []*command{
+	{"build", "build a SlimTrie from key-value lines", runBuild},
+	{"stat", "print statistics and a size breakdown", runStat},
+	{"get", "get values of keys", runGet},
+	{"range", "get values of keys with RangeGet", runRange},
+	{"search", "search the left, equal and right values of keys", runSearch},
+	{"scan", "dump keys and values of a trie built with -complete", runScan},
+	{"verify", "check a trie against its source key-value lines", runVerify},
+	{"dump", "print the trie structure", runDump},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run runs a command and returns the exit code.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+
+	e := &env{stdin: stdin, stdout: stdout, stderr: stderr}
+
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		usage(stderr)
+		if len(args) == 0 {
+			return 2
+		}
+		return 0
+	}
+
+	for _, c := range commands {
+		if c.name != args[0] {
+			continue
+		}
+
+		err := c.run(e, args[1:])
+		switch {
+		case err == nil:
+			return 0
+		case err == flag.ErrHelp:
+			return 0
+		case err == errFailed:
+			return 1
+		case err == errUsage:
+			return 2
+		case isUsageError(err):
+			fmt.Fprintf(stderr, "slim %s: %v\n", c.name, err)
+			return 2
+		default:
+			fmt.Fprintf(stderr, "slim %s: %v\n", c.name, err)
+			return 1
+		}
+	}
+
+	fmt.Fprintf(stderr, "slim: unknown command: %q\n", args[0])
+	usage(stderr)
+	return 2
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintf(w, "Usage: slim <command> [flags] [args]\n\nCommands:\n")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-8s %s\n", c.name, c.usage)
+	}
+	fmt.Fprintf(w, "\nRun 'slim <command> -h' for flags of a command.\n")
+	fmt.Fprintf(w, "Value types: %s\n", strings.Join(valueTypeNames(), ", "))
+}
+
+// usageError is an error in command line arguments.
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+func usagef(format string, args ...interface{}) error {
+	return &usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+func isUsageError(err error) bool {
+	_, ok := err.(*usageError)
+	return ok
+}
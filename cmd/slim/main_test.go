@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/openacid/slim/trie"
+	"github.com/stretchr/testify/require"
+)
+
+// runCmd runs slim with args and returns the exit code, stdout and stderr.
+func runCmd(stdin string, args ...string) (int, string, string) {
+	stdout := bytes.NewBuffer(nil)
+	stderr := bytes.NewBuffer(nil)
+	code := run(args, strings.NewReader(stdin), stdout, stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	err := os.WriteFile(path, []byte(content), 0644)
+	require.NoError(t, err)
+	return path
+}
+
+const tsvInput = "banana\t2\napple\t1\ncherry\t3\ndate\t4\n"
+
+func TestBuildAndQuery(t *testing.T) {
+
+	ta := require.New(t)
+
+	dir := t.TempDir()
+	in := writeFile(t, dir, "in.tsv", tsvInput)
+	out := filepath.Join(dir, "a.slim")
+
+	code, _, stderr := runCmd("", "build", "-encoder", "u32", "-complete", "-meta", "owner=me", "-o", out, in)
+	ta.Equal(0, code, stderr)
+
+	b, err := os.ReadFile(out)
+	ta.NoError(err)
+	st, err := trie.Load(b)
+	ta.NoError(err)
+	ta.Equal(map[string][]byte{"owner": []byte("me")}, st.Metadata)
+	ta.True(*st.Opt().Complete)
+
+	cases := []struct {
+		args  []string
+		stdin string
+		want  string
+	}{
+		{[]string{"get", out, "apple", "zzz"}, "", "apple\t1\nzzz\t-\n"},
+		{[]string{"get", out}, "cherry\nbanana\n", "cherry\t3\nbanana\t2\n"},
+		{[]string{"range", out, "date"}, "", "date\t4\n"},
+		{[]string{"search", out, "b"}, "", "b\t1\t-\t2\n"},
+		{[]string{"scan", out}, "", "apple\t1\nbanana\t2\ncherry\t3\ndate\t4\n"},
+		{[]string{"scan", "-from", "b", "-limit", "2", out}, "", "banana\t2\ncherry\t3\n"},
+		{[]string{"verify", out, in}, "", "OK: 4 keys\n"},
+		{[]string{"verify", out}, tsvInput, "OK: 4 keys\n"},
+	}
+
+	for i, c := range cases {
+		code, stdout, stderr := runCmd(c.stdin, c.args...)
+		ta.Equal(0, code, "%d-th: %v: %s", i+1, c.args, stderr)
+		ta.Equal(c.want, stdout, "%d-th: %v", i+1, c.args)
+	}
+
+	code, stdout, _ := runCmd("", "stat", out)
+	ta.Equal(0, code)
+	for _, s := range []string{"encoder:   U32", "owner=me", "keys:      4", "fpr:       ", "Leaves"} {
+		ta.Contains(stdout, s)
+	}
+
+	absentPath := writeFile(t, dir, "absent.txt", "apple\nbanana\n")
+	code, stdout, _ = runCmd("", "stat", "-absent", absentPath, out)
+	ta.Equal(0, code)
+	ta.Contains(stdout, ", 1.000000 measured with 2 keys\n")
+
+	code, stdout, _ = runCmd("", "dump", out)
+	ta.Equal(0, code)
+	ta.Equal(st.String()+"\n", stdout)
+}
+
+func TestBuild_csvCompactAndFilter(t *testing.T) {
+
+	ta := require.New(t)
+
+	dir := t.TempDir()
+	in := writeFile(t, dir, "in.csv", "key,value\n\"b,1\",x\na,y\n")
+	out := filepath.Join(dir, "a.slim")
+
+	code, _, stderr := runCmd("", "build", "-format", "csv", "-skip-header", "-compact", "-o", out, in)
+	ta.Equal(0, code, stderr)
+
+	code, stdout, _ := runCmd("", "get", out, "a", "b,1")
+	ta.Equal(0, code)
+	ta.Equal("a\ty\nb,1\tx\n", stdout)
+
+	code, stdout, _ = runCmd("", "stat", out)
+	ta.Equal(0, code)
+	ta.Contains(stdout, "compact:   true")
+
+	code, stdout, _ = runCmd("", "verify", "-format", "csv", "-skip-header", out, in)
+	ta.Equal(0, code)
+	ta.Equal("OK: 2 keys\n", stdout)
+
+	// A trie without values from stdin
+	code, _, stderr = runCmd("b\na\n", "build", "-encoder", "none", "-o", out)
+	ta.Equal(0, code, stderr)
+
+	code, stdout, _ = runCmd("", "get", out, "a")
+	ta.Equal(0, code)
+	ta.Equal("a\t+\n", stdout)
+
+	code, _, stderr = runCmd("", "scan", out)
+	ta.Equal(1, code)
+	ta.Contains(stderr, "-complete")
+}
+
+func TestVerify_mismatch(t *testing.T) {
+
+	ta := require.New(t)
+
+	dir := t.TempDir()
+	in := writeFile(t, dir, "in.tsv", tsvInput)
+	out := filepath.Join(dir, "a.slim")
+
+	code, _, stderr := runCmd("", "build", "-encoder", "int", "-o", out, in)
+	ta.Equal(0, code, stderr)
+
+	code, stdout, _ := runCmd("apple\t1\nbanana\t5\n", "verify", out)
+	ta.Equal(1, code)
+	ta.Contains(stdout, `mismatch: line 2 key "banana": want 5 got 2`)
+	ta.Contains(stdout, "FAIL: 1 of 2 keys mismatch")
+}
+
+func TestErrors(t *testing.T) {
+
+	ta := require.New(t)
+
+	dir := t.TempDir()
+	out := filepath.Join(dir, "a.slim")
+
+	cases := []struct {
+		args    []string
+		stdin   string
+		code    int
+		wantErr string
+	}{
+		{nil, "", 2, "Usage"},
+		{[]string{"foo"}, "", 2, "unknown command"},
+		{[]string{"build", "-x"}, "", 2, "not defined"},
+		{[]string{"build"}, "", 2, "-o is required"},
+		{[]string{"build", "-encoder", "foo", "-o", out}, "", 2, "unknown value type"},
+		{[]string{"build", "-o", out}, "a\n", 1, "line 1: no tab"},
+		{[]string{"build", "-o", out}, "a\t1\na\t2\n", 1, "duplicated key"},
+		{[]string{"build", "-encoder", "u8", "-o", out}, "a\t256\n", 1, "invalid u8 value"},
+		{[]string{"build", "-format", "xml", "-o", out}, "", 1, "unknown format"},
+		{[]string{"get"}, "", 2, "no trie file"},
+		{[]string{"get", filepath.Join(dir, "nonexistent")}, "", 1, "no such file"},
+	}
+
+	for i, c := range cases {
+		code, _, stderr := runCmd(c.stdin, c.args...)
+		ta.Equal(c.code, code, "%d-th: %v", i+1, c.args)
+		ta.Contains(stderr, c.wantErr, "%d-th: %v", i+1, c.args)
+	}
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/openacid/slim/trie"
+)
+
+const (
+	// absent is printed for a key that is not found or a nil value.
+	absent = "-"
+
+	// present is printed for a key that is found in a trie without values.
+	present = "+"
+)
+
+// loadTrie loads a SlimTrie from a file with the encoder recorded in it.
+func loadTrie(path string) (*trie.SlimTrie, error) {
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	st, err := trie.Load(b)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return st, nil
+}
+
+// queryKeys returns the keys in args, or the lines of stdin if args is empty.
+func queryKeys(e *env, args []string) ([]string, error) {
+
+	if len(args) > 0 {
+		return args, nil
+	}
+
+	var keys []string
+	sc := bufio.NewScanner(e.stdin)
+	sc.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for sc.Scan() {
+		keys = append(keys, strings.TrimSuffix(sc.Text(), "\r"))
+	}
+	return keys, sc.Err()
+}
+
+func formatValue(v interface{}) string {
+	if v == nil {
+		return absent
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+func formatFound(v interface{}, found bool) string {
+	if !found {
+		return absent
+	}
+	if v == nil {
+		return present
+	}
+	return formatValue(v)
+}
+
+// runQuery loads the trie in the first argument and calls fn for every key.
+func runQuery(e *env, name string, args []string, fn func(st *trie.SlimTrie, key string) string) error {
+
+	fs := newFlagSet(e, name, "file.slim [key...]")
+	err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if fs.NArg() < 1 {
+		return usagef("no trie file")
+	}
+
+	st, err := loadTrie(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	keys, err := queryKeys(e, fs.Args()[1:])
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(e.stdout)
+	defer w.Flush()
+
+	for _, k := range keys {
+		fmt.Fprintf(w, "%s\t%s\n", k, fn(st, k))
+	}
+	return nil
+}
+
+func runGet(e *env, args []string) error {
+	return runQuery(e, "get", args, func(st *trie.SlimTrie, key string) string {
+		v, found := st.Get(key)
+		return formatFound(v, found)
+	})
+}
+
+func runRange(e *env, args []string) error {
+	return runQuery(e, "range", args, func(st *trie.SlimTrie, key string) string {
+		v, found := st.RangeGet(key)
+		return formatFound(v, found)
+	})
+}
+
+func runSearch(e *env, args []string) error {
+	return runQuery(e, "search", args, func(st *trie.SlimTrie, key string) string {
+		l, eq, r := st.Search(key)
+		return formatValue(l) + "\t" + formatValue(eq) + "\t" + formatValue(r)
+	})
+}
+
+func runScan(e *env, args []string) error {
+
+	var (
+		from  string
+		limit int
+	)
+
+	fs := newFlagSet(e, "scan", "[flags] file.slim")
+	fs.StringVar(&from, "from", "", "start from the first key >= this key")
+	fs.IntVar(&limit, "limit", 0, "max number of keys to print, 0 for no limit")
+
+	err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return usagef("expect one trie file but: %v", fs.Args())
+	}
+
+	st, err := loadTrie(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	c := st.Opt().Complete
+	if c == nil || !*c {
+		return fmt.Errorf("%s: scan requires a trie built with -complete", fs.Arg(0))
+	}
+
+	enc := st.Encoder()
+
+	w := bufio.NewWriter(e.stdout)
+	defer w.Flush()
+
+	n := 0
+	st.ScanFrom(from, true, enc != nil, func(k, v []byte) bool {
+		if enc == nil {
+			fmt.Fprintf(w, "%s\n", k)
+		} else {
+			_, val := enc.Decode(v)
+			fmt.Fprintf(w, "%s\t%s\n", k, formatValue(val))
+		}
+
+		n++
+		return limit == 0 || n < limit
+	})
+
+	return nil
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openacid/low/size"
+	"github.com/openacid/slim/encode"
+	"github.com/openacid/slim/trie"
+)
+
+// variant is an Opt combination to compare.
+type variant struct {
+	name string
+	opt  trie.Opt
+}
+
+var variants = []variant{
+	{"default", trie.Opt{}},
+	{"inner-prefix", trie.Opt{InnerPrefix: trie.Bool(true)}},
+	{"leaf-prefix", trie.Opt{LeafPrefix: trie.Bool(true)}},
+	{"complete", trie.Opt{Complete: trie.Bool(true)}},
+	{"no-dedup", trie.Opt{DedupValue: trie.Bool(false)}},
+}
+
+func variantNames() []string {
+	var names []string
+	for _, v := range variants {
+		names = append(names, v.name)
+	}
+	return names
+}
+
+func findVariants(names string) ([]variant, error) {
+
+	var rst []variant
+
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+
+		found := false
+		for _, v := range variants {
+			if v.name == name {
+				rst = append(rst, v)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown variant: %q, supported: %s", name, strings.Join(variantNames(), ","))
+		}
+	}
+
+	return rst, nil
+}
+
+// Result is the measurement of one variant.
+type Result struct {
+	Variant           string  `json:"variant" tw-title:"variant"`
+	KeyCnt            int     `json:"key_cnt" tw-title:"keys"`
+	Bytes             int     `json:"bytes" tw-title:"bytes"`
+	BitsPerKey        float64 `json:"bits_per_key" tw-title:"bits/key" tw-fmt:"%.2f"`
+	BuildMs           float64 `json:"build_ms" tw-title:"build-ms" tw-fmt:"%.1f"`
+	GetPresentNs      float64 `json:"get_present_ns" tw-title:"get-present-ns" tw-fmt:"%.1f"`
+	GetAbsentNs       float64 `json:"get_absent_ns" tw-title:"get-absent-ns" tw-fmt:"%.1f"`
+	RangeGetPresentNs float64 `json:"rangeget_present_ns" tw-title:"rangeget-present-ns" tw-fmt:"%.1f"`
+	RangeGetAbsentNs  float64 `json:"rangeget_absent_ns" tw-title:"rangeget-absent-ns" tw-fmt:"%.1f"`
+	AbsentCnt         int     `json:"absent_cnt" tw-title:"absent"`
+
+	// FPR is the ratio of absent keys that Get finds, from 0 to 1.
+	FPR float64 `json:"fpr" tw-title:"fpr" tw-fmt:"%.5f"`
+}
+
+// benchConfig is the input of a benchmark.
+type benchConfig struct {
+	keys   []string
+	absent []string
+
+	// dupRun is the number of adjacent keys sharing a value, to measure the
+	// effect of DedupValue.
+	dupRun int
+
+	// duration is the minimal time to run each query benchmark.
+	duration time.Duration
+}
+
+// values returns values of keys: every dupRun adjacent keys have the same
+// value.
+func (c *benchConfig) values() []uint32 {
+	vals := make([]uint32, len(c.keys))
+	for i := range vals {
+		vals[i] = uint32(i / c.dupRun)
+	}
+	return vals
+}
+
+// bench builds a SlimTrie with a variant and measures it.
+func bench(c *benchConfig, v variant) (*Result, error) {
+
+	vals := c.values()
+
+	start := time.Now()
+	st, err := trie.NewSlimTrie(encode.U32{}, c.keys, vals, v.opt)
+	if err != nil {
+		return nil, err
+	}
+	buildTime := time.Since(start)
+
+	r := &Result{
+		Variant:   v.name,
+		KeyCnt:    len(c.keys),
+		Bytes:     size.Of(st),
+		BuildMs:   float64(buildTime) / float64(time.Millisecond),
+		AbsentCnt: len(c.absent),
+	}
+	r.BitsPerKey = float64(r.Bytes) * 8 / float64(len(c.keys))
+
+	get := func(k string) bool {
+		_, found := st.Get(k)
+		return found
+	}
+	rangeGet := func(k string) bool {
+		_, found := st.RangeGet(k)
+		return found
+	}
+
+	r.GetPresentNs = nsPerOp(c.keys, c.duration, get)
+	r.RangeGetPresentNs = nsPerOp(c.keys, c.duration, rangeGet)
+
+	if len(c.absent) > 0 {
+		r.GetAbsentNs = nsPerOp(c.absent, c.duration, get)
+		r.RangeGetAbsentNs = nsPerOp(c.absent, c.duration, rangeGet)
+
+		fp := 0
+		for _, k := range c.absent {
+			if get(k) {
+				fp++
+			}
+		}
+		r.FPR = float64(fp) / float64(len(c.absent))
+	}
+
+	return r, nil
+}
+
+// sink keeps the results of queries from being optimized away.
+var sink int
+
+// nsPerOp runs fn on keys in a shuffled order, repeatedly for at least d, and
+// returns the average time per call.
+func nsPerOp(keys []string, d time.Duration, fn func(string) bool) float64 {
+
+	if len(keys) == 0 {
+		return 0
+	}
+
+	order := rand.New(rand.NewSource(0)).Perm(len(keys))
+	shuffled := make([]string, len(keys))
+	for i, j := range order {
+		shuffled[i] = keys[j]
+	}
+
+	n := 0
+	found := 0
+	start := time.Now()
+	for {
+		for _, k := range shuffled {
+			if fn(k) {
+				found++
+			}
+		}
+		n += len(shuffled)
+
+		if time.Since(start) >= d {
+			break
+		}
+	}
+	elapsed := time.Since(start)
+
+	sink += found
+	return float64(elapsed.Nanoseconds()) / float64(n)
+}
+
+// normalizeKeys sorts keys and removes duplicates.
+func normalizeKeys(keys []string) []string {
+
+	sort.Strings(keys)
+
+	rst := keys[:0]
+	for _, k := range keys {
+		if len(rst) > 0 && k == rst[len(rst)-1] {
+			continue
+		}
+		rst = append(rst, k)
+	}
+	return rst
+}
+
+// genAbsent generates n keys that are not in keys, by changing one byte of a
+// randomly chosen key.
+// Such keys share long prefixes with present keys, which is the worst case for
+// the false positive rate.
+func genAbsent(keys []string, n int, seed int64) []string {
+
+	present := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		present[k] = true
+	}
+
+	rnd := rand.New(rand.NewSource(seed))
+	rst := make([]string, 0, n)
+
+	for tries := 0; len(rst) < n && tries < n*100; tries++ {
+		k := []byte(keys[rnd.Intn(len(keys))])
+		if len(k) == 0 {
+			continue
+		}
+
+		k[rnd.Intn(len(k))] = byte(rnd.Intn(256))
+		s := string(k)
+		if present[s] {
+			continue
+		}
+		rst = append(rst, s)
+	}
+
+	return rst
+}
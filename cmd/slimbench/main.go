@@ -0,0 +1,187 @@
+// Command slimbench measures SlimTrie with user-supplied keys for several Opt
+// variants, to help choose an Opt before adopting it.
+//
+// Usage:
+//
+//	slimbench [flags] keys.txt
+//
+// The key file has one key per line.
+// Absent keys used to measure queries of absent keys and the false positive
+// rate are read from -absent, or generated by changing one byte of present
+// keys.
+//
+// For every variant it reports the size, bits per key, build time, ns/op of Get
+// and RangeGet for present and absent keys, and the false positive rate of Get.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/openacid/tablewriter"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+
+	fs := flag.NewFlagSet("slimbench", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: slimbench [flags] keys.txt\n")
+		fs.PrintDefaults()
+	}
+
+	var (
+		absentPath = fs.String("absent", "", "file of absent keys, one per line; generated from present keys if empty")
+		absentCnt  = fs.Int("absent-cnt", 0, "number of absent keys to generate, defaults to the number of keys")
+		names      = fs.String("variants", strings.Join(variantNames(), ","), "comma separated Opt variants to compare")
+		dupRun     = fs.Int("dup-run", 1, "number of adjacent keys sharing a value")
+		duration   = fs.Duration("duration", 200*time.Millisecond, "minimal time to run each query benchmark")
+		format     = fs.String("format", "table", "output format: table or json")
+		seed       = fs.Int64("seed", 0, "random seed to generate absent keys")
+	)
+
+	err := fs.Parse(args)
+	if err == flag.ErrHelp {
+		return 0
+	}
+	if err != nil {
+		return 2
+	}
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	if *format != "table" && *format != "json" {
+		fmt.Fprintf(stderr, "slimbench: unknown format: %q\n", *format)
+		return 2
+	}
+	if *dupRun < 1 {
+		fmt.Fprintf(stderr, "slimbench: -dup-run must be positive\n")
+		return 2
+	}
+
+	vs, err := findVariants(*names)
+	if err != nil {
+		fmt.Fprintf(stderr, "slimbench: %v\n", err)
+		return 2
+	}
+
+	keys, err := readKeys(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "slimbench: %v\n", err)
+		return 1
+	}
+	keys = normalizeKeys(keys)
+	if len(keys) == 0 {
+		fmt.Fprintf(stderr, "slimbench: no keys in %s\n", fs.Arg(0))
+		return 1
+	}
+
+	var absent []string
+	if *absentPath != "" {
+		absent, err = readKeys(*absentPath)
+		if err != nil {
+			fmt.Fprintf(stderr, "slimbench: %v\n", err)
+			return 1
+		}
+		absent = removePresent(absent, keys)
+	} else {
+		n := *absentCnt
+		if n == 0 {
+			n = len(keys)
+		}
+		absent = genAbsent(keys, n, *seed)
+	}
+
+	c := &benchConfig{
+		keys:     keys,
+		absent:   absent,
+		dupRun:   *dupRun,
+		duration: *duration,
+	}
+
+	var results []*Result
+	for _, v := range vs {
+		r, err := bench(c, v)
+		if err != nil {
+			fmt.Fprintf(stderr, "slimbench: %s: %v\n", v.name, err)
+			return 1
+		}
+		results = append(results, r)
+	}
+
+	if *format == "json" {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(results)
+		if err != nil {
+			fmt.Fprintf(stderr, "slimbench: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	writeTable(stdout, results)
+	return 0
+}
+
+// writeTable writes results in markdown table, the same as benchhelper
+// writes reports.
+func writeTable(w io.Writer, results []*Result) {
+	tb := tablewriter.NewWriter(w)
+	tb.SetAutoFormatHeaders(false)
+	tb.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
+	tb.SetCenterSeparator("|")
+	tb.SetContent(results)
+	tb.Render()
+}
+
+// readKeys reads non-empty lines from a file.
+func readKeys(path string) ([]string, error) {
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var keys []string
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for sc.Scan() {
+		k := strings.TrimSuffix(sc.Text(), "\r")
+		if k != "" {
+			keys = append(keys, k)
+		}
+	}
+
+	return keys, sc.Err()
+}
+
+// removePresent removes keys that are in present from absent.
+func removePresent(absent, present []string) []string {
+
+	m := make(map[string]bool, len(present))
+	for _, k := range present {
+		m[k] = true
+	}
+
+	rst := absent[:0]
+	for _, k := range absent {
+		if !m[k] {
+			rst = append(rst, k)
+		}
+	}
+	return rst
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeKeys(t *testing.T, dir, name string, keys []string) string {
+	path := filepath.Join(dir, name)
+	err := os.WriteFile(path, []byte(strings.Join(keys, "\n")+"\n"), 0644)
+	require.NoError(t, err)
+	return path
+}
+
+func TestRun(t *testing.T) {
+
+	ta := require.New(t)
+
+	dir := t.TempDir()
+
+	var keys, absent []string
+	for i := 0; i < 1000; i++ {
+		keys = append(keys, fmt.Sprintf("key-%05d", i*2))
+		absent = append(absent, fmt.Sprintf("key-%05d", i*2+1))
+	}
+	// duplicated and present keys are ignored
+	keys = append(keys, keys[0])
+	absent = append(absent, keys[1])
+
+	keyPath := writeKeys(t, dir, "keys.txt", keys)
+	absentPath := writeKeys(t, dir, "absent.txt", absent)
+
+	stdout := bytes.NewBuffer(nil)
+	stderr := bytes.NewBuffer(nil)
+	code := run([]string{"-duration", "1ms", "-format", "json", "-absent", absentPath, keyPath}, stdout, stderr)
+	ta.Equal(0, code, stderr.String())
+
+	var results []*Result
+	ta.NoError(json.Unmarshal(stdout.Bytes(), &results))
+	ta.Equal(len(variants), len(results))
+
+	for i, r := range results {
+		ta.Equal(variants[i].name, r.Variant)
+		ta.Equal(1000, r.KeyCnt)
+		ta.Equal(1000, r.AbsentCnt)
+		ta.True(r.Bytes > 0)
+		ta.InDelta(float64(r.Bytes)*8/1000, r.BitsPerKey, 1e-9)
+		ta.True(r.GetPresentNs > 0)
+		ta.True(r.GetAbsentNs > 0)
+		ta.True(r.FPR >= 0 && r.FPR <= 1)
+	}
+
+	ta.Equal("complete", results[3].Variant)
+	ta.Equal(float64(0), results[3].FPR)
+
+	// table output with generated absent keys
+	stdout.Reset()
+	code = run([]string{"-duration", "1ms", "-variants", "default,complete", keyPath}, stdout, stderr)
+	ta.Equal(0, code, stderr.String())
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	ta.Equal(4, len(lines))
+	ta.Contains(lines[0], "bits/key")
+	ta.Contains(lines[2], "default")
+	ta.Contains(lines[3], "complete")
+}
+
+func TestRun_errors(t *testing.T) {
+
+	ta := require.New(t)
+
+	dir := t.TempDir()
+	keyPath := writeKeys(t, dir, "keys.txt", []string{"a", "b"})
+
+	cases := []struct {
+		args []string
+		code int
+		want string
+	}{
+		{nil, 2, "Usage"},
+		{[]string{"-variants", "foo", keyPath}, 2, "unknown variant"},
+		{[]string{"-format", "xml", keyPath}, 2, "unknown format"},
+		{[]string{"-dup-run", "0", keyPath}, 2, "-dup-run"},
+		{[]string{filepath.Join(dir, "nonexistent")}, 1, "no such file"},
+	}
+
+	for i, c := range cases {
+		stderr := bytes.NewBuffer(nil)
+		code := run(c.args, bytes.NewBuffer(nil), stderr)
+		ta.Equal(c.code, code, "%d-th: %v", i+1, c.args)
+		ta.Contains(stderr.String(), c.want, "%d-th: %v", i+1, c.args)
+	}
+}
+
+func TestGenAbsent(t *testing.T) {
+
+	ta := require.New(t)
+
+	keys := []string{"abc", "abd", "b", ""}
+	absent := genAbsent(keys, 100, 1)
+	ta.Equal(100, len(absent))
+
+	for _, k := range absent {
+		ta.NotContains(keys, k)
+	}
+
+	ta.Equal([]string{"a", "b", "c"}, normalizeKeys([]string{"c", "a", "b", "a", "c", "c"}))
+}
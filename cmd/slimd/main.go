@@ -0,0 +1,80 @@
+// Command slimd serves marshaled SlimTries over HTTP in JSON, for services
+// that can not link the Go package.
+//
+// Usage:
+//
+//	slimd [-addr :8080] [-reload 5s] [name=]path...
+//
+// A trie is served by name, which defaults to the base name of its path
+// without extension.
+// A trie file must be written by Marshal or MarshalCompact of version 0.5.13 or
+// later, which records the value encoder.
+//
+// Files are checked every -reload interval and reloaded if their modification
+// time or size changes.
+// Replace a file atomically, e.g., by renaming a new file onto it, so that a
+// partially written file is never loaded.
+// If reloading fails the previously loaded trie keeps serving.
+//
+// See server.handler for endpoints.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stderr))
+}
+
+func run(args []string, stderr io.Writer) int {
+
+	fs := flag.NewFlagSet("slimd", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: slimd [flags] [name=]path...\n")
+		fs.PrintDefaults()
+	}
+
+	addr := fs.String("addr", ":8080", "address to listen on")
+	interval := fs.Duration("reload", 5*time.Second, "interval to check files for reloading, 0 to disable")
+
+	err := fs.Parse(args)
+	if err == flag.ErrHelp {
+		return 0
+	}
+	if err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	s, err := newServer(fs.Args())
+	if err != nil {
+		fmt.Fprintf(stderr, "slimd: %v\n", err)
+		return 2
+	}
+
+	// A trie that fails to load at start is retried by the next reload.
+	err = s.reload()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+	}
+
+	if *interval > 0 {
+		go s.watch(*interval, make(chan struct{}), stderr)
+	}
+
+	fmt.Fprintf(stderr, "slimd: serving %d tries on %s\n", len(s.names), *addr)
+
+	err = http.ListenAndServe(*addr, s.handler())
+	fmt.Fprintf(stderr, "slimd: %v\n", err)
+	return 1
+}
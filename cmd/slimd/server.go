@@ -0,0 +1,589 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/openacid/slim/trie"
+)
+
+const (
+	defaultScanLimit = 100
+	maxScanLimit     = 10000
+	maxBatchKeys     = 10000
+	maxBodySize      = 64 << 20
+)
+
+// source is a trie file served by name.
+type source struct {
+	name string
+	path string
+
+	// the following fields are protected by server.mu
+
+	st       *trie.SlimTrie
+	modTime  time.Time
+	size     int64
+	loadedAt time.Time
+	err      error
+}
+
+// server serves SlimTries loaded from files.
+// Every file is reloaded by reload if its modification time or size changes.
+// If reloading fails, the previously loaded trie is kept serving.
+type server struct {
+	mu      sync.RWMutex
+	sources map[string]*source
+	names   []string
+
+	metrics metrics
+}
+
+// metrics are counters exported by /metrics.
+type metrics struct {
+	mu       sync.Mutex
+	requests map[string]int64
+	errors   map[string]int64
+
+	reloads      int64
+	reloadErrors int64
+}
+
+func (m *metrics) inc(endpoint string, failed bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.requests[endpoint]++
+	if failed {
+		m.errors[endpoint]++
+	}
+}
+
+// newServer creates a server of sources in form of "name=path" or "path".
+// A name defaults to the base name of path without extension.
+func newServer(specs []string) (*server, error) {
+
+	s := &server{
+		sources: map[string]*source{},
+		metrics: metrics{
+			requests: map[string]int64{},
+			errors:   map[string]int64{},
+		},
+	}
+
+	for _, spec := range specs {
+		name, path := parseSpec(spec)
+		if name == "" || strings.Contains(name, "/") {
+			return nil, fmt.Errorf("invalid name in: %q", spec)
+		}
+		if _, ok := s.sources[name]; ok {
+			return nil, fmt.Errorf("duplicated name: %q", name)
+		}
+
+		s.sources[name] = &source{name: name, path: path}
+		s.names = append(s.names, name)
+	}
+
+	sort.Strings(s.names)
+
+	return s, nil
+}
+
+func parseSpec(spec string) (string, string) {
+	i := strings.IndexByte(spec, '=')
+	if i >= 0 {
+		return spec[:i], spec[i+1:]
+	}
+
+	base := spec[strings.LastIndexByte(spec, '/')+1:]
+	if j := strings.LastIndexByte(base, '.'); j > 0 {
+		base = base[:j]
+	}
+	return base, spec
+}
+
+// reload loads every source whose file has changed since last load.
+// It returns the first error it encounters.
+func (s *server) reload() error {
+
+	var firstErr error
+
+	for _, name := range s.names {
+		err := s.reloadSource(s.sources[name])
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
+
+func (s *server) reloadSource(src *source) error {
+
+	fi, err := os.Stat(src.path)
+	if err != nil {
+		return s.setError(src, err)
+	}
+
+	s.mu.RLock()
+	unchanged := src.st != nil && fi.ModTime().Equal(src.modTime) && fi.Size() == src.size
+	s.mu.RUnlock()
+
+	if unchanged {
+		return nil
+	}
+
+	b, err := os.ReadFile(src.path)
+	if err != nil {
+		return s.setError(src, err)
+	}
+
+	st, err := trie.Load(b)
+	if err != nil {
+		return s.setError(src, err)
+	}
+
+	// ExpectedFPR walks through every node the first time and is cached in
+	// st. Compute it before serving so that /stat and /metrics do not.
+	st.ExpectedFPR()
+
+	atomic.AddInt64(&s.metrics.reloads, 1)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	src.st = st
+	src.modTime = fi.ModTime()
+	src.size = fi.Size()
+	src.loadedAt = time.Now()
+	src.err = nil
+
+	return nil
+}
+
+func (s *server) setError(src *source, err error) error {
+
+	atomic.AddInt64(&s.metrics.reloadErrors, 1)
+	err = fmt.Errorf("failed to load %s from %s: %v", src.name, src.path, err)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	src.err = err
+	return err
+}
+
+// watch reloads changed files every interval until stop is closed.
+func (s *server) watch(interval time.Duration, stop <-chan struct{}, logw io.Writer) {
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-t.C:
+			err := s.reload()
+			if err != nil {
+				fmt.Fprintln(logw, err)
+			}
+		}
+	}
+}
+
+// getTrie returns the currently loaded trie by name.
+func (s *server) getTrie(name string) (*trie.SlimTrie, error) {
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	src := s.sources[name]
+	if src == nil {
+		return nil, &httpError{http.StatusNotFound, fmt.Sprintf("no such trie: %q", name)}
+	}
+	if src.st == nil {
+		return nil, &httpError{http.StatusServiceUnavailable, fmt.Sprintf("trie is not loaded: %q", name)}
+	}
+
+	return src.st, nil
+}
+
+// httpError is an error with an HTTP status code.
+type httpError struct {
+	code int
+	msg  string
+}
+
+func (e *httpError) Error() string {
+	return e.msg
+}
+
+func badRequest(format string, args ...interface{}) error {
+	return &httpError{http.StatusBadRequest, fmt.Sprintf(format, args...)}
+}
+
+// handler returns the HTTP handler of all endpoints:
+//
+//	GET  /healthz
+//	GET  /metrics
+//	GET  /tries
+//	GET  /tries/<name>/get?key=<key>
+//	GET  /tries/<name>/range?key=<key>
+//	GET  /tries/<name>/search?key=<key>
+//	GET  /tries/<name>/scan?from=<key>&to=<key>&include_to=<bool>&limit=<n>
+//	POST /tries/<name>/batch-get with body {"keys": [...]}
+//	GET  /tries/<name>/stat
+func (s *server) handler() http.Handler {
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", s.handleHealth)
+	mux.HandleFunc("/metrics", s.handleMetrics)
+	mux.HandleFunc("/tries", s.wrap("list", s.handleList))
+	mux.HandleFunc("/tries/", s.handleTrie)
+
+	return mux
+}
+
+type trieHandler func(st *trie.SlimTrie, r *http.Request) (interface{}, error)
+
+// trieHandlers are handlers of /tries/<name>/<op> by op.
+var trieHandlers = map[string]trieHandler{
+	"get":       handleGet,
+	"range":     handleRange,
+	"search":    handleSearch,
+	"scan":      handleScan,
+	"batch-get": handleBatchGet,
+	"stat":      handleStat,
+}
+
+func (s *server) handleTrie(w http.ResponseWriter, r *http.Request) {
+
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/tries/"), "/")
+	if len(parts) != 2 {
+		s.writeError(w, "unknown", &httpError{http.StatusNotFound, "not found: " + r.URL.Path})
+		return
+	}
+
+	name, op := parts[0], parts[1]
+
+	h := trieHandlers[op]
+	if h == nil {
+		s.writeError(w, "unknown", &httpError{http.StatusNotFound, "unknown operation: " + op})
+		return
+	}
+
+	s.wrap(op, func(r *http.Request) (interface{}, error) {
+
+		method := http.MethodGet
+		if op == "batch-get" {
+			method = http.MethodPost
+		}
+		if r.Method != method {
+			return nil, &httpError{http.StatusMethodNotAllowed, "method not allowed: " + r.Method}
+		}
+
+		st, err := s.getTrie(name)
+		if err != nil {
+			return nil, err
+		}
+		return h(st, r)
+	})(w, r)
+}
+
+// wrap converts a function returning a JSON body to an http.HandlerFunc and
+// counts requests.
+func (s *server) wrap(endpoint string, fn func(r *http.Request) (interface{}, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		body, err := fn(r)
+		if err != nil {
+			s.writeError(w, endpoint, err)
+			return
+		}
+
+		s.metrics.inc(endpoint, false)
+		writeJSON(w, http.StatusOK, body)
+	}
+}
+
+func (s *server) writeError(w http.ResponseWriter, endpoint string, err error) {
+
+	s.metrics.inc(endpoint, true)
+
+	code := http.StatusInternalServerError
+	if he, ok := err.(*httpError); ok {
+		code = he.code
+	}
+
+	writeJSON(w, code, map[string]string{"error": err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, code int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(body)
+}
+
+func (s *server) handleHealth(w http.ResponseWriter, r *http.Request) {
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var notLoaded []string
+	for _, name := range s.names {
+		if s.sources[name].st == nil {
+			notLoaded = append(notLoaded, name)
+		}
+	}
+
+	if len(notLoaded) > 0 {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]interface{}{
+			"status":     "unavailable",
+			"not_loaded": notLoaded,
+		})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// handleMetrics writes metrics in Prometheus text format.
+func (s *server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+	s.metrics.mu.Lock()
+	var endpoints []string
+	for ep := range s.metrics.requests {
+		endpoints = append(endpoints, ep)
+	}
+	sort.Strings(endpoints)
+
+	fmt.Fprintf(w, "# TYPE slimd_requests_total counter\n")
+	for _, ep := range endpoints {
+		fmt.Fprintf(w, "slimd_requests_total{endpoint=%q} %d\n", ep, s.metrics.requests[ep])
+	}
+	fmt.Fprintf(w, "# TYPE slimd_request_errors_total counter\n")
+	for _, ep := range endpoints {
+		fmt.Fprintf(w, "slimd_request_errors_total{endpoint=%q} %d\n", ep, s.metrics.errors[ep])
+	}
+	s.metrics.mu.Unlock()
+
+	fmt.Fprintf(w, "# TYPE slimd_reloads_total counter\n")
+	fmt.Fprintf(w, "slimd_reloads_total %d\n", atomic.LoadInt64(&s.metrics.reloads))
+	fmt.Fprintf(w, "# TYPE slimd_reload_errors_total counter\n")
+	fmt.Fprintf(w, "slimd_reload_errors_total %d\n", atomic.LoadInt64(&s.metrics.reloadErrors))
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	fmt.Fprintf(w, "# TYPE slimd_trie_loaded gauge\n")
+	for _, name := range s.names {
+		loaded := 0
+		if s.sources[name].st != nil {
+			loaded = 1
+		}
+		fmt.Fprintf(w, "slimd_trie_loaded{trie=%q} %d\n", name, loaded)
+	}
+	fmt.Fprintf(w, "# TYPE slimd_trie_loaded_timestamp_seconds gauge\n")
+	for _, name := range s.names {
+		src := s.sources[name]
+		if src.st != nil {
+			fmt.Fprintf(w, "slimd_trie_loaded_timestamp_seconds{trie=%q} %d\n", name, src.loadedAt.Unix())
+		}
+	}
+	fmt.Fprintf(w, "# TYPE slimd_trie_expected_fpr gauge\n")
+	for _, name := range s.names {
+		src := s.sources[name]
+		if src.st != nil {
+			fmt.Fprintf(w, "slimd_trie_expected_fpr{trie=%q} %g\n", name, src.st.ExpectedFPR())
+		}
+	}
+}
+
+type trieInfo struct {
+	Name     string `json:"name"`
+	Path     string `json:"path"`
+	Loaded   bool   `json:"loaded"`
+	LoadedAt string `json:"loaded_at,omitempty"`
+	Version  string `json:"version,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+func (s *server) handleList(r *http.Request) (interface{}, error) {
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	infos := []trieInfo{}
+	for _, name := range s.names {
+		src := s.sources[name]
+		info := trieInfo{Name: name, Path: src.path, Loaded: src.st != nil}
+		if src.st != nil {
+			info.LoadedAt = src.loadedAt.UTC().Format(time.RFC3339)
+			info.Version = src.st.GetVersion()
+		}
+		if src.err != nil {
+			info.Error = src.err.Error()
+		}
+		infos = append(infos, info)
+	}
+
+	return map[string]interface{}{"tries": infos}, nil
+}
+
+type getResult struct {
+	Key   string      `json:"key"`
+	Found bool        `json:"found"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+func requireKey(r *http.Request) (string, error) {
+	q := r.URL.Query()
+	if _, ok := q["key"]; !ok {
+		return "", badRequest("missing query parameter: key")
+	}
+	return q.Get("key"), nil
+}
+
+func handleGet(st *trie.SlimTrie, r *http.Request) (interface{}, error) {
+	key, err := requireKey(r)
+	if err != nil {
+		return nil, err
+	}
+	v, found := st.Get(key)
+	return getResult{Key: key, Found: found, Value: v}, nil
+}
+
+func handleRange(st *trie.SlimTrie, r *http.Request) (interface{}, error) {
+	key, err := requireKey(r)
+	if err != nil {
+		return nil, err
+	}
+	v, found := st.RangeGet(key)
+	return getResult{Key: key, Found: found, Value: v}, nil
+}
+
+func handleSearch(st *trie.SlimTrie, r *http.Request) (interface{}, error) {
+	key, err := requireKey(r)
+	if err != nil {
+		return nil, err
+	}
+	l, eq, rt := st.Search(key)
+	return map[string]interface{}{
+		"key":   key,
+		"left":  l,
+		"equal": eq,
+		"right": rt,
+	}, nil
+}
+
+type batchGetRequest struct {
+	Keys []string `json:"keys"`
+}
+
+func handleBatchGet(st *trie.SlimTrie, r *http.Request) (interface{}, error) {
+
+	req := batchGetRequest{}
+	err := json.NewDecoder(io.LimitReader(r.Body, maxBodySize)).Decode(&req)
+	if err != nil {
+		return nil, badRequest("invalid body: %v", err)
+	}
+	if len(req.Keys) > maxBatchKeys {
+		return nil, badRequest("too many keys: %d > %d", len(req.Keys), maxBatchKeys)
+	}
+
+	results := make([]getResult, len(req.Keys))
+	for i, k := range req.Keys {
+		v, found := st.Get(k)
+		results[i] = getResult{Key: k, Found: found, Value: v}
+	}
+
+	return map[string]interface{}{"results": results}, nil
+}
+
+type scanItem struct {
+	Key   string      `json:"key"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// handleScan returns at most limit keys in [from, to).
+// If there are more keys, "next" is the key to start the next page from, which
+// is passed as `after` in the next request.
+func handleScan(st *trie.SlimTrie, r *http.Request) (interface{}, error) {
+
+	c := st.Opt().Complete
+	if c == nil || !*c {
+		return nil, badRequest("scan requires a trie created with Opt.Complete")
+	}
+
+	q := r.URL.Query()
+
+	from, includeFrom := q.Get("from"), true
+	if _, ok := q["after"]; ok {
+		from, includeFrom = q.Get("after"), false
+	}
+
+	limit := defaultScanLimit
+	if l := q.Get("limit"); l != "" {
+		n, err := strconv.Atoi(l)
+		if err != nil || n <= 0 || n > maxScanLimit {
+			return nil, badRequest("limit must be in [1, %d]: %q", maxScanLimit, l)
+		}
+		limit = n
+	}
+
+	includeTo := false
+	if s := q.Get("include_to"); s != "" {
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, badRequest("invalid include_to: %q", s)
+		}
+		includeTo = b
+	}
+
+	enc := st.Encoder()
+	items := []scanItem{}
+	more := false
+
+	fn := func(k, v []byte) bool {
+		if len(items) == limit {
+			more = true
+			return false
+		}
+
+		item := scanItem{Key: string(k)}
+		if enc != nil {
+			_, item.Value = enc.Decode(v)
+		}
+		items = append(items, item)
+		return true
+	}
+
+	if _, ok := q["to"]; ok {
+		st.ScanFromTo(from, includeFrom, q.Get("to"), includeTo, enc != nil, fn)
+	} else {
+		st.ScanFrom(from, includeFrom, enc != nil, fn)
+	}
+
+	resp := map[string]interface{}{"items": items}
+	if more {
+		resp["next"] = items[len(items)-1].Key
+	}
+
+	return resp, nil
+}
+
+func handleStat(st *trie.SlimTrie, r *http.Request) (interface{}, error) {
+	return st.Stat(), nil
+}
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openacid/slim/encode"
+	"github.com/openacid/slim/trie"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTrie builds a trie and replaces the file at path atomically.
+func writeTrie(t *testing.T, path string, keys []string, values []int32, opts ...trie.Opt) {
+
+	st, err := trie.NewSlimTrie(encode.I32{}, keys, values, opts...)
+	require.NoError(t, err)
+
+	b, err := st.Marshal()
+	require.NoError(t, err)
+
+	tmp := path + ".tmp"
+	require.NoError(t, os.WriteFile(tmp, b, 0644))
+	require.NoError(t, os.Rename(tmp, path))
+}
+
+func doJSON(t *testing.T, method, u string, body string) (int, map[string]interface{}) {
+
+	req, err := http.NewRequest(method, u, strings.NewReader(body))
+	require.NoError(t, err)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	m := map[string]interface{}{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&m))
+	return resp.StatusCode, m
+}
+
+func newTestServer(t *testing.T) (*server, *httptest.Server, string) {
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "abc.slim")
+
+	writeTrie(t, path,
+		[]string{"abc", "abd", "bcd", "cde", "cdf"},
+		[]int32{1, 2, 3, 4, 5},
+		trie.Opt{Complete: trie.Bool(true)})
+
+	s, err := newServer([]string{path, "missing=" + filepath.Join(dir, "missing.slim")})
+	require.NoError(t, err)
+
+	err = s.reload()
+	require.Error(t, err, "missing.slim does not exist")
+
+	ts := httptest.NewServer(s.handler())
+	t.Cleanup(ts.Close)
+
+	return s, ts, path
+}
+
+func TestServer_queries(t *testing.T) {
+
+	ta := require.New(t)
+
+	_, ts, _ := newTestServer(t)
+	u := ts.URL + "/tries/abc/"
+
+	cases := []struct {
+		method string
+		path   string
+		body   string
+		code   int
+		want   string
+	}{
+		{"GET", "get?key=abd", "", 200, `{"found":true,"key":"abd","value":2}`},
+		{"GET", "get?key=abz", "", 200, `{"found":false,"key":"abz"}`},
+		{"GET", "range?key=abz", "", 200, `{"found":true,"key":"abz","value":2}`},
+		{"GET", "search?key=abz", "", 200, `{"equal":null,"key":"abz","left":2,"right":3}`},
+		{"POST", "batch-get", `{"keys":["abc","x"]}`, 200,
+			`{"results":[{"found":true,"key":"abc","value":1},{"found":false,"key":"x"}]}`},
+		{"GET", "scan?from=abd&to=cdf", "", 200,
+			`{"items":[{"key":"abd","value":2},{"key":"bcd","value":3},{"key":"cde","value":4}]}`},
+		{"GET", "scan?from=abd&to=cdf&include_to=true&limit=2", "", 200,
+			`{"items":[{"key":"abd","value":2},{"key":"bcd","value":3}],"next":"bcd"}`},
+		{"GET", "scan?after=bcd&limit=2", "", 200,
+			`{"items":[{"key":"cde","value":4},{"key":"cdf","value":5}]}`},
+		{"GET", "get", "", 400, `{"error":"missing query parameter: key"}`},
+		{"GET", "scan?limit=0", "", 400, `{"error":"limit must be in [1, 10000]: \"0\""}`},
+		{"GET", "batch-get", "", 405, `{"error":"method not allowed: GET"}`},
+		{"POST", "batch-get", `{`, 400, `{"error":"invalid body: unexpected EOF"}`},
+		{"GET", "foo", "", 404, `{"error":"unknown operation: foo"}`},
+	}
+
+	for i, c := range cases {
+		code, body := doJSON(t, c.method, u+c.path, c.body)
+		got, err := json.Marshal(body)
+		ta.NoError(err)
+
+		ta.Equal(c.code, code, "%d-th: %s", i+1, c.path)
+		ta.Equal(c.want, string(got), "%d-th: %s", i+1, c.path)
+	}
+
+	code, body := doJSON(t, "GET", u+"stat", "")
+	ta.Equal(200, code)
+	ta.Equal(float64(5), body["KeyCnt"])
+
+	code, body = doJSON(t, "GET", ts.URL+"/tries/missing/get?key=a", "")
+	ta.Equal(503, code)
+	ta.Contains(body["error"], "not loaded")
+
+	code, _ = doJSON(t, "GET", ts.URL+"/tries/nonexistent/get?key=a", "")
+	ta.Equal(404, code)
+
+	code, body = doJSON(t, "GET", ts.URL+"/tries", "")
+	ta.Equal(200, code)
+	tries := body["tries"].([]interface{})
+	ta.Equal(2, len(tries))
+	ta.Equal(true, tries[0].(map[string]interface{})["loaded"])
+	ta.Contains(tries[1].(map[string]interface{})["error"], "missing.slim")
+}
+
+func TestServer_scanIncomplete(t *testing.T) {
+
+	ta := require.New(t)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a.slim")
+	writeTrie(t, path, []string{"a", "b"}, []int32{1, 2})
+
+	s, err := newServer([]string{path})
+	ta.NoError(err)
+	ta.NoError(s.reload())
+
+	ts := httptest.NewServer(s.handler())
+	defer ts.Close()
+
+	code, body := doJSON(t, "GET", ts.URL+"/tries/a/scan", "")
+	ta.Equal(400, code)
+	ta.Contains(body["error"], "Complete")
+}
+
+func TestServer_reload(t *testing.T) {
+
+	ta := require.New(t)
+
+	s, ts, path := newTestServer(t)
+	u := ts.URL + "/tries/abc/get?key=" + url.QueryEscape("abc")
+
+	_, body := doJSON(t, "GET", u, "")
+	ta.Equal(float64(1), body["value"])
+
+	// Make sure the modification time changes.
+	time.Sleep(10 * time.Millisecond)
+	writeTrie(t, path, []string{"abc"}, []int32{100}, trie.Opt{Complete: trie.Bool(true)})
+
+	ta.Error(s.reload())
+
+	_, body = doJSON(t, "GET", u, "")
+	ta.Equal(float64(100), body["value"])
+
+	// A broken file does not replace the loaded trie.
+	time.Sleep(10 * time.Millisecond)
+	ta.NoError(os.WriteFile(path, []byte("broken"), 0644))
+
+	err := s.reload()
+	ta.Error(err)
+	ta.Contains(err.Error(), "abc")
+
+	_, body = doJSON(t, "GET", u, "")
+	ta.Equal(float64(100), body["value"])
+
+	// The missing trie is loaded once the file appears.
+	writeTrie(t, filepath.Join(filepath.Dir(path), "missing.slim"), []string{"x"}, []int32{7})
+	s.reload()
+
+	_, body = doJSON(t, "GET", ts.URL+"/tries/missing/get?key=x", "")
+	ta.Equal(float64(7), body["value"])
+
+	// watch reloads in background
+	time.Sleep(10 * time.Millisecond)
+	writeTrie(t, path, []string{"abc"}, []int32{200}, trie.Opt{Complete: trie.Bool(true)})
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go s.watch(time.Millisecond, stop, io.Discard)
+
+	ta.Eventually(func() bool {
+		_, body := doJSON(t, "GET", u, "")
+		return body["value"] == float64(200)
+	}, 5*time.Second, 5*time.Millisecond)
+}
+
+func TestServer_healthAndMetrics(t *testing.T) {
+
+	ta := require.New(t)
+
+	s, ts, path := newTestServer(t)
+
+	code, body := doJSON(t, "GET", ts.URL+"/healthz", "")
+	ta.Equal(503, code)
+	ta.Equal([]interface{}{"missing"}, body["not_loaded"])
+
+	writeTrie(t, filepath.Join(filepath.Dir(path), "missing.slim"), []string{"x"}, []int32{7})
+	ta.NoError(s.reload())
+
+	code, body = doJSON(t, "GET", ts.URL+"/healthz", "")
+	ta.Equal(200, code)
+	ta.Equal("ok", body["status"])
+
+	doJSON(t, "GET", ts.URL+"/tries/abc/get?key=a", "")
+	doJSON(t, "GET", ts.URL+"/tries/abc/get", "")
+
+	resp, err := http.Get(ts.URL + "/metrics")
+	ta.NoError(err)
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	ta.NoError(err)
+
+	for _, s := range []string{
+		`slimd_requests_total{endpoint="get"} 2`,
+		`slimd_request_errors_total{endpoint="get"} 1`,
+		`slimd_reloads_total 2`,
+		`slimd_reload_errors_total 1`,
+		`slimd_trie_loaded{trie="abc"} 1`,
+		`slimd_trie_loaded{trie="missing"} 1`,
+		"slimd_trie_expected_fpr{trie=\"abc\"} 0\n",
+	} {
+		ta.Contains(string(b), s)
+	}
+}
+
+func TestNewServer(t *testing.T) {
+
+	ta := require.New(t)
+
+	s, err := newServer([]string{"a/b/foo.slim", "bar=x.slim", "c/.hidden"})
+	ta.NoError(err)
+	ta.Equal([]string{".hidden", "bar", "foo"}, s.names)
+	ta.Equal("a/b/foo.slim", s.sources["foo"].path)
+	ta.Equal("x.slim", s.sources["bar"].path)
+
+	_, err = newServer([]string{"a.slim", "b/a.slim"})
+	ta.Error(err)
+
+	_, err = newServer([]string{"=a.slim"})
+	ta.Error(err)
+}
@@ -0,0 +1,85 @@
+// Package container stores several named SlimTries in a single file, so that
+// related indexes can be published and swapped together.
+//
+// A container is laid out as:
+//
+//	section 0: a marshaled SlimTrie
+//	section 1: a marshaled SlimTrie
+//	...
+//	directory: ContainerDirectory in protobuf
+//	trailer:   24 bytes, see trailer
+//
+// The directory records the name, offset, size, CRC32C checksum and encoder of
+// every section.
+// A Reader reads only the trailer and the directory when opening a container,
+// and loads a section when it is asked for.
+//
+// Since 0.5.13
+package container
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+
+	"github.com/openacid/errors"
+)
+
+// containerVersion is the version of the container format.
+const containerVersion = "0.5.13"
+
+// trailerMagic identifies a container file.
+var trailerMagic = [4]byte{'S', 'L', 'M', 'C'}
+
+var crc32cTable = crc32.MakeTable(crc32.Castagnoli)
+
+// trailer is the fixed-size end of a container, which locates the directory.
+type trailer struct {
+	// DirOffset is the position of the directory.
+	DirOffset uint64
+
+	// DirSize is the number of bytes of the directory.
+	DirSize uint64
+
+	// DirCRC32C is the checksum of the directory.
+	DirCRC32C uint32
+
+	Magic [4]byte
+}
+
+// trailerSize is the number of bytes of a marshaled trailer.
+var trailerSize = int64(binary.Size(trailer{}))
+
+func (t *trailer) marshal() []byte {
+	b := bytes.NewBuffer(make([]byte, 0, trailerSize))
+	// Writing a fixed-size struct to a bytes.Buffer never fails.
+	_ = binary.Write(b, binary.LittleEndian, t)
+	return b.Bytes()
+}
+
+func (t *trailer) unmarshal(b []byte) error {
+
+	err := binary.Read(bytes.NewReader(b), binary.LittleEndian, t)
+	if err != nil {
+		return errors.Wrapf(ErrNotContainer, "failed to read trailer: %v", err)
+	}
+
+	if t.Magic != trailerMagic {
+		return errors.Wrapf(ErrNotContainer, "magic: %q", t.Magic[:])
+	}
+
+	return nil
+}
+
+// countingWriter counts the bytes written to w.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
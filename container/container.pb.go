@@ -0,0 +1,202 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: container.proto
+
+package container
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+// ContainerDirectory lists the sections in a container.
+// It is written after all sections.
+//
+// Since 0.5.13
+type ContainerDirectory struct {
+	// Version is the version of the container format.
+	//
+	// Since 0.5.13
+	Version string `protobuf:"bytes,10,opt,name=Version,proto3" json:"Version,omitempty"`
+	// Sections in the order they are added.
+	//
+	// Since 0.5.13
+	Sections             []*ContainerSection `protobuf:"bytes,20,rep,name=Sections,proto3" json:"Sections,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *ContainerDirectory) Reset()         { *m = ContainerDirectory{} }
+func (m *ContainerDirectory) String() string { return proto.CompactTextString(m) }
+func (*ContainerDirectory) ProtoMessage()    {}
+func (*ContainerDirectory) Descriptor() ([]byte, []int) {
+	return fileDescriptor_container_b8fed261ae760f7c, []int{0}
+}
+func (m *ContainerDirectory) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ContainerDirectory.Unmarshal(m, b)
+}
+func (m *ContainerDirectory) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ContainerDirectory.Marshal(b, m, deterministic)
+}
+func (dst *ContainerDirectory) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ContainerDirectory.Merge(dst, src)
+}
+func (m *ContainerDirectory) XXX_Size() int {
+	return xxx_messageInfo_ContainerDirectory.Size(m)
+}
+func (m *ContainerDirectory) XXX_DiscardUnknown() {
+	xxx_messageInfo_ContainerDirectory.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ContainerDirectory proto.InternalMessageInfo
+
+func (m *ContainerDirectory) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *ContainerDirectory) GetSections() []*ContainerSection {
+	if m != nil {
+		return m.Sections
+	}
+	return nil
+}
+
+// ContainerSection describes a marshaled SlimTrie in a container.
+//
+// Since 0.5.13
+type ContainerSection struct {
+	// Name is the unique name of the section.
+	//
+	// Since 0.5.13
+	Name string `protobuf:"bytes,10,opt,name=Name,proto3" json:"Name,omitempty"`
+	// Offset is the position of the section from the start of the container.
+	//
+	// Since 0.5.13
+	Offset int64 `protobuf:"varint,11,opt,name=Offset,proto3" json:"Offset,omitempty"`
+	// Size is the number of bytes of the section.
+	//
+	// Since 0.5.13
+	Size int64 `protobuf:"varint,12,opt,name=Size,proto3" json:"Size,omitempty"`
+	// CRC32C is the CRC-32 checksum with Castagnoli polynomial of the
+	// section.
+	//
+	// Since 0.5.13
+	CRC32C uint32 `protobuf:"fixed32,13,opt,name=CRC32C,proto3" json:"CRC32C,omitempty"`
+	// Encoder is the registered name of the encoder of values.
+	// See encode.Describer.
+	// It is empty if the encoder can not be described.
+	//
+	// Since 0.5.13
+	Encoder string `protobuf:"bytes,20,opt,name=Encoder,proto3" json:"Encoder,omitempty"`
+	// EncoderParams is the params to rebuild the encoder of values.
+	//
+	// Since 0.5.13
+	EncoderParams        []byte   `protobuf:"bytes,21,opt,name=EncoderParams,proto3" json:"EncoderParams,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ContainerSection) Reset()         { *m = ContainerSection{} }
+func (m *ContainerSection) String() string { return proto.CompactTextString(m) }
+func (*ContainerSection) ProtoMessage()    {}
+func (*ContainerSection) Descriptor() ([]byte, []int) {
+	return fileDescriptor_container_b8fed261ae760f7c, []int{1}
+}
+func (m *ContainerSection) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ContainerSection.Unmarshal(m, b)
+}
+func (m *ContainerSection) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ContainerSection.Marshal(b, m, deterministic)
+}
+func (dst *ContainerSection) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ContainerSection.Merge(dst, src)
+}
+func (m *ContainerSection) XXX_Size() int {
+	return xxx_messageInfo_ContainerSection.Size(m)
+}
+func (m *ContainerSection) XXX_DiscardUnknown() {
+	xxx_messageInfo_ContainerSection.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ContainerSection proto.InternalMessageInfo
+
+func (m *ContainerSection) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ContainerSection) GetOffset() int64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *ContainerSection) GetSize() int64 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+func (m *ContainerSection) GetCRC32C() uint32 {
+	if m != nil {
+		return m.CRC32C
+	}
+	return 0
+}
+
+func (m *ContainerSection) GetEncoder() string {
+	if m != nil {
+		return m.Encoder
+	}
+	return ""
+}
+
+func (m *ContainerSection) GetEncoderParams() []byte {
+	if m != nil {
+		return m.EncoderParams
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*ContainerDirectory)(nil), "ContainerDirectory")
+	proto.RegisterType((*ContainerSection)(nil), "ContainerSection")
+}
+
+func init() { proto.RegisterFile("container.proto", fileDescriptor_container_b8fed261ae760f7c) }
+
+var fileDescriptor_container_b8fed261ae760f7c = []byte{
+	// 215 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xe2, 0x4f, 0xce, 0xcf, 0x2b,
+	0x49, 0xcc, 0xcc, 0x4b, 0x2d, 0xd2, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x57, 0x8a, 0xe5, 0x12, 0x72,
+	0x86, 0x09, 0xb9, 0x64, 0x16, 0xa5, 0x26, 0x97, 0xe4, 0x17, 0x55, 0x0a, 0x49, 0x70, 0xb1, 0x87,
+	0xa5, 0x16, 0x15, 0x67, 0xe6, 0xe7, 0x49, 0x70, 0x29, 0x30, 0x6a, 0x70, 0x06, 0xc1, 0xb8, 0x42,
+	0xba, 0x5c, 0x1c, 0xc1, 0xa9, 0xc9, 0x25, 0x99, 0xf9, 0x79, 0xc5, 0x12, 0x22, 0x0a, 0xcc, 0x1a,
+	0xdc, 0x46, 0x82, 0x7a, 0x70, 0x03, 0xa0, 0x32, 0x41, 0x70, 0x25, 0x4a, 0xab, 0x18, 0xb9, 0x04,
+	0xd0, 0xa5, 0x85, 0x84, 0xb8, 0x58, 0xfc, 0x12, 0x73, 0x53, 0xa1, 0x46, 0x83, 0xd9, 0x42, 0x62,
+	0x5c, 0x6c, 0xfe, 0x69, 0x69, 0xc5, 0xa9, 0x25, 0x12, 0xdc, 0x0a, 0x8c, 0x1a, 0xcc, 0x41, 0x50,
+	0x1e, 0x48, 0x6d, 0x70, 0x66, 0x55, 0xaa, 0x04, 0x0f, 0x58, 0x14, 0xcc, 0x06, 0xa9, 0x75, 0x0e,
+	0x72, 0x36, 0x36, 0x72, 0x96, 0xe0, 0x55, 0x60, 0xd4, 0x60, 0x0f, 0x82, 0xf2, 0x40, 0xae, 0x76,
+	0xcd, 0x4b, 0xce, 0x4f, 0x49, 0x2d, 0x92, 0x10, 0x81, 0xb8, 0x1a, 0xca, 0x15, 0x52, 0xe1, 0xe2,
+	0x85, 0x32, 0x03, 0x12, 0x8b, 0x12, 0x73, 0x8b, 0x25, 0x44, 0x15, 0x18, 0x35, 0x78, 0x82, 0x50,
+	0x05, 0x9d, 0xb8, 0xa3, 0x38, 0xe1, 0xc1, 0x93, 0xc4, 0x06, 0x0e, 0x1f, 0x63, 0xc0, 0x00, 0xa7,
+	0x62, 0x03, 0x5f, 0x32, 0x01, 0x00, 0x00,
+}
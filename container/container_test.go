@@ -0,0 +1,379 @@
+package container
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/openacid/errors"
+	"github.com/openacid/slim/encode"
+	"github.com/openacid/slim/trie"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	fwdKeys = []string{"abc", "abcd", "abd", "abde", "bc", "bcd", "bcde", "cde"}
+	fwdVals = []int32{0, 1, 2, 3, 4, 5, 6, 7}
+
+	revKeys = []string{"a", "b", "c"}
+	revVals = []string{"x", "yy", "zzz"}
+)
+
+func newTries(t *testing.T) (*trie.SlimTrie, *trie.SlimTrie) {
+
+	ta := require.New(t)
+
+	fwd, err := trie.NewSlimTrie(encode.I32{}, fwdKeys, fwdVals)
+	ta.NoError(err)
+
+	rev, err := trie.NewSlimTrie(encode.String16{}, revKeys, revVals, trie.Opt{Complete: trie.Bool(true)})
+	ta.NoError(err)
+
+	return fwd, rev
+}
+
+func writeContainer(t *testing.T) []byte {
+
+	ta := require.New(t)
+
+	fwd, rev := newTries(t)
+
+	buf := bytes.NewBuffer(nil)
+	w := NewWriter(buf)
+	ta.NoError(w.Add("forward", fwd))
+	ta.NoError(w.Add("reverse", rev))
+	ta.NoError(w.Close())
+
+	return buf.Bytes()
+}
+
+func checkTries(t *testing.T, r *Reader) {
+
+	ta := require.New(t)
+
+	ta.Equal([]string{"forward", "reverse"}, r.Names())
+
+	fwd, err := r.Trie("forward")
+	ta.NoError(err)
+	for i, k := range fwdKeys {
+		v, found := fwd.Get(k)
+		ta.True(found)
+		ta.Equal(fwdVals[i], v)
+	}
+
+	rev, err := r.Trie("reverse")
+	ta.NoError(err)
+	for i, k := range revKeys {
+		v, found := rev.Get(k)
+		ta.True(found)
+		ta.Equal(revVals[i], v)
+	}
+	_, found := rev.Get("d")
+	ta.False(found)
+
+	// cached
+
+	fwd2, err := r.Trie("forward")
+	ta.NoError(err)
+	ta.True(fwd == fwd2)
+
+	_, err = r.Trie("foo")
+	ta.Equal(ErrNotFound, errors.Cause(err))
+}
+
+func TestCreateOpen(t *testing.T) {
+
+	ta := require.New(t)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "index.slimc")
+
+	fwd, rev := newTries(t)
+
+	w, err := Create(path)
+	ta.NoError(err)
+	ta.NoError(w.Add("forward", fwd))
+	ta.NoError(w.Add("reverse", rev))
+
+	_, err = os.Stat(path)
+	ta.True(os.IsNotExist(err), "target should not be visible before Close")
+
+	ta.NoError(w.Close())
+
+	r, err := Open(path)
+	ta.NoError(err)
+	defer r.Close()
+
+	checkTries(t, r)
+
+	sec := r.Section("reverse")
+	ta.Equal("String16", sec.Encoder)
+	ta.Nil(r.Section("foo"))
+
+	// no temp file left
+
+	files, err := ioutil.ReadDir(dir)
+	ta.NoError(err)
+	ta.Equal(1, len(files))
+
+	// Replace it
+
+	w, err = Create(path)
+	ta.NoError(err)
+	ta.NoError(w.Add("reverse", rev))
+	ta.NoError(w.Close())
+
+	// The opened one still reads the old file
+
+	checkTries(t, r)
+
+	r2, err := Open(path)
+	ta.NoError(err)
+	defer r2.Close()
+	ta.Equal([]string{"reverse"}, r2.Names())
+}
+
+func TestWriter_Abort(t *testing.T) {
+
+	ta := require.New(t)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "index.slimc")
+
+	fwd, _ := newTries(t)
+
+	w, err := Create(path)
+	ta.NoError(err)
+	ta.NoError(w.Add("forward", fwd))
+	ta.NoError(w.Abort())
+
+	files, err := ioutil.ReadDir(dir)
+	ta.NoError(err)
+	ta.Equal(0, len(files))
+
+	ta.Equal(ErrClosed, errors.Cause(w.Add("foo", fwd)))
+	ta.Equal(ErrClosed, errors.Cause(w.Close()))
+}
+
+func TestWriter_Add_error(t *testing.T) {
+
+	ta := require.New(t)
+
+	fwd, rev := newTries(t)
+
+	w := NewWriter(io.Discard)
+	ta.Equal(ErrEmptyName, errors.Cause(w.Add("", fwd)))
+	ta.NoError(w.Add("a", fwd))
+	ta.Equal(ErrDuplicatedName, errors.Cause(w.Add("a", rev)))
+	ta.NoError(w.Close())
+
+	// write error is sticky
+
+	w = NewWriter(&failWriter{})
+	err := w.Add("a", fwd)
+	ta.Equal(io.ErrShortWrite, errors.Cause(err))
+	ta.Equal(io.ErrShortWrite, errors.Cause(w.Add("b", fwd)))
+	ta.Equal(io.ErrShortWrite, errors.Cause(w.Close()))
+}
+
+type failWriter struct{}
+
+func (w *failWriter) Write(p []byte) (int, error) {
+	return 0, io.ErrShortWrite
+}
+
+// recordingReaderAt records ranges read.
+type recordingReaderAt struct {
+	r     io.ReaderAt
+	reads [][2]int64
+}
+
+func (r *recordingReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	r.reads = append(r.reads, [2]int64{off, off + int64(len(p))})
+	return r.r.ReadAt(p, off)
+}
+
+func TestReader_lazy(t *testing.T) {
+
+	ta := require.New(t)
+
+	buf := writeContainer(t)
+
+	rr := &recordingReaderAt{r: bytes.NewReader(buf)}
+	r, err := NewReader(rr, int64(len(buf)))
+	ta.NoError(err)
+
+	// only trailer and directory are read
+
+	dirStart := rr.reads[1][0]
+	for _, rng := range rr.reads {
+		ta.True(rng[0] >= dirStart)
+	}
+
+	rr.reads = nil
+	_, err = r.Trie("reverse")
+	ta.NoError(err)
+
+	sec := r.Section("reverse")
+	ta.Equal([][2]int64{{sec.Offset, sec.Offset + sec.Size}}, rr.reads)
+
+	// cached: no more read
+
+	rr.reads = nil
+	_, err = r.Trie("reverse")
+	ta.NoError(err)
+	ta.Equal(0, len(rr.reads))
+}
+
+func TestReader_corrupted(t *testing.T) {
+
+	ta := require.New(t)
+
+	buf := writeContainer(t)
+
+	r, err := NewReader(bytes.NewReader(buf), int64(len(buf)))
+	ta.NoError(err)
+
+	// flip a byte in the section "forward"
+
+	sec := r.Section("forward")
+
+	bad := append([]byte{}, buf...)
+	bad[sec.Offset+sec.Size/2] ^= 0x10
+
+	r, err = NewReader(bytes.NewReader(bad), int64(len(bad)))
+	ta.NoError(err)
+
+	_, err = r.Trie("forward")
+	ta.Equal(ErrCorrupted, errors.Cause(err))
+
+	_, err = r.Trie("reverse")
+	ta.NoError(err, "other sections are not affected")
+
+	// flip a byte in the directory
+
+	bad = append([]byte{}, buf...)
+	bad[len(bad)-int(trailerSize)-1] ^= 0x10
+	_, err = NewReader(bytes.NewReader(bad), int64(len(bad)))
+	ta.Equal(ErrCorrupted, errors.Cause(err))
+
+	// bad directory offset
+
+	bad = append([]byte{}, buf...)
+	bad[len(bad)-int(trailerSize)] ^= 0x10
+	_, err = NewReader(bytes.NewReader(bad), int64(len(bad)))
+	ta.Equal(ErrCorrupted, errors.Cause(err))
+
+	// not a container
+
+	for _, b := range [][]byte{nil, []byte("foo"), bytes.Repeat([]byte("foo"), 100), buf[:len(buf)-1]} {
+		_, err = NewReader(bytes.NewReader(b), int64(len(b)))
+		ta.Equal(ErrNotContainer, errors.Cause(err))
+	}
+}
+
+// blockingReaderAt blocks reading from offset `from` until `release` is
+// closed.
+type blockingReaderAt struct {
+	r       io.ReaderAt
+	from    int64
+	started chan struct{}
+	release chan struct{}
+
+	mu    sync.Mutex
+	reads int
+	err   error
+}
+
+func (r *blockingReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if off == r.from {
+		r.mu.Lock()
+		r.reads++
+		err := r.err
+		r.err = nil
+		r.mu.Unlock()
+
+		if err != nil {
+			return 0, err
+		}
+
+		close(r.started)
+		<-r.release
+	}
+	return r.r.ReadAt(p, off)
+}
+
+func TestReader_Trie_concurrent(t *testing.T) {
+
+	ta := require.New(t)
+
+	buf := writeContainer(t)
+
+	r, err := NewReader(bytes.NewReader(buf), int64(len(buf)))
+	ta.NoError(err)
+
+	br := &blockingReaderAt{
+		r:       bytes.NewReader(buf),
+		from:    r.Section("forward").Offset,
+		started: make(chan struct{}),
+		release: make(chan struct{}),
+		err:     errors.New("transient"),
+	}
+	r, err = NewReader(br, int64(len(buf)))
+	ta.NoError(err)
+
+	// a failed load is not cached
+
+	_, err = r.Trie("forward")
+	ta.Error(err)
+
+	const n = 5
+	type result struct {
+		st  *trie.SlimTrie
+		err error
+	}
+
+	results := make(chan result, n)
+	for i := 0; i < n; i++ {
+		go func() {
+			st, err := r.Trie("forward")
+			results <- result{st, err}
+		}()
+	}
+
+	<-br.started
+
+	// loading "forward" does not block other sections
+
+	done := make(chan error)
+	go func() {
+		_, err := r.Trie("reverse")
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		ta.NoError(err)
+	case <-time.After(5 * time.Second):
+		ta.Fail("Trie(\"reverse\") is blocked by loading another section")
+	}
+
+	close(br.release)
+
+	first := <-results
+	ta.NoError(first.err)
+	for i := 1; i < n; i++ {
+		rst := <-results
+		ta.NoError(rst.err)
+		ta.True(first.st == rst.st)
+	}
+
+	br.mu.Lock()
+	ta.Equal(2, br.reads, "one failed read and one load")
+	br.mu.Unlock()
+}
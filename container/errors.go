@@ -0,0 +1,29 @@
+package container
+
+import "errors"
+
+var (
+
+	// ErrNotContainer means the data to read is not a container.
+	ErrNotContainer = errors.New("not a container")
+
+	// ErrIncompatible means the container is written in an incompatible
+	// version.
+	ErrIncompatible = errors.New("incompatible container version")
+
+	// ErrCorrupted means a section or the directory does not match its
+	// checksum, or it is out of the range of the container.
+	ErrCorrupted = errors.New("container corrupted")
+
+	// ErrNotFound means there is no section with the name.
+	ErrNotFound = errors.New("section not found")
+
+	// ErrDuplicatedName means a section with the same name is already added.
+	ErrDuplicatedName = errors.New("duplicated section name")
+
+	// ErrEmptyName means a section is added without a name.
+	ErrEmptyName = errors.New("empty section name")
+
+	// ErrClosed means a Writer is used after it is closed or aborted.
+	ErrClosed = errors.New("writer closed")
+)
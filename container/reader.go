@@ -0,0 +1,244 @@
+package container
+
+import (
+	"hash/crc32"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/openacid/errors"
+	"github.com/openacid/low/vers"
+	"github.com/openacid/slim/encode"
+	"github.com/openacid/slim/trie"
+)
+
+// Reader reads SlimTries from a container.
+// A section is loaded only when it is asked for by Trie, and is cached
+// afterwards.
+//
+// It is safe to use a Reader concurrently.
+//
+// Since 0.5.13
+type Reader struct {
+	r      io.ReaderAt
+	closer io.Closer
+
+	dir      *ContainerDirectory
+	sections map[string]*ContainerSection
+
+	mu    sync.Mutex
+	tries map[string]*loadedTrie
+}
+
+// loadedTrie is the result of loading a section, which is loaded only once
+// even if it is asked for concurrently.
+type loadedTrie struct {
+	once sync.Once
+	st   *trie.SlimTrie
+	err  error
+}
+
+// Open opens a container file.
+// It reads only the directory. The file is kept open until Close.
+//
+// Since 0.5.13
+func Open(path string) (*Reader, error) {
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, errors.WithStack(err)
+	}
+
+	r, err := NewReader(f, fi.Size())
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	r.closer = f
+	return r, nil
+}
+
+// NewReader creates a Reader of a container of `size` bytes in r.
+// It reads only the directory.
+//
+// If r is not a container, it returns an error with cause ErrNotContainer.
+// If the directory is corrupted, it returns an error with cause ErrCorrupted.
+//
+// Since 0.5.13
+func NewReader(r io.ReaderAt, size int64) (*Reader, error) {
+
+	if size < trailerSize {
+		return nil, errors.Wrapf(ErrNotContainer, "size: %d", size)
+	}
+
+	b := make([]byte, trailerSize)
+	_, err := r.ReadAt(b, size-trailerSize)
+	if err != nil {
+		return nil, errors.WithMessage(errors.WithStack(err), "failed to read trailer")
+	}
+
+	t := &trailer{}
+	err = t.unmarshal(b)
+	if err != nil {
+		return nil, err
+	}
+
+	dirEnd := uint64(size - trailerSize)
+	if t.DirOffset > dirEnd || t.DirSize != dirEnd-t.DirOffset {
+		return nil, errors.Wrapf(ErrCorrupted, "directory offset: %d, size: %d", t.DirOffset, t.DirSize)
+	}
+
+	b = make([]byte, t.DirSize)
+	_, err = r.ReadAt(b, int64(t.DirOffset))
+	if err != nil {
+		return nil, errors.WithMessage(errors.WithStack(err), "failed to read directory")
+	}
+
+	if crc32.Checksum(b, crc32cTable) != t.DirCRC32C {
+		return nil, errors.Wrapf(ErrCorrupted, "directory checksum mismatch")
+	}
+
+	dir := &ContainerDirectory{}
+	err = proto.Unmarshal(b, dir)
+	if err != nil {
+		return nil, errors.Wrapf(ErrCorrupted, "failed to unmarshal directory: %v", err)
+	}
+
+	if !vers.IsCompatible(dir.Version, []string{"==" + containerVersion}) {
+		return nil, errors.Wrapf(ErrIncompatible, "version: %q", dir.Version)
+	}
+
+	// sections are before the directory
+	end := int64(t.DirOffset)
+
+	sections := make(map[string]*ContainerSection, len(dir.Sections))
+	for _, sec := range dir.Sections {
+		if sec.Offset < 0 || sec.Offset > end || sec.Size < 0 || sec.Size > end-sec.Offset {
+			return nil, errors.Wrapf(ErrCorrupted, "section %q offset: %d, size: %d", sec.Name, sec.Offset, sec.Size)
+		}
+		sections[sec.Name] = sec
+	}
+
+	return &Reader{
+		r:        r,
+		dir:      dir,
+		sections: sections,
+		tries:    map[string]*loadedTrie{},
+	}, nil
+}
+
+// Names returns the names of sections, in the order they are added.
+//
+// Since 0.5.13
+func (r *Reader) Names() []string {
+	names := make([]string, 0, len(r.dir.Sections))
+	for _, sec := range r.dir.Sections {
+		names = append(names, sec.Name)
+	}
+	return names
+}
+
+// Section returns the description of a section, or nil if there is no such
+// section.
+//
+// Since 0.5.13
+func (r *Reader) Section(name string) *ContainerSection {
+	return r.sections[name]
+}
+
+// Trie loads the SlimTrie of the section with the name.
+// The section is read and verified on the first call, and the same SlimTrie
+// is returned afterwards.
+//
+// The encoder recorded for the section is used to decode values.
+// If there is no such section, it returns an error with cause ErrNotFound.
+// If the section does not match its checksum, it returns an error with cause
+// ErrCorrupted.
+// A failed load is not cached, the next call reads the section again.
+//
+// Loading a section does not block loading other sections.
+// Concurrent calls for the same section wait for the first one to load it.
+//
+// Since 0.5.13
+func (r *Reader) Trie(name string) (*trie.SlimTrie, error) {
+
+	sec := r.sections[name]
+	if sec == nil {
+		return nil, errors.Wrapf(ErrNotFound, "name: %q", name)
+	}
+
+	r.mu.Lock()
+	lt, ok := r.tries[name]
+	if !ok {
+		lt = &loadedTrie{}
+		r.tries[name] = lt
+	}
+	r.mu.Unlock()
+
+	lt.once.Do(func() {
+		lt.st, lt.err = r.load(sec)
+	})
+
+	if lt.err != nil {
+		r.mu.Lock()
+		if r.tries[name] == lt {
+			delete(r.tries, name)
+		}
+		r.mu.Unlock()
+
+		return nil, errors.WithMessagef(lt.err, "failed to load section: %q", name)
+	}
+
+	return lt.st, nil
+}
+
+// Close closes the underlying file if it is created by Open.
+//
+// Since 0.5.13
+func (r *Reader) Close() error {
+	if r.closer == nil {
+		return nil
+	}
+	return errors.WithStack(r.closer.Close())
+}
+
+func (r *Reader) load(sec *ContainerSection) (*trie.SlimTrie, error) {
+
+	b := make([]byte, sec.Size)
+	_, err := r.r.ReadAt(b, sec.Offset)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if crc32.Checksum(b, crc32cTable) != sec.CRC32C {
+		return nil, errors.Wrapf(ErrCorrupted, "checksum mismatch")
+	}
+
+	var e encode.Encoder
+	if sec.Encoder != "" {
+		e, err = encode.New(sec.Encoder, sec.EncoderParams)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	st, err := trie.NewSlimTrie(e, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	err = st.Unmarshal(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return st, nil
+}
@@ -0,0 +1,217 @@
+package container
+
+import (
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/openacid/errors"
+	"github.com/openacid/slim/encode"
+	"github.com/openacid/slim/trie"
+)
+
+// Writer writes SlimTries into a container one by one.
+// The directory is written when it is closed.
+//
+// Since 0.5.13
+type Writer struct {
+	w     *countingWriter
+	dir   *ContainerDirectory
+	names map[string]bool
+	err   error
+
+	// For a Writer created by Create:
+
+	f       *os.File
+	path    string
+	tmpPath string
+}
+
+// NewWriter creates a Writer that writes a container to w.
+//
+// Since 0.5.13
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{
+		w:     &countingWriter{w: w},
+		dir:   &ContainerDirectory{Version: containerVersion},
+		names: map[string]bool{},
+	}
+}
+
+// Create creates a Writer that writes a container file at path.
+//
+// Data is written to a temporary file in the same directory, which is renamed
+// to path by Close.
+// Thus a reader never sees a partially written container, and an existing
+// container at path is replaced atomically.
+// Abort removes the temporary file.
+//
+// Since 0.5.13
+func Create(path string) (*Writer, error) {
+
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	w := NewWriter(f)
+	w.f = f
+	w.path = path
+	w.tmpPath = f.Name()
+
+	return w, nil
+}
+
+// Add writes a SlimTrie as a section with the name.
+//
+// If name is empty it returns an error with cause ErrEmptyName.
+// If name is already added it returns an error with cause ErrDuplicatedName.
+// If it fails to write, the Writer can not be used any more.
+//
+// Since 0.5.13
+func (w *Writer) Add(name string, st *trie.SlimTrie) error {
+
+	if w.err != nil {
+		return w.err
+	}
+
+	if name == "" {
+		return errors.WithStack(ErrEmptyName)
+	}
+
+	if w.names[name] {
+		return errors.Wrapf(ErrDuplicatedName, "name: %q", name)
+	}
+
+	sec := &ContainerSection{
+		Name:   name,
+		Offset: w.w.n,
+	}
+
+	if st.Encoder() != nil {
+		ename, params, err := encode.Describe(st.Encoder())
+		if err == nil {
+			sec.Encoder = ename
+			sec.EncoderParams = params
+		}
+	}
+
+	h := crc32.New(crc32cTable)
+	n, err := st.WriteTo(io.MultiWriter(w.w, h))
+	if err != nil {
+		w.err = errors.WithMessagef(err, "failed to write section: %q", name)
+		return w.err
+	}
+
+	sec.Size = n
+	sec.CRC32C = h.Sum32()
+
+	w.names[name] = true
+	w.dir.Sections = append(w.dir.Sections, sec)
+
+	return nil
+}
+
+// Close writes the directory and the trailer.
+// For a Writer created by Create, it syncs the temporary file and renames it
+// to the target path.
+//
+// Since 0.5.13
+func (w *Writer) Close() error {
+
+	if w.err != nil {
+		err := w.err
+		w.Abort()
+		return err
+	}
+
+	err := w.writeDirectory()
+	if err == nil && w.f != nil {
+		err = w.commit()
+	}
+
+	if err != nil {
+		w.Abort()
+		return err
+	}
+
+	w.err = ErrClosed
+	return nil
+}
+
+// Abort discards the container.
+// For a Writer created by Create, it removes the temporary file and leaves the
+// target path untouched.
+// It does nothing if the Writer is already closed.
+//
+// Since 0.5.13
+func (w *Writer) Abort() error {
+
+	if w.err == ErrClosed {
+		return nil
+	}
+	w.err = ErrClosed
+
+	if w.f == nil {
+		return nil
+	}
+
+	w.f.Close()
+	return errors.WithStack(os.Remove(w.tmpPath))
+}
+
+func (w *Writer) writeDirectory() error {
+
+	b, err := proto.Marshal(w.dir)
+	if err != nil {
+		return errors.WithMessage(errors.WithStack(err), "failed to marshal directory")
+	}
+
+	t := &trailer{
+		DirOffset: uint64(w.w.n),
+		DirSize:   uint64(len(b)),
+		DirCRC32C: crc32.Checksum(b, crc32cTable),
+		Magic:     trailerMagic,
+	}
+
+	_, err = w.w.Write(b)
+	if err != nil {
+		return errors.WithMessage(errors.WithStack(err), "failed to write directory")
+	}
+
+	_, err = w.w.Write(t.marshal())
+	if err != nil {
+		return errors.WithMessage(errors.WithStack(err), "failed to write trailer")
+	}
+
+	return nil
+}
+
+// commit makes the temporary file durable and moves it to the target path.
+func (w *Writer) commit() error {
+
+	// A temporary file is created with mode 0600.
+	err := w.f.Chmod(0644)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = w.f.Sync()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = w.f.Close()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = os.Rename(w.tmpPath, w.path)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package encode
+
+import (
+	"reflect"
+)
+
+// AppendDecoder is an optional interface an Encoder implements to encode into
+// and decode into a buffer provided by the caller.
+//
+// All built-in encoders in this package implement it.
+// DecodeInto does not allocate for the fixed-size integer, float and bool
+// encoders, the varint encoders, TypeEncoder, and the string and bytes
+// encoders decoding into a *[]byte.
+// Others, e.g., decoding a string into a *string, or ProtoEncoder,
+// JSONEncoder, GobEncoder and CompressedEncoder, still allocate for the
+// decoded value.
+//
+// Since 0.5.13
+type AppendDecoder interface {
+	// AppendEncode appends the encoded "v" to "dst" and returns the extended
+	// slice.
+	AppendEncode(dst []byte, v interface{}) []byte
+
+	// DecodeInto decodes the value at the start of "b" into "dst", which is
+	// a pointer to the decoded type, e.g., a *uint32 for U32.
+	// It returns number bytes consumed.
+	DecodeInto(b []byte, dst interface{}) int
+}
+
+// AppendEncode appends encoded "v" to "dst" with e.AppendEncode if "e"
+// implements AppendDecoder.
+// Otherwise it appends the result of e.Encode(v).
+//
+// Since 0.5.13
+func AppendEncode(e Encoder, dst []byte, v interface{}) []byte {
+	if ae, ok := e.(AppendDecoder); ok {
+		return ae.AppendEncode(dst, v)
+	}
+	return append(dst, e.Encode(v)...)
+}
+
+// DecodeInto decodes "b" into "dst" with e.DecodeInto if "e" implements
+// AppendDecoder.
+// Otherwise it calls e.Decode(b) and stores the result into the pointer "dst"
+// with reflect.
+// It returns number bytes consumed.
+//
+// Since 0.5.13
+func DecodeInto(e Encoder, b []byte, dst interface{}) int {
+	if ae, ok := e.(AppendDecoder); ok {
+		return ae.DecodeInto(b, dst)
+	}
+	n, v := e.Decode(b)
+	reflect.ValueOf(dst).Elem().Set(reflect.ValueOf(v))
+	return n
+}
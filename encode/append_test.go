@@ -0,0 +1,177 @@
+package encode_test
+
+import (
+	"encoding/binary"
+	"reflect"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/openacid/slim/encode"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppendDecoder(t *testing.T) {
+
+	ta := require.New(t)
+
+	typeEnc, err := encode.NewTypeEncoder(point{})
+	ta.NoError(err)
+
+	structEnc, err := encode.NewStructEncoder(varRecord{})
+	ta.NoError(err)
+
+	cases := []struct {
+		e     encode.Encoder
+		input interface{}
+	}{
+		{encode.U8{}, uint8(3)},
+		{encode.I8{}, int8(-3)},
+		{encode.U16{}, uint16(300)},
+		{encode.U32{}, uint32(300)},
+		{encode.U64{}, uint64(300)},
+		{encode.I16{}, int16(-300)},
+		{encode.I32{}, int32(-300)},
+		{encode.I64{}, int64(-300)},
+		{encode.Int{}, -300},
+		{encode.Bool{}, true},
+		{encode.F32{}, float32(1.5)},
+		{encode.F64{}, -2.5},
+		{encode.UVarint{}, uint64(300)},
+		{encode.Varint{}, int64(-300)},
+		{encode.String16{}, "foo"},
+		{encode.String32{}, "foo"},
+		{encode.StringVarint{}, "foo"},
+		{encode.Bytes{Size: 3}, []byte("foo")},
+		{encode.FixedBytes(3), []byte("foo")},
+		{typeEnc, point{1, 2}},
+		{structEnc, varRecord{ID: 3, Name: "foo", Raw: []byte{}, Points: []point{{1, 2}}}},
+		{encode.JSON(reflect.TypeOf(record{})), record{Name: "foo"}},
+		{encode.Gob(reflect.TypeOf(record{})), record{Name: "foo", Tags: []string{"x"}}},
+	}
+
+	for _, c := range cases {
+
+		ae, ok := c.e.(encode.AppendDecoder)
+		ta.True(ok, "%T", c.e)
+
+		want := c.e.Encode(c.input)
+		got := ae.AppendEncode([]byte("x"), c.input)
+		ta.Equal(append([]byte("x"), want...), got, "%T", c.e)
+
+		wantN, wantV := c.e.Decode(want)
+		dst := reflect.New(reflect.TypeOf(wantV))
+		n := ae.DecodeInto(append(want, 'y'), dst.Interface())
+		ta.Equal(wantN, n, "%T", c.e)
+		ta.Equal(wantV, dst.Elem().Interface(), "%T", c.e)
+
+		// helper functions
+		ta.Equal(got, encode.AppendEncode(c.e, []byte("x"), c.input), "%T", c.e)
+		dst = reflect.New(reflect.TypeOf(wantV))
+		ta.Equal(wantN, encode.DecodeInto(c.e, want, dst.Interface()), "%T", c.e)
+		ta.Equal(wantV, dst.Elem().Interface(), "%T", c.e)
+	}
+
+	// Dummy
+
+	ta.Equal([]byte("x"), encode.Dummy{}.AppendEncode([]byte("x"), 1))
+	ta.Equal(0, encode.Dummy{}.DecodeInto([]byte("x"), nil))
+
+	// Proto decodes into a message
+
+	pe := encode.Proto(&wrappers.StringValue{})
+	b := pe.AppendEncode(nil, &wrappers.StringValue{Value: "foo"})
+	ta.Equal(pe.Encode(&wrappers.StringValue{Value: "foo"}), b)
+
+	m := &wrappers.StringValue{Value: "bar"}
+	ta.Equal(len(b), pe.DecodeInto(b, m))
+	ta.Equal("foo", m.Value)
+
+	// string decodes into []byte without copy
+
+	sb := encode.StringVarint{}.Encode("foo")
+	var raw []byte
+	ta.Equal(4, encode.StringVarint{}.DecodeInto(sb, &raw))
+	ta.Equal([]byte("foo"), raw)
+	ta.Equal(&sb[1], &raw[0])
+}
+
+func TestAppendDecoder_fallback(t *testing.T) {
+
+	ta := require.New(t)
+
+	e := struct{ encode.Encoder }{encode.U32{}}
+	_, ok := interface{}(e).(encode.AppendDecoder)
+	ta.False(ok)
+
+	b := encode.AppendEncode(e, []byte("x"), uint32(5))
+	ta.Equal([]byte{'x', 5, 0, 0, 0}, b)
+
+	var v uint32
+	ta.Equal(4, encode.DecodeInto(e, b[1:], &v))
+	ta.Equal(uint32(5), v)
+}
+
+func TestAppendDecoder_alloc(t *testing.T) {
+
+	ta := require.New(t)
+
+	b := encode.U64{}.Encode(uint64(5))
+	buf := make([]byte, 0, 64)
+
+	u64 := new(uint64)
+	f64 := new(float64)
+	str := new([]byte)
+	sb := encode.StringVarint{}.Encode("foo")
+	s32 := encode.String32{}.Encode("foo")
+	vb := encode.Varint{}.Encode(int64(-5))
+	i64 := new(int64)
+	bl := new(bool)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		encode.U64{}.DecodeInto(b, u64)
+		encode.F64{}.DecodeInto(b, f64)
+		encode.StringVarint{}.DecodeInto(sb, str)
+		encode.String32{}.DecodeInto(s32, str)
+		encode.Bytes{Size: 3}.DecodeInto(sb, str)
+		encode.FixedBytes(3).DecodeInto(sb, str)
+		encode.Varint{}.DecodeInto(vb, i64)
+		encode.Bool{}.DecodeInto(b, bl)
+		encode.U32{}.AppendEncode(buf[:0], uint32(5))
+		encode.DecodeInto(encode.U64{}, b, u64)
+	})
+	ta.Equal(float64(0), allocs)
+}
+
+func TestTypeEncoder_DecodeInto(t *testing.T) {
+
+	ta := require.New(t)
+
+	cases := []interface{}{
+		uint32(5),
+		point{-1, 2.5},
+		[3]int16{-1, 2, 3},
+	}
+
+	for _, c := range cases {
+		for _, endian := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+
+			m, err := encode.NewTypeEncoderEndian(c, endian)
+			ta.NoError(err)
+
+			b := m.Encode(c)
+			dst := reflect.New(reflect.TypeOf(c))
+			ta.Equal(m.Size, m.DecodeInto(b, dst.Interface()))
+			ta.Equal(c, dst.Elem().Interface(), "%T %v", c, endian)
+		}
+	}
+
+	m, err := encode.NewTypeEncoder(point{})
+	ta.NoError(err)
+	b := m.Encode(point{1, 2})
+	dst := new(point)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		m.DecodeInto(b, dst)
+	})
+	ta.Equal(float64(0), allocs)
+}
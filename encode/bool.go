@@ -0,0 +1,65 @@
+package encode
+
+// Bool converts bool to slice of 1 byte and back.
+// true is encoded as 1 and false is encoded as 0.
+//
+// Since 0.5.13
+type Bool struct{}
+
+// Encode converts bool to slice of 1 byte.
+func (c Bool) Encode(d interface{}) []byte {
+	if d.(bool) {
+		return []byte{1}
+	}
+	return []byte{0}
+}
+
+// Decode converts slice of 1 byte to bool.
+// Any non-zero byte is decoded as true.
+// It returns number bytes consumed and a bool.
+func (c Bool) Decode(b []byte) (int, interface{}) {
+	return 1, b[0] != 0
+}
+
+// GetSize returns the size in byte after encoding v.
+func (c Bool) GetSize(d interface{}) int {
+	return 1
+}
+
+// GetEncodedSize returns 1.
+func (c Bool) GetEncodedSize(b []byte) int {
+	return 1
+}
+
+// FixedSize returns 1.
+//
+// Since 0.5.13
+func (c Bool) FixedSize() int {
+	return 1
+}
+
+// AppendEncode appends 1 byte of bool to dst.
+//
+// Since 0.5.13
+func (c Bool) AppendEncode(dst []byte, d interface{}) []byte {
+	if d.(bool) {
+		return append(dst, 1)
+	}
+	return append(dst, 0)
+}
+
+// DecodeInto decodes 1 byte into a *bool.
+// It returns number bytes consumed.
+//
+// Since 0.5.13
+func (c Bool) DecodeInto(b []byte, dst interface{}) int {
+	*dst.(*bool) = b[0] != 0
+	return 1
+}
+
+// Describe returns the registered name "Bool" and no params.
+//
+// Since 0.5.13
+func (c Bool) Describe() (string, []byte) {
+	return "Bool", nil
+}
@@ -0,0 +1,42 @@
+package encode_test
+
+import (
+	"testing"
+
+	"github.com/openacid/slim/encode"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBool(t *testing.T) {
+
+	ta := require.New(t)
+
+	cases := []struct {
+		input    bool
+		want     string
+		wantsize int
+	}{
+		{false, string([]byte{0}), 1},
+		{true, string([]byte{1}), 1},
+	}
+
+	m := encode.Bool{}
+
+	for _, c := range cases {
+		rst := m.Encode(c.input)
+		ta.Equal(c.want, string(rst))
+
+		n := m.GetSize(c.input)
+		ta.Equal(c.wantsize, n)
+
+		n = m.GetEncodedSize(rst)
+		ta.Equal(c.wantsize, n)
+
+		n, v := m.Decode(rst)
+		ta.Equal(c.input, v)
+		ta.Equal(c.wantsize, n)
+	}
+
+	_, v := m.Decode([]byte{2})
+	ta.Equal(true, v, "non-zero byte is true")
+}
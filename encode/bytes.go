@@ -27,3 +27,34 @@ func (c Bytes) GetSize(d interface{}) int {
 func (c Bytes) GetEncodedSize(b []byte) int {
 	return c.Size
 }
+
+// FixedSize returns c.Size.
+//
+// Since 0.5.13
+func (c Bytes) FixedSize() int {
+	return c.Size
+}
+
+// AppendEncode appends the byte slice to dst.
+//
+// Since 0.5.13
+func (c Bytes) AppendEncode(dst []byte, d interface{}) []byte {
+	return append(dst, d.([]byte)...)
+}
+
+// DecodeInto stores the first c.Size bytes into a *[]byte.
+// The bytes are NOT copied.
+// It returns number bytes consumed.
+//
+// Since 0.5.13
+func (c Bytes) DecodeInto(b []byte, dst interface{}) int {
+	*dst.(*[]byte) = b[:c.Size]
+	return c.Size
+}
+
+// Describe returns the registered name "Bytes" and c.Size in uvarint as params.
+//
+// Since 0.5.13
+func (c Bytes) Describe() (string, []byte) {
+	return "Bytes", appendUvarint(nil, uint64(c.Size))
+}
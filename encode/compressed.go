@@ -0,0 +1,265 @@
+package encode
+
+import (
+	"bytes"
+	"compress/flate"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/openacid/errors"
+)
+
+// CompressedEncoder compresses the output of another Encoder with
+// compress/flate, and decompresses it before decoding.
+//
+// An optional dictionary shared by all values improves the compression ratio
+// of small values, which otherwise have too little context to compress well.
+// See TrainDict.
+//
+// The encoded value is the compressed data prefixed with its length in uvarint:
+//
+//	<uvarint(len(compressed))><compressed>
+//
+// Since 0.5.13
+type CompressedEncoder struct {
+	// Inner encodes a value before compression.
+	// If it is nil, values are []byte and are compressed as is.
+	Inner Encoder
+
+	// Level is the flate compression level, from flate.HuffmanOnly to
+	// flate.BestCompression.
+	Level int
+
+	// Dict is the preset dictionary. It must be the same when encoding and
+	// decoding.
+	Dict []byte
+
+	writers sync.Pool
+	readers sync.Pool
+}
+
+// Compressed creates a *CompressedEncoder without dictionary.
+// It panics if "level" is not a valid flate compression level.
+//
+// Since 0.5.13
+func Compressed(inner Encoder, level int) *CompressedEncoder {
+	return CompressedDict(inner, level, nil)
+}
+
+// CompressedDict creates a *CompressedEncoder with a preset dictionary "dict",
+// such as one built by TrainDict.
+// It panics if "level" is not a valid flate compression level.
+//
+// Since 0.5.13
+func CompressedDict(inner Encoder, level int, dict []byte) *CompressedEncoder {
+	if level < flate.HuffmanOnly || level > flate.BestCompression {
+		panic(errors.Errorf("invalid flate compression level: %d", level))
+	}
+
+	return &CompressedEncoder{
+		Inner: inner,
+		Level: level,
+		Dict:  dict,
+	}
+}
+
+// compress appends the length prefixed compressed "raw" to dst.
+func (c *CompressedEncoder) compress(dst []byte, raw []byte) []byte {
+	var buf bytes.Buffer
+
+	w, _ := c.writers.Get().(*flate.Writer)
+	if w == nil {
+		var err error
+		w, err = flate.NewWriterDict(&buf, c.Level, c.Dict)
+		if err != nil {
+			panic(errors.Wrapf(err, "failure to create flate writer"))
+		}
+	} else {
+		w.Reset(&buf)
+	}
+
+	_, err := w.Write(raw)
+	if err == nil {
+		err = w.Close()
+	}
+	if err != nil {
+		panic(errors.Wrapf(err, "failure to compress"))
+	}
+	c.writers.Put(w)
+
+	return appendLenPrefixed(dst, buf.Bytes())
+}
+
+// decompress returns the decompressed data and number of bytes consumed.
+func (c *CompressedEncoder) decompress(b []byte) ([]byte, int) {
+	payload, n := readLenPrefixed(b)
+
+	r, _ := c.readers.Get().(io.ReadCloser)
+	if r == nil {
+		r = flate.NewReaderDict(bytes.NewReader(payload), c.Dict)
+	} else {
+		err := r.(flate.Resetter).Reset(bytes.NewReader(payload), c.Dict)
+		if err != nil {
+			panic(errors.Wrapf(err, "failure to reset flate reader"))
+		}
+	}
+
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		panic(errors.Wrapf(err, "failure to decompress"))
+	}
+	c.readers.Put(r)
+
+	return raw, n
+}
+
+func (c *CompressedEncoder) encodeInner(dst []byte, d interface{}) []byte {
+	if c.Inner == nil {
+		return append(dst, d.([]byte)...)
+	}
+	return AppendEncode(c.Inner, dst, d)
+}
+
+// Encode encodes "d" with c.Inner and compresses it.
+func (c *CompressedEncoder) Encode(d interface{}) []byte {
+	return c.compress(nil, c.encodeInner(nil, d))
+}
+
+// Decode decompresses "b" and decodes it with c.Inner.
+// It returns number bytes consumed and the value.
+func (c *CompressedEncoder) Decode(b []byte) (int, interface{}) {
+	raw, n := c.decompress(b)
+	if c.Inner == nil {
+		return n, raw
+	}
+	_, v := c.Inner.Decode(raw)
+	return n, v
+}
+
+// GetSize returns the size in byte after encoding v.
+// It has to compress v to find out the size.
+func (c *CompressedEncoder) GetSize(d interface{}) int {
+	return len(c.Encode(d))
+}
+
+// GetEncodedSize returns size of the encoded value.
+func (c *CompressedEncoder) GetEncodedSize(b []byte) int {
+	_, n := readLenPrefixed(b)
+	return n
+}
+
+// AppendEncode appends the compressed "d" to dst.
+//
+// Since 0.5.13
+func (c *CompressedEncoder) AppendEncode(dst []byte, d interface{}) []byte {
+	return c.compress(dst, c.encodeInner(nil, d))
+}
+
+// DecodeInto decompresses "b" and decodes it into "dst" with c.Inner.
+// If c.Inner is nil, "dst" is a *[]byte.
+// It returns number bytes consumed.
+//
+// Since 0.5.13
+func (c *CompressedEncoder) DecodeInto(b []byte, dst interface{}) int {
+	raw, n := c.decompress(b)
+	if c.Inner == nil {
+		*dst.(*[]byte) = raw
+		return n
+	}
+	DecodeInto(c.Inner, raw, dst)
+	return n
+}
+
+// Describe returns the registered name "Compressed" and params of the
+// compression level, the dictionary and the description of c.Inner:
+//
+//	<varint(level)>
+//	<uvarint(len(dict))><dict>
+//	<uvarint(len(innerName))><innerName><innerParams>
+//
+// If c.Inner is nil, innerName is empty.
+// If c.Inner can not be described, it returns an empty name.
+//
+// Since 0.5.13
+func (c *CompressedEncoder) Describe() (string, []byte) {
+	params := appendVarint(nil, int64(c.Level))
+	params = appendLenPrefixed(params, c.Dict)
+
+	innerName, innerParams := "", []byte(nil)
+	if c.Inner != nil {
+		var err error
+		innerName, innerParams, err = Describe(c.Inner)
+		if err != nil {
+			return "", nil
+		}
+	}
+	params = appendLenPrefixed(params, []byte(innerName))
+	return "Compressed", append(params, innerParams...)
+}
+
+// TrainDict builds a preset dictionary of at most "size" bytes for
+// CompressedDict, from sample encoded values.
+//
+// A sample that shares more 8-byte substrings with other samples is more
+// likely to be selected.
+// Since flate encodes a nearer match with fewer bits, the most shared sample
+// is placed at the end of the dictionary.
+// flate uses at most the last 32KB of a dictionary.
+//
+// Since 0.5.13
+func TrainDict(samples [][]byte, size int) []byte {
+
+	const gram = 8
+
+	// number of samples a gram appears in
+	freq := make(map[string]int)
+	for _, s := range samples {
+		seen := make(map[string]bool)
+		for i := 0; i+gram <= len(s); i++ {
+			g := string(s[i : i+gram])
+			if !seen[g] {
+				seen[g] = true
+				freq[g]++
+			}
+		}
+	}
+
+	type scored struct {
+		sample []byte
+		score  float64
+	}
+
+	ss := make([]scored, 0, len(samples))
+	for _, s := range samples {
+		if len(s) < gram {
+			continue
+		}
+		shared := 0
+		for i := 0; i+gram <= len(s); i++ {
+			shared += freq[string(s[i:i+gram])] - 1
+		}
+		if shared > 0 {
+			ss = append(ss, scored{s, float64(shared) / float64(len(s))})
+		}
+	}
+
+	sort.SliceStable(ss, func(i, j int) bool { return ss[i].score > ss[j].score })
+
+	// Fill from the end: the best sample is the last.
+	dict := make([]byte, size)
+	end := size
+	for _, s := range ss {
+		if end == 0 {
+			break
+		}
+		n := len(s.sample)
+		if n > end {
+			n = end
+		}
+		copy(dict[end-n:end], s.sample[:n])
+		end -= n
+	}
+
+	return dict[end:]
+}
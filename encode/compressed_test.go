@@ -0,0 +1,140 @@
+package encode_test
+
+import (
+	"bytes"
+	"compress/flate"
+	"fmt"
+	"testing"
+
+	"github.com/openacid/slim/encode"
+	"github.com/stretchr/testify/require"
+)
+
+func htmlSamples(n int) [][]byte {
+	rst := make([][]byte, 0, n)
+	for i := 0; i < n; i++ {
+		rst = append(rst, []byte(fmt.Sprintf(
+			`<div class="item"><a href="/item/%d">item %d</a><span class="price">%d.00</span></div>`,
+			i, i, i*7%1000)))
+	}
+	return rst
+}
+
+func TestCompressed(t *testing.T) {
+
+	ta := require.New(t)
+
+	big := bytes.Repeat([]byte("hello world "), 100)
+
+	cases := []struct {
+		e     *encode.CompressedEncoder
+		input interface{}
+	}{
+		{encode.Compressed(nil, flate.BestCompression), big},
+		{encode.Compressed(nil, flate.DefaultCompression), []byte{}},
+		{encode.Compressed(nil, flate.NoCompression), []byte("abc")},
+		{encode.Compressed(encode.StringVarint{}, flate.BestSpeed), string(big)},
+		{encode.Compressed(encode.U64{}, flate.HuffmanOnly), uint64(12345)},
+	}
+
+	for _, c := range cases {
+		e := c.e
+
+		rst := e.Encode(c.input)
+		ta.Equal(len(rst), e.GetSize(c.input))
+		ta.Equal(len(rst), e.GetEncodedSize(append(rst, 1, 2)))
+
+		n, v := e.Decode(append(rst, 1, 2))
+		ta.Equal(len(rst), n)
+		ta.Equal(c.input, v)
+
+		// encode twice with pooled writer and reader
+		ta.Equal(rst, e.Encode(c.input))
+		_, v = e.Decode(rst)
+		ta.Equal(c.input, v)
+
+		ta.Equal(append([]byte("x"), rst...), e.AppendEncode([]byte("x"), c.input))
+	}
+
+	e := encode.Compressed(nil, flate.BestCompression)
+	rst := e.Encode(big)
+	ta.True(len(rst) < len(big)/10, "compressed: %d", len(rst))
+
+	var into []byte
+	ta.Equal(len(rst), e.DecodeInto(rst, &into))
+	ta.Equal(big, into)
+
+	var s string
+	es := encode.Compressed(encode.StringVarint{}, flate.BestCompression)
+	es.DecodeInto(es.Encode("foo"), &s)
+	ta.Equal("foo", s)
+
+	ta.Panics(func() { encode.Compressed(nil, 10) })
+	ta.Panics(func() { e.Decode([]byte{2, 0xff, 0xff}) })
+}
+
+func TestCompressed_dict(t *testing.T) {
+
+	ta := require.New(t)
+
+	samples := htmlSamples(200)
+	dict := encode.TrainDict(samples[:100], 1024)
+	ta.True(len(dict) > 0)
+	ta.True(len(dict) <= 1024)
+
+	plain := encode.Compressed(nil, flate.BestCompression)
+	withDict := encode.CompressedDict(nil, flate.BestCompression, dict)
+
+	plainSize, dictSize := 0, 0
+	for _, s := range samples[100:] {
+		plainSize += len(plain.Encode(s))
+
+		b := withDict.Encode(s)
+		dictSize += len(b)
+
+		_, v := withDict.Decode(b)
+		ta.Equal(s, v)
+	}
+
+	ta.True(dictSize < plainSize/2, "with dict: %d, without: %d", dictSize, plainSize)
+
+	// decoding with a different dictionary fails or yields different data.
+	b := withDict.Encode(samples[150])
+	func() {
+		defer func() { _ = recover() }()
+		_, v := plain.Decode(b)
+		ta.NotEqual(samples[150], v)
+	}()
+}
+
+func TestTrainDict(t *testing.T) {
+
+	ta := require.New(t)
+
+	ta.Equal([]byte{}, encode.TrainDict(nil, 100))
+	ta.Equal([]byte{}, encode.TrainDict([][]byte{[]byte("abc")}, 100))
+
+	// not shared samples are not selected
+	dict := encode.TrainDict([][]byte{
+		[]byte("0123456789"),
+		[]byte("abcdefghij"),
+		[]byte("0123456789x"),
+	}, 100)
+	ta.Equal("0123456789x0123456789", string(dict))
+
+	dict = encode.TrainDict(htmlSamples(100), 10)
+	ta.Equal(10, len(dict))
+}
+
+func BenchmarkCompressed_Decode(b *testing.B) {
+	samples := htmlSamples(100)
+	e := encode.CompressedDict(nil, flate.BestCompression, encode.TrainDict(samples, 4096))
+	enc := e.Encode(samples[0])
+
+	var into []byte
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		e.DecodeInto(enc, &into)
+	}
+}
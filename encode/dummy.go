@@ -24,3 +24,31 @@ func (c Dummy) GetSize(d interface{}) int {
 func (c Dummy) GetEncodedSize(b []byte) int {
 	return 0
 }
+
+// FixedSize returns 0.
+//
+// Since 0.5.13
+func (c Dummy) FixedSize() int {
+	return 0
+}
+
+// AppendEncode returns dst.
+//
+// Since 0.5.13
+func (c Dummy) AppendEncode(dst []byte, d interface{}) []byte {
+	return dst
+}
+
+// DecodeInto does nothing and returns 0.
+//
+// Since 0.5.13
+func (c Dummy) DecodeInto(b []byte, dst interface{}) int {
+	return 0
+}
+
+// Describe returns the registered name "Dummy" and c.Size in uvarint as params.
+//
+// Since 0.5.13
+func (c Dummy) Describe() (string, []byte) {
+	return "Dummy", appendUvarint(nil, uint64(c.Size))
+}
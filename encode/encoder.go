@@ -17,6 +17,13 @@ var (
 	// determined by its type.
 	// Such slice of interface.
 	ErrNotFixedSize = errors.New("element type is not fixed size")
+
+	// ErrUnknownEncoder indicates an encoder name is not registered, or an
+	// Encoder can not be described by a name.
+	ErrUnknownEncoder = errors.New("encoder is unknown")
+
+	// ErrInvalidParams indicates the params to create an encoder is malformed.
+	ErrInvalidParams = errors.New("invalid encoder params")
 )
 
 // A Encoder converts one element between serialized byte stream
@@ -107,3 +114,31 @@ func (s String16) GetEncodedSize(b []byte) int {
 	l := int(b[0])<<8 + int(b[1])
 	return 2 + l
 }
+
+// AppendEncode appends 2 bytes length and the string to dst.
+//
+// Since 0.5.13
+func (s String16) AppendEncode(dst []byte, d interface{}) []byte {
+	ss := d.(string)
+	l := len(ss)
+	dst = append(dst, byte(l>>8), byte(l))
+	return append(dst, ss...)
+}
+
+// DecodeInto decodes into a *string or a *[]byte.
+// Decoding into a *[]byte does not allocate: it references b.
+// It returns number bytes consumed.
+//
+// Since 0.5.13
+func (s String16) DecodeInto(b []byte, dst interface{}) int {
+	l := int(b[0])<<8 + int(b[1])
+	decodeStringInto(b[2:2+l], dst)
+	return 2 + l
+}
+
+// Describe returns the registered name "String16" and no params.
+//
+// Since 0.5.13
+func (s String16) Describe() (string, []byte) {
+	return "String16", nil
+}
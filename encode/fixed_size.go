@@ -0,0 +1,27 @@
+package encode
+
+// FixedSizer is an optional interface an Encoder implements if every encoded
+// value has the same size.
+// With it the size is known without an encoded value.
+//
+// The built-in encoders of fixed-size types implement it.
+//
+// Since 0.5.13
+type FixedSizer interface {
+	// FixedSize returns the size in byte of every encoded value.
+	// It returns -1 if encoded values are var-length, e.g., a StructEncoder
+	// of a struct with a string field.
+	FixedSize() int
+}
+
+// FixedSize returns the size of every value encoded by "e", if "e" implements
+// FixedSizer.
+// Otherwise it returns -1.
+//
+// Since 0.5.13
+func FixedSize(e Encoder) int {
+	if fs, ok := e.(FixedSizer); ok {
+		return fs.FixedSize()
+	}
+	return -1
+}
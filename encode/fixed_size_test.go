@@ -0,0 +1,68 @@
+package encode_test
+
+import (
+	"math/bits"
+	"testing"
+
+	"github.com/openacid/slim/encode"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFixedSize(t *testing.T) {
+
+	ta := require.New(t)
+
+	type fixed struct {
+		A int32
+		B uint16
+	}
+	type varLen struct {
+		A int32
+		S string
+	}
+
+	structEnc := func(zero interface{}) encode.Encoder {
+		e, err := encode.NewStructEncoder(zero)
+		ta.NoError(err)
+		return e
+	}
+	typeEnc, err := encode.NewTypeEncoder(uint32(0))
+	ta.NoError(err)
+
+	cases := []struct {
+		e    encode.Encoder
+		want int
+	}{
+		{encode.U8{}, 1},
+		{encode.I8{}, 1},
+		{encode.Bool{}, 1},
+		{encode.U16{}, 2},
+		{encode.I16{}, 2},
+		{encode.U32{}, 4},
+		{encode.I32{}, 4},
+		{encode.F32{}, 4},
+		{encode.U64{}, 8},
+		{encode.I64{}, 8},
+		{encode.F64{}, 8},
+		{encode.Int{}, bits.UintSize / 8},
+		{encode.Bytes{Size: 3}, 3},
+		{encode.FixedBytes(5), 5},
+		{encode.Dummy{}, 0},
+		{typeEnc, 4},
+		{structEnc(fixed{}), 6},
+
+		{structEnc(varLen{}), -1},
+		{encode.String16{}, -1},
+		{encode.String32{}, -1},
+		{encode.StringVarint{}, -1},
+		{encode.UVarint{}, -1},
+	}
+
+	for i, c := range cases {
+		ta.Equal(c.want, encode.FixedSize(c.e), "%d-th: %T", i+1, c.e)
+
+		if c.want >= 0 {
+			ta.Equal(c.want, c.e.GetEncodedSize(nil), "%d-th: %T", i+1, c.e)
+		}
+	}
+}
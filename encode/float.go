@@ -0,0 +1,128 @@
+package encode
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// F32 converts float32 to slice of 4 bytes and back.
+// A float32 is stored in IEEE 754 binary representation, in little endian.
+//
+// Since 0.5.13
+type F32 struct{}
+
+// Encode converts float32 to slice of 4 bytes.
+func (c F32) Encode(d interface{}) []byte {
+	b := make([]byte, 4)
+	binary.LittleEndian.PutUint32(b, math.Float32bits(d.(float32)))
+	return b
+}
+
+// Decode converts slice of 4 bytes to float32.
+// It returns number bytes consumed and a float32.
+func (c F32) Decode(b []byte) (int, interface{}) {
+	return 4, math.Float32frombits(binary.LittleEndian.Uint32(b[:4]))
+}
+
+// GetSize returns the size in byte after encoding v.
+func (c F32) GetSize(d interface{}) int {
+	return 4
+}
+
+// GetEncodedSize returns 4.
+func (c F32) GetEncodedSize(b []byte) int {
+	return 4
+}
+
+// FixedSize returns 4.
+//
+// Since 0.5.13
+func (c F32) FixedSize() int {
+	return 4
+}
+
+// AppendEncode appends 4 bytes of float32 to dst.
+//
+// Since 0.5.13
+func (c F32) AppendEncode(dst []byte, d interface{}) []byte {
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], math.Float32bits(d.(float32)))
+	return append(dst, b[:]...)
+}
+
+// DecodeInto decodes 4 bytes into a *float32.
+// It returns number bytes consumed.
+//
+// Since 0.5.13
+func (c F32) DecodeInto(b []byte, dst interface{}) int {
+	*dst.(*float32) = math.Float32frombits(binary.LittleEndian.Uint32(b[:4]))
+	return 4
+}
+
+// Describe returns the registered name "F32" and no params.
+//
+// Since 0.5.13
+func (c F32) Describe() (string, []byte) {
+	return "F32", nil
+}
+
+// F64 converts float64 to slice of 8 bytes and back.
+// A float64 is stored in IEEE 754 binary representation, in little endian.
+//
+// Since 0.5.13
+type F64 struct{}
+
+// Encode converts float64 to slice of 8 bytes.
+func (c F64) Encode(d interface{}) []byte {
+	b := make([]byte, 8)
+	binary.LittleEndian.PutUint64(b, math.Float64bits(d.(float64)))
+	return b
+}
+
+// Decode converts slice of 8 bytes to float64.
+// It returns number bytes consumed and a float64.
+func (c F64) Decode(b []byte) (int, interface{}) {
+	return 8, math.Float64frombits(binary.LittleEndian.Uint64(b[:8]))
+}
+
+// GetSize returns the size in byte after encoding v.
+func (c F64) GetSize(d interface{}) int {
+	return 8
+}
+
+// GetEncodedSize returns 8.
+func (c F64) GetEncodedSize(b []byte) int {
+	return 8
+}
+
+// FixedSize returns 8.
+//
+// Since 0.5.13
+func (c F64) FixedSize() int {
+	return 8
+}
+
+// AppendEncode appends 8 bytes of float64 to dst.
+//
+// Since 0.5.13
+func (c F64) AppendEncode(dst []byte, d interface{}) []byte {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], math.Float64bits(d.(float64)))
+	return append(dst, b[:]...)
+}
+
+// DecodeInto decodes 8 bytes into a *float64.
+// It returns number bytes consumed.
+//
+// Since 0.5.13
+func (c F64) DecodeInto(b []byte, dst interface{}) int {
+	*dst.(*float64) = math.Float64frombits(binary.LittleEndian.Uint64(b[:8]))
+	return 8
+}
+
+// Describe returns the registered name "F64" and no params.
+//
+// Since 0.5.13
+func (c F64) Describe() (string, []byte) {
+	return "F64", nil
+}
@@ -0,0 +1,74 @@
+package encode_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/openacid/slim/encode"
+	"github.com/stretchr/testify/require"
+)
+
+func TestF32(t *testing.T) {
+
+	ta := require.New(t)
+
+	cases := []struct {
+		input float32
+		want  string
+	}{
+		{0, string([]byte{0, 0, 0, 0})},
+		{1, string([]byte{0, 0, 0x80, 0x3f})},
+		{-2.5, string([]byte{0, 0, 0x20, 0xc0})},
+		{math.MaxFloat32, string([]byte{0xff, 0xff, 0x7f, 0x7f})},
+		{float32(math.Inf(-1)), string([]byte{0, 0, 0x80, 0xff})},
+	}
+
+	m := encode.F32{}
+
+	for _, c := range cases {
+		rst := m.Encode(c.input)
+		ta.Equal(c.want, string(rst))
+
+		ta.Equal(4, m.GetSize(c.input))
+		ta.Equal(4, m.GetEncodedSize(rst))
+
+		n, v := m.Decode(rst)
+		ta.Equal(c.input, v)
+		ta.Equal(4, n)
+	}
+
+	_, v := m.Decode(m.Encode(float32(math.NaN())))
+	ta.True(math.IsNaN(float64(v.(float32))))
+}
+
+func TestF64(t *testing.T) {
+
+	ta := require.New(t)
+
+	cases := []struct {
+		input float64
+		want  string
+	}{
+		{0, string([]byte{0, 0, 0, 0, 0, 0, 0, 0})},
+		{1, string([]byte{0, 0, 0, 0, 0, 0, 0xf0, 0x3f})},
+		{-2.5, string([]byte{0, 0, 0, 0, 0, 0, 0x04, 0xc0})},
+		{math.Inf(1), string([]byte{0, 0, 0, 0, 0, 0, 0xf0, 0x7f})},
+	}
+
+	m := encode.F64{}
+
+	for _, c := range cases {
+		rst := m.Encode(c.input)
+		ta.Equal(c.want, string(rst))
+
+		ta.Equal(8, m.GetSize(c.input))
+		ta.Equal(8, m.GetEncodedSize(rst))
+
+		n, v := m.Decode(rst)
+		ta.Equal(c.input, v)
+		ta.Equal(8, n)
+	}
+
+	_, v := m.Decode(m.Encode(math.NaN()))
+	ta.True(math.IsNaN(v.(float64)))
+}
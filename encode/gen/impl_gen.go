@@ -41,6 +41,39 @@ func (c {{.Name}}) GetSize(d interface{}) int {
 func (c {{.Name}}) GetEncodedSize(b []byte) int {
 	return {{.ValLen}}
 }
+
+// FixedSize returns {{.ValLen}}.
+//
+// Since 0.5.13
+func (c {{.Name}}) FixedSize() int {
+	return {{.ValLen}}
+}
+
+// AppendEncode appends {{.ValLen}} bytes of {{.ValType}} to dst.
+//
+// Since 0.5.13
+func (c {{.Name}}) AppendEncode(dst []byte, d interface{}) []byte {
+	v := {{.EncodeCast}}(d.({{.ValType}}))
+	var b [{{.ValLen}}]byte
+	binary.LittleEndian.Put{{.Codec}}(b[:], v)
+	return append(dst, b[:]...)
+}
+
+// DecodeInto decodes {{.ValLen}} bytes into a *{{.ValType}}.
+// It returns number bytes consumed.
+//
+// Since 0.5.13
+func (c {{.Name}}) DecodeInto(b []byte, dst interface{}) int {
+	*dst.(*{{.ValType}}) = {{.ValType}}(binary.LittleEndian.{{.Codec}}(b[:{{.ValLen}}]))
+	return {{.ValLen}}
+}
+
+// Describe returns the registered name "{{.Name}}" and no params.
+//
+// Since 0.5.13
+func (c {{.Name}}) Describe() (string, []byte) {
+	return "{{.Name}}", nil
+}
 `
 
 var testHead = `package encode_test
@@ -101,6 +134,23 @@ func Test{{.Name}}(t *testing.T) {
 			t.Fatalf("%d-th: decoded size: input: %v; want: %v; actual: %v",
 				i+1, c.input, c.wantsize, n)
 		}
+
+		appended := m.AppendEncode([]byte("x"), c.input)
+		if string(appended) != "x"+c.want {
+			t.Fatalf("%d-th: append: input: %v; want: %v; actual: %v",
+				i+1, c.input, []byte("x"+c.want), appended)
+		}
+
+		var into {{.ValType}}
+		n = m.DecodeInto(rst, &into)
+		if c.input != into {
+			t.Fatalf("%d-th: decode into: input: %v; want: %v; actual: %v",
+				i+1, c.input, c.input, into)
+		}
+		if c.wantsize != n {
+			t.Fatalf("%d-th: decoded into size: input: %v; want: %v; actual: %v",
+				i+1, c.input, c.wantsize, n)
+		}
 	}
 }
 `
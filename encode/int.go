@@ -36,6 +36,39 @@ func (c U16) GetEncodedSize(b []byte) int {
 	return 2
 }
 
+// FixedSize returns 2.
+//
+// Since 0.5.13
+func (c U16) FixedSize() int {
+	return 2
+}
+
+// AppendEncode appends 2 bytes of uint16 to dst.
+//
+// Since 0.5.13
+func (c U16) AppendEncode(dst []byte, d interface{}) []byte {
+	v := d.(uint16)
+	var b [2]byte
+	binary.LittleEndian.PutUint16(b[:], v)
+	return append(dst, b[:]...)
+}
+
+// DecodeInto decodes 2 bytes into a *uint16.
+// It returns number bytes consumed.
+//
+// Since 0.5.13
+func (c U16) DecodeInto(b []byte, dst interface{}) int {
+	*dst.(*uint16) = binary.LittleEndian.Uint16(b[:2])
+	return 2
+}
+
+// Describe returns the registered name "U16" and no params.
+//
+// Since 0.5.13
+func (c U16) Describe() (string, []byte) {
+	return "U16", nil
+}
+
 // U32 converts uint32 to slice of 4 bytes and back.
 type U32 struct{}
 
@@ -68,6 +101,39 @@ func (c U32) GetEncodedSize(b []byte) int {
 	return 4
 }
 
+// FixedSize returns 4.
+//
+// Since 0.5.13
+func (c U32) FixedSize() int {
+	return 4
+}
+
+// AppendEncode appends 4 bytes of uint32 to dst.
+//
+// Since 0.5.13
+func (c U32) AppendEncode(dst []byte, d interface{}) []byte {
+	v := d.(uint32)
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], v)
+	return append(dst, b[:]...)
+}
+
+// DecodeInto decodes 4 bytes into a *uint32.
+// It returns number bytes consumed.
+//
+// Since 0.5.13
+func (c U32) DecodeInto(b []byte, dst interface{}) int {
+	*dst.(*uint32) = binary.LittleEndian.Uint32(b[:4])
+	return 4
+}
+
+// Describe returns the registered name "U32" and no params.
+//
+// Since 0.5.13
+func (c U32) Describe() (string, []byte) {
+	return "U32", nil
+}
+
 // U64 converts uint64 to slice of 8 bytes and back.
 type U64 struct{}
 
@@ -100,6 +166,39 @@ func (c U64) GetEncodedSize(b []byte) int {
 	return 8
 }
 
+// FixedSize returns 8.
+//
+// Since 0.5.13
+func (c U64) FixedSize() int {
+	return 8
+}
+
+// AppendEncode appends 8 bytes of uint64 to dst.
+//
+// Since 0.5.13
+func (c U64) AppendEncode(dst []byte, d interface{}) []byte {
+	v := d.(uint64)
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], v)
+	return append(dst, b[:]...)
+}
+
+// DecodeInto decodes 8 bytes into a *uint64.
+// It returns number bytes consumed.
+//
+// Since 0.5.13
+func (c U64) DecodeInto(b []byte, dst interface{}) int {
+	*dst.(*uint64) = binary.LittleEndian.Uint64(b[:8])
+	return 8
+}
+
+// Describe returns the registered name "U64" and no params.
+//
+// Since 0.5.13
+func (c U64) Describe() (string, []byte) {
+	return "U64", nil
+}
+
 // I16 converts int16 to slice of 2 bytes and back.
 type I16 struct{}
 
@@ -132,6 +231,39 @@ func (c I16) GetEncodedSize(b []byte) int {
 	return 2
 }
 
+// FixedSize returns 2.
+//
+// Since 0.5.13
+func (c I16) FixedSize() int {
+	return 2
+}
+
+// AppendEncode appends 2 bytes of int16 to dst.
+//
+// Since 0.5.13
+func (c I16) AppendEncode(dst []byte, d interface{}) []byte {
+	v := uint16(d.(int16))
+	var b [2]byte
+	binary.LittleEndian.PutUint16(b[:], v)
+	return append(dst, b[:]...)
+}
+
+// DecodeInto decodes 2 bytes into a *int16.
+// It returns number bytes consumed.
+//
+// Since 0.5.13
+func (c I16) DecodeInto(b []byte, dst interface{}) int {
+	*dst.(*int16) = int16(binary.LittleEndian.Uint16(b[:2]))
+	return 2
+}
+
+// Describe returns the registered name "I16" and no params.
+//
+// Since 0.5.13
+func (c I16) Describe() (string, []byte) {
+	return "I16", nil
+}
+
 // I32 converts int32 to slice of 4 bytes and back.
 type I32 struct{}
 
@@ -164,6 +296,39 @@ func (c I32) GetEncodedSize(b []byte) int {
 	return 4
 }
 
+// FixedSize returns 4.
+//
+// Since 0.5.13
+func (c I32) FixedSize() int {
+	return 4
+}
+
+// AppendEncode appends 4 bytes of int32 to dst.
+//
+// Since 0.5.13
+func (c I32) AppendEncode(dst []byte, d interface{}) []byte {
+	v := uint32(d.(int32))
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], v)
+	return append(dst, b[:]...)
+}
+
+// DecodeInto decodes 4 bytes into a *int32.
+// It returns number bytes consumed.
+//
+// Since 0.5.13
+func (c I32) DecodeInto(b []byte, dst interface{}) int {
+	*dst.(*int32) = int32(binary.LittleEndian.Uint32(b[:4]))
+	return 4
+}
+
+// Describe returns the registered name "I32" and no params.
+//
+// Since 0.5.13
+func (c I32) Describe() (string, []byte) {
+	return "I32", nil
+}
+
 // I64 converts int64 to slice of 8 bytes and back.
 type I64 struct{}
 
@@ -195,3 +360,36 @@ func (c I64) GetSize(d interface{}) int {
 func (c I64) GetEncodedSize(b []byte) int {
 	return 8
 }
+
+// FixedSize returns 8.
+//
+// Since 0.5.13
+func (c I64) FixedSize() int {
+	return 8
+}
+
+// AppendEncode appends 8 bytes of int64 to dst.
+//
+// Since 0.5.13
+func (c I64) AppendEncode(dst []byte, d interface{}) []byte {
+	v := uint64(d.(int64))
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], v)
+	return append(dst, b[:]...)
+}
+
+// DecodeInto decodes 8 bytes into a *int64.
+// It returns number bytes consumed.
+//
+// Since 0.5.13
+func (c I64) DecodeInto(b []byte, dst interface{}) int {
+	*dst.(*int64) = int64(binary.LittleEndian.Uint64(b[:8]))
+	return 8
+}
+
+// Describe returns the registered name "I64" and no params.
+//
+// Since 0.5.13
+func (c I64) Describe() (string, []byte) {
+	return "I64", nil
+}
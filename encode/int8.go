@@ -1,5 +1,61 @@
 package encode
 
+// U8 converts uint8 to slice of 1 byte and back.
+//
+// Since 0.5.13
+type U8 struct{}
+
+// Encode converts uint8 to slice of 1 byte.
+func (c U8) Encode(d interface{}) []byte {
+	return []byte{d.(uint8)}
+}
+
+// Decode converts slice of 1 byte to uint8.
+// It returns number bytes consumed and an uint8.
+func (c U8) Decode(b []byte) (int, interface{}) {
+	return 1, b[0]
+}
+
+// GetSize returns the size in byte after encoding v.
+func (c U8) GetSize(d interface{}) int {
+	return 1
+}
+
+// GetEncodedSize returns 1.
+func (c U8) GetEncodedSize(b []byte) int {
+	return 1
+}
+
+// FixedSize returns 1.
+//
+// Since 0.5.13
+func (c U8) FixedSize() int {
+	return 1
+}
+
+// AppendEncode appends 1 byte of uint8 to dst.
+//
+// Since 0.5.13
+func (c U8) AppendEncode(dst []byte, d interface{}) []byte {
+	return append(dst, d.(uint8))
+}
+
+// DecodeInto decodes 1 byte into a *uint8.
+// It returns number bytes consumed.
+//
+// Since 0.5.13
+func (c U8) DecodeInto(b []byte, dst interface{}) int {
+	*dst.(*uint8) = b[0]
+	return 1
+}
+
+// Describe returns the registered name "U8" and no params.
+//
+// Since 0.5.13
+func (c U8) Describe() (string, []byte) {
+	return "U8", nil
+}
+
 // I8 converts int8 to slice of 1 byte and back.
 type I8 struct{}
 
@@ -19,7 +75,37 @@ func (c I8) GetSize(d interface{}) int {
 	return 1
 }
 
-// GetEncodedSize returns 2.
+// GetEncodedSize returns 1.
 func (c I8) GetEncodedSize(b []byte) int {
 	return 1
 }
+
+// FixedSize returns 1.
+//
+// Since 0.5.13
+func (c I8) FixedSize() int {
+	return 1
+}
+
+// AppendEncode appends 1 byte of int8 to dst.
+//
+// Since 0.5.13
+func (c I8) AppendEncode(dst []byte, d interface{}) []byte {
+	return append(dst, byte(d.(int8)))
+}
+
+// DecodeInto decodes 1 byte into a *int8.
+// It returns number bytes consumed.
+//
+// Since 0.5.13
+func (c I8) DecodeInto(b []byte, dst interface{}) int {
+	*dst.(*int8) = int8(b[0])
+	return 1
+}
+
+// Describe returns the registered name "I8" and no params.
+//
+// Since 0.5.13
+func (c I8) Describe() (string, []byte) {
+	return "I8", nil
+}
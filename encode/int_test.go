@@ -56,6 +56,23 @@ func TestU16(t *testing.T) {
 			t.Fatalf("%d-th: decoded size: input: %v; want: %v; actual: %v",
 				i+1, c.input, c.wantsize, n)
 		}
+
+		appended := m.AppendEncode([]byte("x"), c.input)
+		if string(appended) != "x"+c.want {
+			t.Fatalf("%d-th: append: input: %v; want: %v; actual: %v",
+				i+1, c.input, []byte("x"+c.want), appended)
+		}
+
+		var into uint16
+		n = m.DecodeInto(rst, &into)
+		if c.input != into {
+			t.Fatalf("%d-th: decode into: input: %v; want: %v; actual: %v",
+				i+1, c.input, c.input, into)
+		}
+		if c.wantsize != n {
+			t.Fatalf("%d-th: decoded into size: input: %v; want: %v; actual: %v",
+				i+1, c.input, c.wantsize, n)
+		}
 	}
 }
 
@@ -107,6 +124,23 @@ func TestU32(t *testing.T) {
 			t.Fatalf("%d-th: decoded size: input: %v; want: %v; actual: %v",
 				i+1, c.input, c.wantsize, n)
 		}
+
+		appended := m.AppendEncode([]byte("x"), c.input)
+		if string(appended) != "x"+c.want {
+			t.Fatalf("%d-th: append: input: %v; want: %v; actual: %v",
+				i+1, c.input, []byte("x"+c.want), appended)
+		}
+
+		var into uint32
+		n = m.DecodeInto(rst, &into)
+		if c.input != into {
+			t.Fatalf("%d-th: decode into: input: %v; want: %v; actual: %v",
+				i+1, c.input, c.input, into)
+		}
+		if c.wantsize != n {
+			t.Fatalf("%d-th: decoded into size: input: %v; want: %v; actual: %v",
+				i+1, c.input, c.wantsize, n)
+		}
 	}
 }
 
@@ -158,6 +192,23 @@ func TestU64(t *testing.T) {
 			t.Fatalf("%d-th: decoded size: input: %v; want: %v; actual: %v",
 				i+1, c.input, c.wantsize, n)
 		}
+
+		appended := m.AppendEncode([]byte("x"), c.input)
+		if string(appended) != "x"+c.want {
+			t.Fatalf("%d-th: append: input: %v; want: %v; actual: %v",
+				i+1, c.input, []byte("x"+c.want), appended)
+		}
+
+		var into uint64
+		n = m.DecodeInto(rst, &into)
+		if c.input != into {
+			t.Fatalf("%d-th: decode into: input: %v; want: %v; actual: %v",
+				i+1, c.input, c.input, into)
+		}
+		if c.wantsize != n {
+			t.Fatalf("%d-th: decoded into size: input: %v; want: %v; actual: %v",
+				i+1, c.input, c.wantsize, n)
+		}
 	}
 }
 
@@ -209,6 +260,23 @@ func TestI16(t *testing.T) {
 			t.Fatalf("%d-th: decoded size: input: %v; want: %v; actual: %v",
 				i+1, c.input, c.wantsize, n)
 		}
+
+		appended := m.AppendEncode([]byte("x"), c.input)
+		if string(appended) != "x"+c.want {
+			t.Fatalf("%d-th: append: input: %v; want: %v; actual: %v",
+				i+1, c.input, []byte("x"+c.want), appended)
+		}
+
+		var into int16
+		n = m.DecodeInto(rst, &into)
+		if c.input != into {
+			t.Fatalf("%d-th: decode into: input: %v; want: %v; actual: %v",
+				i+1, c.input, c.input, into)
+		}
+		if c.wantsize != n {
+			t.Fatalf("%d-th: decoded into size: input: %v; want: %v; actual: %v",
+				i+1, c.input, c.wantsize, n)
+		}
 	}
 }
 
@@ -260,6 +328,23 @@ func TestI32(t *testing.T) {
 			t.Fatalf("%d-th: decoded size: input: %v; want: %v; actual: %v",
 				i+1, c.input, c.wantsize, n)
 		}
+
+		appended := m.AppendEncode([]byte("x"), c.input)
+		if string(appended) != "x"+c.want {
+			t.Fatalf("%d-th: append: input: %v; want: %v; actual: %v",
+				i+1, c.input, []byte("x"+c.want), appended)
+		}
+
+		var into int32
+		n = m.DecodeInto(rst, &into)
+		if c.input != into {
+			t.Fatalf("%d-th: decode into: input: %v; want: %v; actual: %v",
+				i+1, c.input, c.input, into)
+		}
+		if c.wantsize != n {
+			t.Fatalf("%d-th: decoded into size: input: %v; want: %v; actual: %v",
+				i+1, c.input, c.wantsize, n)
+		}
 	}
 }
 
@@ -311,5 +396,22 @@ func TestI64(t *testing.T) {
 			t.Fatalf("%d-th: decoded size: input: %v; want: %v; actual: %v",
 				i+1, c.input, c.wantsize, n)
 		}
+
+		appended := m.AppendEncode([]byte("x"), c.input)
+		if string(appended) != "x"+c.want {
+			t.Fatalf("%d-th: append: input: %v; want: %v; actual: %v",
+				i+1, c.input, []byte("x"+c.want), appended)
+		}
+
+		var into int64
+		n = m.DecodeInto(rst, &into)
+		if c.input != into {
+			t.Fatalf("%d-th: decode into: input: %v; want: %v; actual: %v",
+				i+1, c.input, c.input, into)
+		}
+		if c.wantsize != n {
+			t.Fatalf("%d-th: decoded into size: input: %v; want: %v; actual: %v",
+				i+1, c.input, c.wantsize, n)
+		}
 	}
 }
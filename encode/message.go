@@ -0,0 +1,331 @@
+package encode
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"reflect"
+
+	proto "github.com/golang/protobuf/proto"
+	"github.com/openacid/errors"
+)
+
+// Encoders in this file serialize a structured value with a general purpose
+// serialization library.
+// The serialized bytes are prefixed with its length in uvarint, thus
+// GetEncodedSize does not need to parse the value:
+//
+//	<uvarint(len(payload))><payload>
+//
+// Decode panics if the payload is corrupted, since the Encoder interface has
+// no way to report an error.
+
+// appendUvarint appends u in uvarint to dst.
+func appendUvarint(dst []byte, u uint64) []byte {
+	var l [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(l[:], u)
+	return append(dst, l[:n]...)
+}
+
+// appendVarint appends a zigzag encoded varint to dst.
+func appendVarint(dst []byte, v int64) []byte {
+	var l [binary.MaxVarintLen64]byte
+	n := binary.PutVarint(l[:], v)
+	return append(dst, l[:n]...)
+}
+
+// appendLenPrefixed appends uvarint(len(payload)) and payload to dst.
+func appendLenPrefixed(dst []byte, payload []byte) []byte {
+	dst = appendUvarint(dst, uint64(len(payload)))
+	return append(dst, payload...)
+}
+
+// readLenPrefixed returns the payload and the total number of bytes of the
+// length prefix and the payload.
+func readLenPrefixed(b []byte) ([]byte, int) {
+	l, n := binary.Uvarint(b)
+	end := n + int(l)
+	return b[n:end], end
+}
+
+// ProtoEncoder converts a protobuf message to slice of bytes and back.
+// The Decode()-ed value is a pointer to a new message of the same type as the
+// prototype.
+// Messages are marshaled deterministically, i.e., map fields are sorted, thus
+// equal messages are encoded to the same bytes.
+//
+// Since 0.5.13
+type ProtoEncoder struct {
+	// Type is the struct type of the message, i.e., not the pointer type.
+	Type reflect.Type
+}
+
+// Proto creates a *ProtoEncoder that encodes messages of the same type as
+// "prototype", such as:
+//
+//	encode.Proto(&pb.MyMessage{})
+//
+// Since 0.5.13
+func Proto(prototype proto.Message) *ProtoEncoder {
+	return &ProtoEncoder{
+		Type: reflect.TypeOf(prototype).Elem(),
+	}
+}
+
+// marshalProto marshals a protobuf message with map fields sorted.
+func marshalProto(m proto.Message) ([]byte, error) {
+	b := proto.NewBuffer(nil)
+	b.SetDeterministic(true)
+	err := b.Marshal(m)
+	return b.Bytes(), err
+}
+
+// Encode converts a protobuf message to slice of bytes.
+func (c *ProtoEncoder) Encode(d interface{}) []byte {
+	payload, err := marshalProto(d.(proto.Message))
+	if err != nil {
+		panic(errors.Wrapf(err, "failure to encode proto"))
+	}
+	return appendLenPrefixed(nil, payload)
+}
+
+// Decode converts slice of bytes to a protobuf message.
+// It returns number bytes consumed and a pointer to a new message.
+func (c *ProtoEncoder) Decode(b []byte) (int, interface{}) {
+	payload, n := readLenPrefixed(b)
+	m := reflect.New(c.Type).Interface().(proto.Message)
+	err := proto.Unmarshal(payload, m)
+	if err != nil {
+		panic(errors.Wrapf(err, "failure to decode proto"))
+	}
+	return n, m
+}
+
+// GetSize returns the size in byte after encoding v.
+func (c *ProtoEncoder) GetSize(d interface{}) int {
+	return uvarintPrefixedSize(proto.Size(d.(proto.Message)))
+}
+
+// GetEncodedSize returns size of the encoded value.
+func (c *ProtoEncoder) GetEncodedSize(b []byte) int {
+	_, n := readLenPrefixed(b)
+	return n
+}
+
+// AppendEncode appends the length prefixed protobuf message to dst.
+//
+// Since 0.5.13
+func (c *ProtoEncoder) AppendEncode(dst []byte, d interface{}) []byte {
+	payload, err := marshalProto(d.(proto.Message))
+	if err != nil {
+		panic(errors.Wrapf(err, "failure to encode proto"))
+	}
+	return appendLenPrefixed(dst, payload)
+}
+
+// DecodeInto unmarshals into "dst", which is a message of the same type as
+// the prototype, e.g., a *pb.MyMessage.
+// It returns number bytes consumed.
+//
+// Since 0.5.13
+func (c *ProtoEncoder) DecodeInto(b []byte, dst interface{}) int {
+	payload, n := readLenPrefixed(b)
+	err := proto.Unmarshal(payload, dst.(proto.Message))
+	if err != nil {
+		panic(errors.Wrapf(err, "failure to decode proto"))
+	}
+	return n
+}
+
+// Describe returns the registered name "Proto" and the full protobuf message
+// name as params.
+// The message type must be registered to protobuf, which is done by generated
+// code.
+//
+// Since 0.5.13
+func (c *ProtoEncoder) Describe() (string, []byte) {
+	m := reflect.New(c.Type).Interface().(proto.Message)
+	return "Proto", []byte(proto.MessageName(m))
+}
+
+// JSONEncoder converts a value to slice of bytes in JSON and back.
+// The Decode()-ed value is of type Type.
+//
+// Since 0.5.13
+type JSONEncoder struct {
+	// Type is the type of value to decode into.
+	Type reflect.Type
+}
+
+// JSON creates a *JSONEncoder that decodes values into type "t".
+//
+// Since 0.5.13
+func JSON(t reflect.Type) *JSONEncoder {
+	return &JSONEncoder{Type: t}
+}
+
+// Encode converts a value to slice of bytes with json.Marshal.
+func (c *JSONEncoder) Encode(d interface{}) []byte {
+	payload, err := json.Marshal(d)
+	if err != nil {
+		panic(errors.Wrapf(err, "failure to encode json"))
+	}
+	return appendLenPrefixed(nil, payload)
+}
+
+// Decode converts slice of bytes to a value of type c.Type.
+// It returns number bytes consumed and the value.
+func (c *JSONEncoder) Decode(b []byte) (int, interface{}) {
+	payload, n := readLenPrefixed(b)
+	v := reflect.New(c.Type)
+	err := json.Unmarshal(payload, v.Interface())
+	if err != nil {
+		panic(errors.Wrapf(err, "failure to decode json"))
+	}
+	return n, v.Elem().Interface()
+}
+
+// GetSize returns the size in byte after encoding v.
+// It has to encode v to find out the size.
+func (c *JSONEncoder) GetSize(d interface{}) int {
+	return len(c.Encode(d))
+}
+
+// GetEncodedSize returns size of the encoded value.
+func (c *JSONEncoder) GetEncodedSize(b []byte) int {
+	_, n := readLenPrefixed(b)
+	return n
+}
+
+// AppendEncode appends the length prefixed JSON to dst.
+//
+// Since 0.5.13
+func (c *JSONEncoder) AppendEncode(dst []byte, d interface{}) []byte {
+	payload, err := json.Marshal(d)
+	if err != nil {
+		panic(errors.Wrapf(err, "failure to encode json"))
+	}
+	return appendLenPrefixed(dst, payload)
+}
+
+// DecodeInto decodes into "dst", which is a pointer to c.Type.
+// It returns number bytes consumed.
+//
+// Since 0.5.13
+func (c *JSONEncoder) DecodeInto(b []byte, dst interface{}) int {
+	payload, n := readLenPrefixed(b)
+	v := reflect.ValueOf(dst).Elem()
+	v.Set(reflect.Zero(c.Type))
+	err := json.Unmarshal(payload, dst)
+	if err != nil {
+		panic(errors.Wrapf(err, "failure to decode json"))
+	}
+	return n
+}
+
+// Describe returns the registered name "JSON" and the type name as params.
+// The type must be registered with RegisterType to rebuild it with New.
+//
+// Since 0.5.13
+func (c *JSONEncoder) Describe() (string, []byte) {
+	return "JSON", []byte(typeName(c.Type))
+}
+
+// GobEncoder converts a value to slice of bytes with encoding/gob and back.
+// Every encoded value carries its own gob type information, thus a value can
+// be decoded alone.
+// The Decode()-ed value is of type Type.
+//
+// It is not deterministic for a value containing a map: encoding/gob encodes
+// map entries in random order. Thus a SlimTrie of such values built twice
+// may marshal to different bytes and have different Digest.
+// Use JSONEncoder, which sorts map keys, or ProtoEncoder for such values.
+//
+// Since 0.5.13
+type GobEncoder struct {
+	// Type is the type of value to decode into.
+	Type reflect.Type
+}
+
+// Gob creates a *GobEncoder that decodes values into type "t".
+//
+// Since 0.5.13
+func Gob(t reflect.Type) *GobEncoder {
+	return &GobEncoder{Type: t}
+}
+
+// Encode converts a value to slice of bytes with a gob.Encoder.
+func (c *GobEncoder) Encode(d interface{}) []byte {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(d)
+	if err != nil {
+		panic(errors.Wrapf(err, "failure to encode gob"))
+	}
+	return appendLenPrefixed(nil, buf.Bytes())
+}
+
+// Decode converts slice of bytes to a value of type c.Type.
+// It returns number bytes consumed and the value.
+func (c *GobEncoder) Decode(b []byte) (int, interface{}) {
+	payload, n := readLenPrefixed(b)
+	v := reflect.New(c.Type)
+	err := gob.NewDecoder(bytes.NewReader(payload)).DecodeValue(v)
+	if err != nil {
+		panic(errors.Wrapf(err, "failure to decode gob"))
+	}
+	return n, v.Elem().Interface()
+}
+
+// GetSize returns the size in byte after encoding v.
+// It has to encode v to find out the size.
+func (c *GobEncoder) GetSize(d interface{}) int {
+	return len(c.Encode(d))
+}
+
+// GetEncodedSize returns size of the encoded value.
+func (c *GobEncoder) GetEncodedSize(b []byte) int {
+	_, n := readLenPrefixed(b)
+	return n
+}
+
+// AppendEncode appends the length prefixed gob data to dst.
+//
+// Since 0.5.13
+func (c *GobEncoder) AppendEncode(dst []byte, d interface{}) []byte {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(d)
+	if err != nil {
+		panic(errors.Wrapf(err, "failure to encode gob"))
+	}
+	return appendLenPrefixed(dst, buf.Bytes())
+}
+
+// DecodeInto decodes into "dst", which is a pointer to c.Type.
+// It returns number bytes consumed.
+//
+// Since 0.5.13
+func (c *GobEncoder) DecodeInto(b []byte, dst interface{}) int {
+	payload, n := readLenPrefixed(b)
+	v := reflect.ValueOf(dst).Elem()
+	v.Set(reflect.Zero(c.Type))
+	err := gob.NewDecoder(bytes.NewReader(payload)).Decode(dst)
+	if err != nil {
+		panic(errors.Wrapf(err, "failure to decode gob"))
+	}
+	return n
+}
+
+// Describe returns the registered name "Gob" and the type name as params.
+// The type must be registered with RegisterType to rebuild it with New.
+//
+// Since 0.5.13
+func (c *GobEncoder) Describe() (string, []byte) {
+	return "Gob", []byte(typeName(c.Type))
+}
+
+// uvarintPrefixedSize returns the size of a payload of size l with its
+// uvarint length prefix.
+func uvarintPrefixedSize(l int) int {
+	return uvarintSize(uint64(l)) + l
+}
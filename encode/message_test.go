@@ -0,0 +1,118 @@
+package encode_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	proto "github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/openacid/slim/encode"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+type record struct {
+	Name  string
+	Score int64
+	Tags  []string
+}
+
+func TestProto(t *testing.T) {
+
+	ta := require.New(t)
+
+	m := encode.Proto(&wrappers.StringValue{})
+
+	for _, s := range []string{"", "a", "hello world"} {
+		input := &wrappers.StringValue{Value: s}
+
+		rst := m.Encode(input)
+		ta.Equal(len(rst), m.GetSize(input))
+		ta.Equal(len(rst), m.GetEncodedSize(append(rst, 1, 2, 3)))
+
+		n, v := m.Decode(append(rst, 1, 2, 3))
+		ta.Equal(len(rst), n)
+		ta.IsType(&wrappers.StringValue{}, v)
+		ta.True(proto.Equal(input, v.(proto.Message)), "decode %q", s)
+	}
+
+	ta.Panics(func() { m.Decode([]byte{2, 0xff, 0xff}) })
+}
+
+func TestProto_deterministic(t *testing.T) {
+
+	ta := require.New(t)
+
+	fields := map[string]interface{}{}
+	for i := 0; i < 100; i++ {
+		fields[fmt.Sprintf("k%d", i)] = i
+	}
+	input, err := structpb.NewStruct(fields)
+	ta.NoError(err)
+
+	m := encode.Proto(&structpb.Struct{})
+	want := m.Encode(input)
+
+	for i := 0; i < 10; i++ {
+		ta.Equal(want, m.Encode(input))
+		ta.Equal(want, m.AppendEncode(nil, input))
+	}
+}
+
+func TestJSON(t *testing.T) {
+
+	ta := require.New(t)
+
+	cases := []struct {
+		typ   reflect.Type
+		input interface{}
+	}{
+		{reflect.TypeOf(record{}), record{Name: "a", Score: -1, Tags: []string{"x", "y"}}},
+		{reflect.TypeOf(&record{}), &record{Name: "b", Score: 3}},
+		{reflect.TypeOf(map[string]int{}), map[string]int{"x": 1}},
+		{reflect.TypeOf(""), "foo"},
+	}
+
+	for _, c := range cases {
+		m := encode.JSON(c.typ)
+
+		rst := m.Encode(c.input)
+		ta.Equal(len(rst), m.GetSize(c.input))
+		ta.Equal(len(rst), m.GetEncodedSize(rst))
+
+		n, v := m.Decode(append(rst, 'x'))
+		ta.Equal(len(rst), n)
+		ta.Equal(c.input, v)
+	}
+
+	rst := encode.JSON(reflect.TypeOf(record{})).Encode(record{Name: "a"})
+	ta.Equal(`"{"Name":"a","Score":0,"Tags":null}`, string(rst))
+}
+
+func TestGob(t *testing.T) {
+
+	ta := require.New(t)
+
+	cases := []struct {
+		typ   reflect.Type
+		input interface{}
+	}{
+		{reflect.TypeOf(record{}), record{Name: "a", Score: -1, Tags: []string{"x", "y"}}},
+		{reflect.TypeOf(map[string]int{}), map[string]int{"x": 1}},
+		{reflect.TypeOf(""), "foo"},
+		{reflect.TypeOf(int64(0)), int64(-5)},
+	}
+
+	for _, c := range cases {
+		m := encode.Gob(c.typ)
+
+		rst := m.Encode(c.input)
+		ta.Equal(len(rst), m.GetSize(c.input))
+		ta.Equal(len(rst), m.GetEncodedSize(rst))
+
+		n, v := m.Decode(append(rst, 'x'))
+		ta.Equal(len(rst), n)
+		ta.Equal(c.input, v)
+	}
+}
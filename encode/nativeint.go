@@ -51,3 +51,41 @@ func (c Int) GetSize(d interface{}) int {
 func (c Int) GetEncodedSize(b []byte) int {
 	return bits.UintSize / 8
 }
+
+// FixedSize returns the size of a native int.
+//
+// Since 0.5.13
+func (c Int) FixedSize() int {
+	return bits.UintSize / 8
+}
+
+// AppendEncode appends native int size bytes of int to dst.
+//
+// Since 0.5.13
+func (c Int) AppendEncode(dst []byte, d interface{}) []byte {
+	var b [8]byte
+	size := bits.UintSize / 8
+	binary.LittleEndian.PutUint64(b[:], uint64(d.(int)))
+	return append(dst, b[:size]...)
+}
+
+// DecodeInto decodes native int size bytes into a *int.
+// It returns number bytes consumed.
+//
+// Since 0.5.13
+func (c Int) DecodeInto(b []byte, dst interface{}) int {
+	size := bits.UintSize / 8
+	if size == 4 {
+		*dst.(*int) = int(int32(binary.LittleEndian.Uint32(b[:4])))
+	} else {
+		*dst.(*int) = int(binary.LittleEndian.Uint64(b[:8]))
+	}
+	return size
+}
+
+// Describe returns the registered name "Int" and no params.
+//
+// Since 0.5.13
+func (c Int) Describe() (string, []byte) {
+	return "Int", nil
+}
@@ -0,0 +1,295 @@
+package encode
+
+import (
+	"compress/flate"
+	"encoding/binary"
+	"math"
+	"reflect"
+	"sync"
+
+	proto "github.com/golang/protobuf/proto"
+	"github.com/openacid/errors"
+)
+
+// Describer is implemented by an Encoder that can be rebuilt by New with the
+// name and params it returns.
+// All built-in encoders implement it.
+//
+// Since 0.5.13
+type Describer interface {
+	// Describe returns the registered name and the params to rebuild this
+	// encoder.
+	// An empty name means it can not be described.
+	Describe() (name string, params []byte)
+}
+
+// Factory creates an Encoder from the params returned by Describe.
+//
+// Since 0.5.13
+type Factory func(params []byte) (Encoder, error)
+
+var (
+	registryMu sync.RWMutex
+	factories  = map[string]Factory{}
+	types      = map[string]reflect.Type{}
+)
+
+// Register makes an encoder available by "name" to New.
+// It panics if "name" is empty, is already registered or "f" is nil.
+//
+// Since 0.5.13
+func Register(name string, f Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if name == "" {
+		panic("encode: Register with empty name")
+	}
+	if f == nil {
+		panic("encode: Register with nil Factory: " + name)
+	}
+	if _, ok := factories[name]; ok {
+		panic("encode: Register twice: " + name)
+	}
+	factories[name] = f
+}
+
+// RegisterType records the type of "v" so that an encoder of this type, such
+// as JSONEncoder, GobEncoder, StructEncoder or TypeEncoder, can be rebuilt by
+// New.
+// It is similar to gob.Register.
+//
+// Since 0.5.13
+func RegisterType(v interface{}) {
+	t := reflect.TypeOf(v)
+	name := typeName(t)
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if prev, ok := types[name]; ok && prev != t {
+		panic("encode: RegisterType with different types of the same name: " + name)
+	}
+	types[name] = t
+}
+
+// New creates an encoder by the name and params returned by Describe.
+// It returns an error with cause ErrUnknownEncoder if "name" is not
+// registered.
+//
+// Since 0.5.13
+func New(name string, params []byte) (Encoder, error) {
+	registryMu.RLock()
+	f, ok := factories[name]
+	registryMu.RUnlock()
+
+	if !ok {
+		return nil, errors.Wrapf(ErrUnknownEncoder, "name: %q", name)
+	}
+
+	e, err := f(params)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failure to create encoder: %q", name)
+	}
+	return e, nil
+}
+
+// Describe returns the name and params to rebuild "e" with New.
+// It returns an error with cause ErrUnknownEncoder if "e" does not implement
+// Describer or can not be described.
+//
+// Since 0.5.13
+func Describe(e Encoder) (string, []byte, error) {
+	if d, ok := e.(Describer); ok {
+		name, params := d.Describe()
+		if name != "" {
+			return name, params, nil
+		}
+	}
+	return "", nil, errors.Wrapf(ErrUnknownEncoder, "type: %T", e)
+}
+
+// typeName returns the package qualified name of a type.
+func typeName(t reflect.Type) string {
+	if t.Name() != "" && t.PkgPath() != "" {
+		return t.PkgPath() + "." + t.Name()
+	}
+	return t.String()
+}
+
+// lookupType returns a type registered by RegisterType.
+func lookupType(name string) (reflect.Type, error) {
+	registryMu.RLock()
+	t, ok := types[name]
+	registryMu.RUnlock()
+
+	if !ok {
+		return nil, errors.Wrapf(ErrUnknownEncoder, "type is not registered: %q", name)
+	}
+	return t, nil
+}
+
+// readUvarintParam reads a uvarint from params and returns the remaining.
+func readUvarintParam(params []byte) (uint64, []byte, error) {
+	v, n := binary.Uvarint(params)
+	if n <= 0 {
+		return 0, nil, errors.Wrapf(ErrInvalidParams, "malformed uvarint")
+	}
+	return v, params[n:], nil
+}
+
+// readVarintParam reads a zigzag encoded varint from params and returns the
+// remaining.
+func readVarintParam(params []byte) (int64, []byte, error) {
+	v, n := binary.Varint(params)
+	if n <= 0 {
+		return 0, nil, errors.Wrapf(ErrInvalidParams, "malformed varint")
+	}
+	return v, params[n:], nil
+}
+
+// readSizeParam reads a uvarint size from params, which must fit in an int32.
+func readSizeParam(params []byte) (int, error) {
+	v, _, err := readUvarintParam(params)
+	if err != nil {
+		return 0, err
+	}
+	if v > math.MaxInt32 {
+		return 0, errors.Wrapf(ErrInvalidParams, "size too large: %d", v)
+	}
+	return int(v), nil
+}
+
+// readBytesParam reads a length prefixed []byte from params and returns the
+// remaining.
+func readBytesParam(params []byte) ([]byte, []byte, error) {
+	l, rest, err := readUvarintParam(params)
+	if err != nil {
+		return nil, nil, err
+	}
+	if uint64(len(rest)) < l {
+		return nil, nil, errors.Wrapf(ErrInvalidParams, "params too short")
+	}
+	return rest[:l], rest[l:], nil
+}
+
+func init() {
+
+	for _, e := range []Encoder{
+		U8{}, I8{}, U16{}, U32{}, U64{}, I16{}, I32{}, I64{},
+		Int{}, Bool{}, F32{}, F64{}, UVarint{}, Varint{},
+		String16{}, String32{}, StringVarint{},
+	} {
+		e := e
+		name, _ := e.(Describer).Describe()
+		Register(name, func(params []byte) (Encoder, error) {
+			return e, nil
+		})
+	}
+
+	Register("Bytes", func(params []byte) (Encoder, error) {
+		size, err := readSizeParam(params)
+		if err != nil {
+			return nil, err
+		}
+		return Bytes{Size: size}, nil
+	})
+
+	Register("Dummy", func(params []byte) (Encoder, error) {
+		size, err := readSizeParam(params)
+		if err != nil {
+			return nil, err
+		}
+		return Dummy{Size: size}, nil
+	})
+
+	Register("FixedBytes", func(params []byte) (Encoder, error) {
+		size, err := readSizeParam(params)
+		if err != nil {
+			return nil, err
+		}
+		return FixedBytes(size), nil
+	})
+
+	Register("Proto", func(params []byte) (Encoder, error) {
+		t := proto.MessageType(string(params))
+		if t == nil {
+			return nil, errors.Wrapf(ErrUnknownEncoder, "proto message is not registered: %q", params)
+		}
+		return Proto(reflect.New(t.Elem()).Interface().(proto.Message)), nil
+	})
+
+	Register("JSON", func(params []byte) (Encoder, error) {
+		t, err := lookupType(string(params))
+		if err != nil {
+			return nil, err
+		}
+		return JSON(t), nil
+	})
+
+	Register("Gob", func(params []byte) (Encoder, error) {
+		t, err := lookupType(string(params))
+		if err != nil {
+			return nil, err
+		}
+		return Gob(t), nil
+	})
+
+	Register("Struct", func(params []byte) (Encoder, error) {
+		t, err := lookupType(string(params))
+		if err != nil {
+			return nil, err
+		}
+		return NewStructEncoderByType(t)
+	})
+
+	Register("Type", func(params []byte) (Encoder, error) {
+		if len(params) == 0 {
+			return nil, errors.Wrapf(ErrInvalidParams, "empty params")
+		}
+
+		var endian binary.ByteOrder = binary.LittleEndian
+		if params[0] == 1 {
+			endian = binary.BigEndian
+		}
+
+		t, err := lookupType(string(params[1:]))
+		if err != nil {
+			return nil, err
+		}
+		return NewTypeEncoderEndianByType(t, endian)
+	})
+
+	Register("Compressed", func(params []byte) (Encoder, error) {
+		level, rest, err := readVarintParam(params)
+		if err != nil {
+			return nil, err
+		}
+
+		dict, rest, err := readBytesParam(rest)
+		if err != nil {
+			return nil, err
+		}
+		if len(dict) == 0 {
+			dict = nil
+		}
+
+		innerName, innerParams, err := readBytesParam(rest)
+		if err != nil {
+			return nil, err
+		}
+
+		var inner Encoder
+		if len(innerName) > 0 {
+			inner, err = New(string(innerName), innerParams)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if level < flate.HuffmanOnly || level > flate.BestCompression {
+			return nil, errors.Wrapf(ErrInvalidParams, "invalid level: %d", level)
+		}
+		return CompressedDict(inner, int(level), dict), nil
+	})
+}
@@ -0,0 +1,185 @@
+package encode_test
+
+import (
+	"compress/flate"
+	"encoding/binary"
+	"reflect"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/openacid/errors"
+	"github.com/openacid/slim/encode"
+	"github.com/stretchr/testify/require"
+)
+
+type registeredRecord struct {
+	Name string
+	N    int32
+}
+
+type registeredFixed struct {
+	X int32
+	Y uint16
+}
+
+func init() {
+	encode.RegisterType(registeredRecord{})
+	encode.RegisterType(registeredFixed{})
+}
+
+func TestRegistry_builtin(t *testing.T) {
+
+	ta := require.New(t)
+
+	structEnc, err := encode.NewStructEncoder(registeredRecord{})
+	ta.NoError(err)
+
+	typeEnc, err := encode.NewTypeEncoderEndian(registeredFixed{}, binary.BigEndian)
+	ta.NoError(err)
+
+	cases := []struct {
+		e     encode.Encoder
+		input interface{}
+	}{
+		{encode.U8{}, uint8(3)},
+		{encode.I8{}, int8(-3)},
+		{encode.U16{}, uint16(300)},
+		{encode.U32{}, uint32(300)},
+		{encode.U64{}, uint64(300)},
+		{encode.I16{}, int16(-300)},
+		{encode.I32{}, int32(-300)},
+		{encode.I64{}, int64(-300)},
+		{encode.Int{}, -300},
+		{encode.Bool{}, true},
+		{encode.F32{}, float32(1.5)},
+		{encode.F64{}, -2.5},
+		{encode.UVarint{}, uint64(300)},
+		{encode.Varint{}, int64(-300)},
+		{encode.String16{}, "foo"},
+		{encode.String32{}, "foo"},
+		{encode.StringVarint{}, "foo"},
+		{encode.Bytes{Size: 3}, []byte("foo")},
+		{encode.FixedBytes(3), []byte("foo")},
+		{encode.Dummy{Size: 0}, nil},
+		{encode.Proto(&wrappers.StringValue{}), &wrappers.StringValue{Value: "foo"}},
+		{encode.JSON(reflect.TypeOf(registeredRecord{})), registeredRecord{"foo", 1}},
+		{encode.Gob(reflect.TypeOf(registeredRecord{})), registeredRecord{"foo", 1}},
+		{structEnc, registeredRecord{"foo", 1}},
+		{typeEnc, registeredFixed{1, 2}},
+		{encode.Compressed(nil, flate.HuffmanOnly), []byte("foo")},
+		{encode.Compressed(encode.StringVarint{}, flate.DefaultCompression), "foo"},
+		{encode.CompressedDict(encode.StringVarint{}, flate.BestCompression, []byte("foofoo")), "foo"},
+	}
+
+	for _, c := range cases {
+
+		name, params, err := encode.Describe(c.e)
+		ta.NoError(err, "%T", c.e)
+		ta.NotEqual("", name)
+
+		e, err := encode.New(name, params)
+		ta.NoError(err, "%T", c.e)
+		ta.IsType(c.e, e)
+
+		name2, params2, err := encode.Describe(e)
+		ta.NoError(err)
+		ta.Equal(name, name2)
+		ta.Equal(params, params2)
+
+		if c.input != nil {
+			b := c.e.Encode(c.input)
+			ta.Equal(b, e.Encode(c.input), "%T", c.e)
+
+			_, want := c.e.Decode(b)
+			_, got := e.Decode(b)
+			ta.Equal(want, got, "%T", c.e)
+		}
+	}
+}
+
+func TestRegistry_compressedLevel(t *testing.T) {
+
+	ta := require.New(t)
+
+	// A level is zigzag encoded, a negative level takes 1 byte.
+
+	cases := []struct {
+		level int
+		want  byte
+	}{
+		{flate.HuffmanOnly, 3},
+		{flate.DefaultCompression, 1},
+		{flate.NoCompression, 0},
+		{flate.BestCompression, 18},
+	}
+
+	for _, c := range cases {
+
+		name, params, err := encode.Describe(encode.Compressed(nil, c.level))
+		ta.NoError(err)
+		ta.Equal(c.want, params[0], "level: %d", c.level)
+
+		e, err := encode.New(name, params)
+		ta.NoError(err)
+		ta.Equal(c.level, e.(*encode.CompressedEncoder).Level)
+	}
+
+	// out of range
+
+	_, err := encode.New("Compressed", []byte{20, 0, 0})
+	ta.Equal(encode.ErrInvalidParams, errors.Cause(err))
+}
+
+func TestRegistry_user(t *testing.T) {
+
+	ta := require.New(t)
+
+	type myEncoder struct{ encode.U32 }
+
+	encode.Register("test-my-encoder", func(params []byte) (encode.Encoder, error) {
+		return myEncoder{}, nil
+	})
+
+	e, err := encode.New("test-my-encoder", nil)
+	ta.NoError(err)
+	ta.Equal(myEncoder{}, e)
+
+	ta.Panics(func() {
+		encode.Register("test-my-encoder", func(params []byte) (encode.Encoder, error) { return nil, nil })
+	})
+	ta.Panics(func() { encode.Register("", func(params []byte) (encode.Encoder, error) { return nil, nil }) })
+	ta.Panics(func() { encode.Register("test-nil", nil) })
+}
+
+func TestRegistry_unknown(t *testing.T) {
+
+	ta := require.New(t)
+
+	_, err := encode.New("no-such-encoder", nil)
+	ta.Equal(encode.ErrUnknownEncoder, errors.Cause(err))
+
+	_, err = encode.New("JSON", []byte("no-such-type"))
+	ta.Equal(encode.ErrUnknownEncoder, errors.Cause(err))
+
+	_, err = encode.New("Proto", []byte("no.such.Message"))
+	ta.Equal(encode.ErrUnknownEncoder, errors.Cause(err))
+
+	_, err = encode.New("Bytes", []byte{0x80})
+	ta.Equal(encode.ErrInvalidParams, errors.Cause(err))
+
+	_, err = encode.New("FixedBytes", []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0x01})
+	ta.Equal(encode.ErrInvalidParams, errors.Cause(err))
+
+	_, err = encode.New("Compressed", []byte{100, 0, 0})
+	ta.Equal(encode.ErrInvalidParams, errors.Cause(err))
+
+	// not a Describer
+
+	_, _, err = encode.Describe(struct{ encode.Encoder }{encode.U32{}})
+	ta.Equal(encode.ErrUnknownEncoder, errors.Cause(err))
+
+	// inner not describable
+
+	_, _, err = encode.Describe(encode.Compressed(struct{ encode.Encoder }{encode.U32{}}, 1))
+	ta.Equal(encode.ErrUnknownEncoder, errors.Cause(err))
+}
@@ -0,0 +1,223 @@
+package encode
+
+import (
+	"encoding/binary"
+)
+
+// String32 converts string to slice of bytes and back.
+// The length of string is stored in the first 4 bytes in big endian, the same
+// as String16 does with 2 bytes.
+// It supports a string up to 4GB.
+//
+// Since 0.5.13
+type String32 struct{}
+
+// Encode converts string to slice of 4 + len(string) bytes.
+func (s String32) Encode(d interface{}) []byte {
+	ss := d.(string)
+	l := len(ss)
+	rst := make([]byte, 4, 4+l)
+	binary.BigEndian.PutUint32(rst, uint32(l))
+	return append(rst, ss...)
+}
+
+// Decode converts slice of bytes to string.
+// It returns number bytes consumed and a string.
+func (s String32) Decode(b []byte) (int, interface{}) {
+	l := int(binary.BigEndian.Uint32(b))
+	ss := string(b[4 : 4+l])
+	return 4 + l, ss
+}
+
+// GetSize returns number of byte required to encode a string.
+// It is len(str) + 4;
+func (s String32) GetSize(d interface{}) int {
+	ss := d.(string)
+	return 4 + len(ss)
+}
+
+// GetEncodedSize returned size of encoded data.
+func (s String32) GetEncodedSize(b []byte) int {
+	l := int(binary.BigEndian.Uint32(b))
+	return 4 + l
+}
+
+// AppendEncode appends 4 bytes length and the string to dst.
+//
+// Since 0.5.13
+func (s String32) AppendEncode(dst []byte, d interface{}) []byte {
+	ss := d.(string)
+	var l [4]byte
+	binary.BigEndian.PutUint32(l[:], uint32(len(ss)))
+	dst = append(dst, l[:]...)
+	return append(dst, ss...)
+}
+
+// DecodeInto decodes into a *string or a *[]byte.
+// Decoding into a *[]byte does not allocate: it references b.
+// It returns number bytes consumed.
+//
+// Since 0.5.13
+func (s String32) DecodeInto(b []byte, dst interface{}) int {
+	l := int(binary.BigEndian.Uint32(b))
+	decodeStringInto(b[4:4+l], dst)
+	return 4 + l
+}
+
+// Describe returns the registered name "String32" and no params.
+//
+// Since 0.5.13
+func (s String32) Describe() (string, []byte) {
+	return "String32", nil
+}
+
+// StringVarint converts string to slice of bytes and back.
+// The length of string is stored as a uvarint before the string content.
+// Thus a short string less than 128 bytes costs only 1 extra byte.
+//
+// Since 0.5.13
+type StringVarint struct{}
+
+// Encode converts string to slice of bytes.
+func (s StringVarint) Encode(d interface{}) []byte {
+	ss := d.(string)
+	l := len(ss)
+	rst := make([]byte, binary.MaxVarintLen64, binary.MaxVarintLen64+l)
+	n := binary.PutUvarint(rst, uint64(l))
+	return append(rst[:n], ss...)
+}
+
+// Decode converts slice of bytes to string.
+// It returns number bytes consumed and a string.
+func (s StringVarint) Decode(b []byte) (int, interface{}) {
+	l, n := binary.Uvarint(b)
+	end := n + int(l)
+	return end, string(b[n:end])
+}
+
+// GetSize returns number of byte required to encode a string.
+// It is len(str) + size of uvarint of len(str).
+func (s StringVarint) GetSize(d interface{}) int {
+	ss := d.(string)
+	return uvarintSize(uint64(len(ss))) + len(ss)
+}
+
+// GetEncodedSize returned size of encoded data.
+func (s StringVarint) GetEncodedSize(b []byte) int {
+	l, n := binary.Uvarint(b)
+	return n + int(l)
+}
+
+// AppendEncode appends uvarint length and the string to dst.
+//
+// Since 0.5.13
+func (s StringVarint) AppendEncode(dst []byte, d interface{}) []byte {
+	ss := d.(string)
+	dst = appendUvarint(dst, uint64(len(ss)))
+	return append(dst, ss...)
+}
+
+// DecodeInto decodes into a *string or a *[]byte.
+// Decoding into a *[]byte does not allocate: it references b.
+// It returns number bytes consumed.
+//
+// Since 0.5.13
+func (s StringVarint) DecodeInto(b []byte, dst interface{}) int {
+	str, n := readLenPrefixed(b)
+	decodeStringInto(str, dst)
+	return n
+}
+
+// Describe returns the registered name "StringVarint" and no params.
+//
+// Since 0.5.13
+func (s StringVarint) Describe() (string, []byte) {
+	return "StringVarint", nil
+}
+
+// FixedBytes converts a byte slice of exactly n bytes to byte slice and back,
+// where n is the value of FixedBytes, e.g., FixedBytes(16) for a 16-byte
+// digest.
+//
+// Unlike Bytes, Encode panics if the input is not of n bytes, because there is
+// no way to find the length of a shorter input when decoding.
+//
+// Since 0.5.13
+type FixedBytes int
+
+// Encode copies the input byte slice.
+// It panics if the length of input is not n.
+func (c FixedBytes) Encode(d interface{}) []byte {
+	b := d.([]byte)
+	if len(b) != int(c) {
+		panic("FixedBytes: input length mismatch")
+	}
+	rst := make([]byte, len(b))
+	copy(rst, b)
+	return rst
+}
+
+// Decode returns the first n bytes of b.
+// The returned bytes are NOT copied.
+func (c FixedBytes) Decode(b []byte) (int, interface{}) {
+	return int(c), b[:c]
+}
+
+// GetSize returns n.
+func (c FixedBytes) GetSize(d interface{}) int {
+	return int(c)
+}
+
+// GetEncodedSize returns n.
+func (c FixedBytes) GetEncodedSize(b []byte) int {
+	return int(c)
+}
+
+// FixedSize returns the length of the byte slice.
+//
+// Since 0.5.13
+func (c FixedBytes) FixedSize() int {
+	return int(c)
+}
+
+// AppendEncode appends the input byte slice to dst.
+// It panics if the length of input is not n.
+//
+// Since 0.5.13
+func (c FixedBytes) AppendEncode(dst []byte, d interface{}) []byte {
+	b := d.([]byte)
+	if len(b) != int(c) {
+		panic("FixedBytes: input length mismatch")
+	}
+	return append(dst, b...)
+}
+
+// DecodeInto stores the first n bytes into a *[]byte.
+// The bytes are NOT copied.
+// It returns number bytes consumed.
+//
+// Since 0.5.13
+func (c FixedBytes) DecodeInto(b []byte, dst interface{}) int {
+	*dst.(*[]byte) = b[:c]
+	return int(c)
+}
+
+// Describe returns the registered name "FixedBytes" and n in uvarint as params.
+//
+// Since 0.5.13
+func (c FixedBytes) Describe() (string, []byte) {
+	return "FixedBytes", appendUvarint(nil, uint64(c))
+}
+
+// decodeStringInto stores the string content "s" into "dst", which is a
+// *string or a *[]byte.
+func decodeStringInto(s []byte, dst interface{}) {
+	switch p := dst.(type) {
+	case *string:
+		*p = string(s)
+	case *[]byte:
+		*p = s
+	default:
+		panic("dst must be a *string or a *[]byte")
+	}
+}
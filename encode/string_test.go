@@ -0,0 +1,97 @@
+package encode_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/openacid/slim/encode"
+	"github.com/stretchr/testify/require"
+)
+
+func TestString32(t *testing.T) {
+
+	ta := require.New(t)
+
+	big := strings.Repeat("x", 1<<16+1)
+
+	cases := []struct {
+		input string
+		want  int
+	}{
+		{"", 4},
+		{"a", 5},
+		{"abc", 7},
+		{big, 4 + 1<<16 + 1},
+	}
+
+	m := encode.String32{}
+
+	for _, c := range cases {
+		rst := m.Encode(c.input)
+		ta.Equal(c.want, len(rst))
+		ta.Equal(c.want, m.GetSize(c.input))
+		ta.Equal(c.want, m.GetEncodedSize(rst))
+
+		n, s := m.Decode(append(rst, 'z'))
+		ta.Equal(c.want, n)
+		ta.Equal(c.input, s)
+	}
+
+	ta.Equal("\x00\x00\x00\x03abc", string(m.Encode("abc")))
+}
+
+func TestStringVarint(t *testing.T) {
+
+	ta := require.New(t)
+
+	cases := []struct {
+		input string
+		want  int
+	}{
+		{"", 1},
+		{"a", 2},
+		{strings.Repeat("x", 127), 128},
+		{strings.Repeat("x", 128), 130},
+		{strings.Repeat("x", 1<<16+1), 3 + 1<<16 + 1},
+	}
+
+	m := encode.StringVarint{}
+
+	for _, c := range cases {
+		rst := m.Encode(c.input)
+		ta.Equal(c.want, len(rst))
+		ta.Equal(c.want, m.GetSize(c.input))
+		ta.Equal(c.want, m.GetEncodedSize(rst))
+
+		n, s := m.Decode(append(rst, 'z'))
+		ta.Equal(c.want, n)
+		ta.Equal(c.input, s)
+	}
+
+	ta.Equal("\x03abc", string(m.Encode("abc")))
+}
+
+func TestFixedBytes(t *testing.T) {
+
+	ta := require.New(t)
+
+	m := encode.FixedBytes(3)
+
+	input := []byte("abc")
+	rst := m.Encode(input)
+	ta.Equal(input, rst)
+
+	// Encode copies
+	input[0] = 'x'
+	ta.Equal([]byte("abc"), rst)
+
+	ta.Equal(3, m.GetSize(input))
+	ta.Equal(3, m.GetEncodedSize(rst))
+
+	n, v := m.Decode([]byte("abcdef"))
+	ta.Equal(3, n)
+	ta.Equal([]byte("abc"), v)
+
+	ta.Panics(func() { m.Encode([]byte("ab")) })
+	ta.Panics(func() { m.Encode([]byte("abcd")) })
+}
@@ -0,0 +1,458 @@
+package encode
+
+import (
+	"encoding/binary"
+	"math"
+	"reflect"
+
+	"github.com/openacid/errors"
+)
+
+// StructEncoder provides encoding for a struct type that may have
+// var-length fields, which TypeEncoder does not support.
+//
+// The encoding procedure is compiled once from the struct type with reflect,
+// thus there is no need to inspect the type when encoding a value.
+//
+// Supported field types are:
+//
+//	bool, int8 ~ int64, uint8 ~ uint64, float32, float64
+//	int, uint          // always encoded as 8 bytes
+//	array of a supported fixed-size type
+//	nested struct
+//	string, []byte
+//	slice of a fixed-size type
+//
+// Unexported fields are ignored.
+// Map, pointer or interface fields are not supported, because their encoded
+// form is either non-deterministic or ambiguous.
+//
+// # Layout
+//
+// Fields are encoded in declaration order, integers and floats in little
+// endian.
+// A string or slice is prefixed with the number of its elements in uvarint.
+//
+// If the struct has no var-length field, the encoded size is fixed and there
+// is no additional prefix.
+// Otherwise the encoded fields are prefixed with their total length in uvarint, thus
+// GetEncodedSize is O(1):
+//
+//	<uvarint(len(fields))><fields>
+//
+// Since 0.5.13
+type StructEncoder struct {
+	// Type is the struct type to encode.
+	Type reflect.Type
+
+	// Size is the encoded size if the struct is fixed size, otherwise it is -1.
+	Size int
+
+	codec *fieldCodec
+}
+
+// fieldCodec encodes or decodes a value of one type.
+type fieldCodec struct {
+	// size is the encoded size of a fixed size type, or -1.
+	size int
+
+	// sizeOf returns the encoded size of a value.
+	sizeOf func(v reflect.Value) int
+
+	// enc appends the encoded value to dst.
+	enc func(dst []byte, v reflect.Value) []byte
+
+	// dec decodes b into a settable value v and returns number of bytes consumed.
+	dec func(b []byte, v reflect.Value) int
+}
+
+// NewStructEncoder creates a *StructEncoder by a value.
+// The value "zero" defines what type this Encoder can deal with.
+// If zero is a pointer, the type it points to is used.
+//
+// Since 0.5.13
+func NewStructEncoder(zero interface{}) (*StructEncoder, error) {
+	return NewStructEncoderByType(reflect.Indirect(reflect.ValueOf(zero)).Type())
+}
+
+// NewStructEncoderByType creates a *StructEncoder for struct type "t".
+//
+// Since 0.5.13
+func NewStructEncoderByType(t reflect.Type) (*StructEncoder, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, errors.Wrapf(ErrUnknownEltType, "type: %v is not a struct", t)
+	}
+
+	codec, err := compileType(t)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StructEncoder{
+		Type:  t,
+		Size:  codec.size,
+		codec: codec,
+	}, nil
+}
+
+// Encode converts a m.Type value or a pointer to it to byte slice.
+// If a value of different type is passed in, it panics.
+func (m *StructEncoder) Encode(d interface{}) []byte {
+	v := reflect.Indirect(reflect.ValueOf(d))
+	if v.Type() != m.Type {
+		panic("different type from StructEncoder.Type")
+	}
+
+	if m.Size >= 0 {
+		return m.codec.enc(make([]byte, 0, m.Size), v)
+	}
+
+	l := m.codec.sizeOf(v)
+	dst := make([]byte, 0, uvarintSize(uint64(l))+l)
+	dst = appendUvarint(dst, uint64(l))
+	return m.codec.enc(dst, v)
+}
+
+// Decode converts byte slice to a m.Type value.
+// It returns number bytes consumed and a m.Type value in interface{}.
+func (m *StructEncoder) Decode(b []byte) (int, interface{}) {
+	v := reflect.New(m.Type).Elem()
+
+	if m.Size >= 0 {
+		m.codec.dec(b[:m.Size], v)
+		return m.Size, v.Interface()
+	}
+
+	payload, n := readLenPrefixed(b)
+	m.codec.dec(payload, v)
+	return n, v.Interface()
+}
+
+// GetSize returns the size in byte after encoding v.
+func (m *StructEncoder) GetSize(d interface{}) int {
+	if m.Size >= 0 {
+		return m.Size
+	}
+	l := m.codec.sizeOf(reflect.Indirect(reflect.ValueOf(d)))
+	return uvarintPrefixedSize(l)
+}
+
+// GetEncodedSize returns size of the encoded value.
+func (m *StructEncoder) GetEncodedSize(b []byte) int {
+	if m.Size >= 0 {
+		return m.Size
+	}
+	_, n := readLenPrefixed(b)
+	return n
+}
+
+// FixedSize returns m.Size, which is -1 if the struct is var-length.
+//
+// Since 0.5.13
+func (m *StructEncoder) FixedSize() int {
+	return m.Size
+}
+
+// AppendEncode appends the encoded m.Type value or a pointer to it to dst.
+//
+// Since 0.5.13
+func (m *StructEncoder) AppendEncode(dst []byte, d interface{}) []byte {
+	v := reflect.Indirect(reflect.ValueOf(d))
+	if v.Type() != m.Type {
+		panic("different type from StructEncoder.Type")
+	}
+
+	if m.Size >= 0 {
+		return m.codec.enc(dst, v)
+	}
+
+	dst = appendUvarint(dst, uint64(m.codec.sizeOf(v)))
+	return m.codec.enc(dst, v)
+}
+
+// DecodeInto decodes into "dst", which is a pointer to m.Type.
+// It returns number bytes consumed.
+//
+// Since 0.5.13
+func (m *StructEncoder) DecodeInto(b []byte, dst interface{}) int {
+	v := reflect.ValueOf(dst).Elem()
+	if v.Type() != m.Type {
+		panic("different type from StructEncoder.Type")
+	}
+
+	if m.Size >= 0 {
+		m.codec.dec(b[:m.Size], v)
+		return m.Size
+	}
+
+	payload, n := readLenPrefixed(b)
+	m.codec.dec(payload, v)
+	return n
+}
+
+// Describe returns the registered name "Struct" and the type name as params.
+// The type must be registered with RegisterType to rebuild it with New.
+//
+// Since 0.5.13
+func (m *StructEncoder) Describe() (string, []byte) {
+	return "Struct", []byte(typeName(m.Type))
+}
+
+func compileType(t reflect.Type) (*fieldCodec, error) {
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return newFixedCodec(1,
+			func(dst []byte, v reflect.Value) []byte {
+				if v.Bool() {
+					return append(dst, 1)
+				}
+				return append(dst, 0)
+			},
+			func(b []byte, v reflect.Value) { v.SetBool(b[0] != 0) },
+		), nil
+
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Int:
+		size := int(t.Size())
+		if t.Kind() == reflect.Int {
+			size = 8
+		}
+		return newFixedCodec(size,
+			func(dst []byte, v reflect.Value) []byte { return appendUint(dst, uint64(v.Int()), size) },
+			func(b []byte, v reflect.Value) { v.SetInt(signExtend(readUint(b, size), size)) },
+		), nil
+
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uint:
+		size := int(t.Size())
+		if t.Kind() == reflect.Uint {
+			size = 8
+		}
+		return newFixedCodec(size,
+			func(dst []byte, v reflect.Value) []byte { return appendUint(dst, v.Uint(), size) },
+			func(b []byte, v reflect.Value) { v.SetUint(readUint(b, size)) },
+		), nil
+
+	case reflect.Float32:
+		return newFixedCodec(4,
+			func(dst []byte, v reflect.Value) []byte {
+				return appendUint(dst, uint64(math.Float32bits(float32(v.Float()))), 4)
+			},
+			func(b []byte, v reflect.Value) {
+				v.SetFloat(float64(math.Float32frombits(uint32(readUint(b, 4)))))
+			},
+		), nil
+
+	case reflect.Float64:
+		return newFixedCodec(8,
+			func(dst []byte, v reflect.Value) []byte {
+				return appendUint(dst, math.Float64bits(v.Float()), 8)
+			},
+			func(b []byte, v reflect.Value) { v.SetFloat(math.Float64frombits(readUint(b, 8))) },
+		), nil
+
+	case reflect.String:
+		return &fieldCodec{
+			size:   -1,
+			sizeOf: func(v reflect.Value) int { return uvarintPrefixedSize(v.Len()) },
+			enc: func(dst []byte, v reflect.Value) []byte {
+				dst = appendUvarint(dst, uint64(v.Len()))
+				return append(dst, v.String()...)
+			},
+			dec: func(b []byte, v reflect.Value) int {
+				s, n := readLenPrefixed(b)
+				v.SetString(string(s))
+				return n
+			},
+		}, nil
+
+	case reflect.Array:
+		return compileArray(t)
+
+	case reflect.Slice:
+		return compileSlice(t)
+
+	case reflect.Struct:
+		return compileStruct(t)
+	}
+
+	return nil, errors.Wrapf(ErrUnknownEltType, "type: %v", t)
+}
+
+func compileArray(t reflect.Type) (*fieldCodec, error) {
+	elt, err := compileType(t.Elem())
+	if err != nil {
+		return nil, err
+	}
+	if elt.size < 0 {
+		return nil, errors.Wrapf(ErrNotFixedSize, "array element type: %v", t.Elem())
+	}
+
+	n := t.Len()
+	return newFixedCodec(n*elt.size,
+		func(dst []byte, v reflect.Value) []byte {
+			for i := 0; i < n; i++ {
+				dst = elt.enc(dst, v.Index(i))
+			}
+			return dst
+		},
+		func(b []byte, v reflect.Value) {
+			for i := 0; i < n; i++ {
+				elt.dec(b[i*elt.size:], v.Index(i))
+			}
+		},
+	), nil
+}
+
+func compileSlice(t reflect.Type) (*fieldCodec, error) {
+
+	if t.Elem().Kind() == reflect.Uint8 {
+		// []byte: copy in a batch.
+		return &fieldCodec{
+			size:   -1,
+			sizeOf: func(v reflect.Value) int { return uvarintPrefixedSize(v.Len()) },
+			enc: func(dst []byte, v reflect.Value) []byte {
+				dst = appendUvarint(dst, uint64(v.Len()))
+				return append(dst, v.Bytes()...)
+			},
+			dec: func(b []byte, v reflect.Value) int {
+				s, n := readLenPrefixed(b)
+				bs := reflect.MakeSlice(v.Type(), len(s), len(s))
+				reflect.Copy(bs, reflect.ValueOf(s))
+				v.Set(bs)
+				return n
+			},
+		}, nil
+	}
+
+	elt, err := compileType(t.Elem())
+	if err != nil {
+		return nil, err
+	}
+	if elt.size < 0 {
+		return nil, errors.Wrapf(ErrNotFixedSize, "slice element type: %v", t.Elem())
+	}
+
+	return &fieldCodec{
+		size: -1,
+		sizeOf: func(v reflect.Value) int {
+			return uvarintSize(uint64(v.Len())) + v.Len()*elt.size
+		},
+		enc: func(dst []byte, v reflect.Value) []byte {
+			n := v.Len()
+			dst = appendUvarint(dst, uint64(n))
+			for i := 0; i < n; i++ {
+				dst = elt.enc(dst, v.Index(i))
+			}
+			return dst
+		},
+		dec: func(b []byte, v reflect.Value) int {
+			cnt, p := binary.Uvarint(b)
+			n := int(cnt)
+			s := reflect.MakeSlice(v.Type(), n, n)
+			for i := 0; i < n; i++ {
+				p += elt.dec(b[p:], s.Index(i))
+			}
+			v.Set(s)
+			return p
+		},
+	}, nil
+}
+
+func compileStruct(t reflect.Type) (*fieldCodec, error) {
+
+	var fieldIndexes []int
+	var codecs []*fieldCodec
+	size := 0
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			// unexported
+			continue
+		}
+
+		c, err := compileType(f.Type)
+		if err != nil {
+			return nil, errors.Wrapf(err, "field: %s.%s", t, f.Name)
+		}
+
+		fieldIndexes = append(fieldIndexes, i)
+		codecs = append(codecs, c)
+
+		if size >= 0 && c.size >= 0 {
+			size += c.size
+		} else {
+			size = -1
+		}
+	}
+
+	rst := &fieldCodec{
+		size: size,
+		sizeOf: func(v reflect.Value) int {
+			l := 0
+			for i, c := range codecs {
+				if c.size >= 0 {
+					l += c.size
+				} else {
+					l += c.sizeOf(v.Field(fieldIndexes[i]))
+				}
+			}
+			return l
+		},
+		enc: func(dst []byte, v reflect.Value) []byte {
+			for i, c := range codecs {
+				dst = c.enc(dst, v.Field(fieldIndexes[i]))
+			}
+			return dst
+		},
+		dec: func(b []byte, v reflect.Value) int {
+			p := 0
+			for i, c := range codecs {
+				p += c.dec(b[p:], v.Field(fieldIndexes[i]))
+			}
+			return p
+		},
+	}
+
+	return rst, nil
+}
+
+// newFixedCodec creates a codec for a fixed size type.
+func newFixedCodec(size int,
+	enc func(dst []byte, v reflect.Value) []byte,
+	dec func(b []byte, v reflect.Value)) *fieldCodec {
+
+	return &fieldCodec{
+		size:   size,
+		sizeOf: func(v reflect.Value) int { return size },
+		enc:    enc,
+		dec: func(b []byte, v reflect.Value) int {
+			dec(b, v)
+			return size
+		},
+	}
+}
+
+// appendUint appends the lower "size" bytes of "u" in little endian.
+func appendUint(dst []byte, u uint64, size int) []byte {
+	for i := 0; i < size; i++ {
+		dst = append(dst, byte(u>>(uint(i)*8)))
+	}
+	return dst
+}
+
+// readUint reads a "size" bytes little endian unsigned integer.
+func readUint(b []byte, size int) uint64 {
+	_ = b[size-1]
+	u := uint64(0)
+	for i := 0; i < size; i++ {
+		u |= uint64(b[i]) << (uint(i) * 8)
+	}
+	return u
+}
+
+// signExtend converts a "size" bytes unsigned integer to int64.
+func signExtend(u uint64, size int) int64 {
+	shift := uint(64 - size*8)
+	return int64(u<<shift) >> shift
+}
@@ -0,0 +1,135 @@
+package encode_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/openacid/slim/encode"
+	"github.com/stretchr/testify/require"
+)
+
+type point struct {
+	X int16
+	Y float32
+}
+
+type fixedRecord struct {
+	A bool
+	B int8
+	C uint32
+	D int
+	P point
+	Q [2]uint16
+	e int64
+}
+
+type varRecord struct {
+	ID     uint64
+	Name   string
+	Raw    []byte
+	Points []point
+	Score  float64
+	Sub    struct {
+		Tag string
+		N   int32
+	}
+}
+
+func TestStructEncoder_fixed(t *testing.T) {
+
+	ta := require.New(t)
+
+	m, err := encode.NewStructEncoder(fixedRecord{})
+	ta.NoError(err)
+	ta.Equal(1+1+4+8+6+4, m.Size)
+
+	input := fixedRecord{A: true, B: -3, C: 0x01020304, D: -1, P: point{-2, 1.5}, Q: [2]uint16{7, 8}, e: 100}
+
+	rst := m.Encode(input)
+	ta.Equal(m.Size, len(rst))
+	ta.Equal(m.Size, m.GetSize(input))
+	ta.Equal(m.Size, m.GetEncodedSize(nil))
+	ta.Equal([]byte{1, 0xfd, 4, 3, 2, 1}, rst[:6])
+
+	// pointer is accepted
+	ta.Equal(rst, m.Encode(&input))
+
+	n, v := m.Decode(append(rst, 9))
+	ta.Equal(m.Size, n)
+
+	// unexported field is ignored
+	input.e = 0
+	ta.Equal(input, v)
+}
+
+func TestStructEncoder_var(t *testing.T) {
+
+	ta := require.New(t)
+
+	m, err := encode.NewStructEncoderByType(reflect.TypeOf(varRecord{}))
+	ta.NoError(err)
+	ta.Equal(-1, m.Size)
+
+	cases := []varRecord{
+		{},
+		{ID: 1, Name: "foo", Raw: []byte{1, 2}, Points: []point{{1, 2}, {-3, 4.5}}, Score: -0.5},
+		{Name: string(make([]byte, 300)), Points: make([]point, 100)},
+	}
+	cases[1].Sub.Tag = "bar"
+	cases[1].Sub.N = -7
+
+	for _, input := range cases {
+		rst := m.Encode(input)
+		ta.Equal(len(rst), m.GetSize(input))
+		ta.Equal(len(rst), m.GetEncodedSize(append(rst, 1, 2, 3)))
+
+		n, v := m.Decode(append(rst, 1, 2, 3))
+		ta.Equal(len(rst), n)
+
+		got := v.(varRecord)
+
+		// nil and empty slices are both decoded as empty
+		if input.Raw == nil {
+			input.Raw = []byte{}
+		}
+		if input.Points == nil {
+			input.Points = []point{}
+		}
+		ta.Equal(input, got)
+	}
+
+	// deterministic layout
+	ta.Equal(
+		[]byte{
+			25,                     // total length
+			1, 0, 0, 0, 0, 0, 0, 0, // ID
+			1, 'a', // Name
+			0,                      // Raw
+			0,                      // Points
+			0, 0, 0, 0, 0, 0, 0, 0, // Score
+			0,          // Sub.Tag
+			0, 0, 0, 0, // Sub.N
+		},
+		m.Encode(varRecord{ID: 1, Name: "a"}))
+}
+
+func TestStructEncoder_unsupported(t *testing.T) {
+
+	ta := require.New(t)
+
+	_, err := encode.NewStructEncoder(1)
+	ta.Error(err)
+
+	cases := []interface{}{
+		struct{ M map[string]int }{},
+		struct{ P *int }{},
+		struct{ I interface{} }{},
+		struct{ S []string }{},
+		struct{ A [2]string }{},
+	}
+
+	for _, c := range cases {
+		_, err := encode.NewStructEncoder(c)
+		ta.Error(err, "%T", c)
+	}
+}
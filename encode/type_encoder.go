@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/binary"
 	"reflect"
+	"sync"
 
 	"github.com/openacid/errors"
 )
@@ -106,3 +107,79 @@ func (m *TypeEncoder) GetSize(d interface{}) int {
 func (m *TypeEncoder) GetEncodedSize(b []byte) int {
 	return m.Size
 }
+
+// FixedSize returns m.Size.
+//
+// Since 0.5.13
+func (m *TypeEncoder) FixedSize() int {
+	return m.Size
+}
+
+// AppendEncode appends the encoded m.Type value to dst.
+//
+// Since 0.5.13
+func (m *TypeEncoder) AppendEncode(dst []byte, d interface{}) []byte {
+	if reflect.Indirect(reflect.ValueOf(d)).Type() != m.Type {
+		panic("different type from TypeEncoder.Type")
+	}
+
+	b := bytes.NewBuffer(dst)
+	err := binary.Write(b, m.Endian, d)
+	if err != nil {
+		panic(err)
+	}
+	return b.Bytes()
+}
+
+// DecodeInto decodes into "dst", which is a pointer to m.Type.
+// It returns number bytes consumed.
+//
+// With binary.LittleEndian, it uses a compiled decoder thus involves 0 alloc.
+//
+// Since 0.5.13
+func (m *TypeEncoder) DecodeInto(b []byte, dst interface{}) int {
+	if m.Endian == binary.LittleEndian {
+		c := typeCodecOf(m.Type)
+		if c != nil && c.size == m.Size {
+			c.dec(b[:m.Size], reflect.ValueOf(dst).Elem())
+			return m.Size
+		}
+	}
+
+	err := binary.Read(bytes.NewReader(b[:m.Size]), m.Endian, dst)
+	if err != nil {
+		panic(err)
+	}
+	return m.Size
+}
+
+// Describe returns the registered name "Type" and params of the byte order
+// and the type name.
+// The type must be registered with RegisterType to rebuild it with New.
+//
+// Since 0.5.13
+func (m *TypeEncoder) Describe() (string, []byte) {
+	var order byte = 0
+	if m.Endian == binary.BigEndian {
+		order = 1
+	}
+	return "Type", append([]byte{order}, typeName(m.Type)...)
+}
+
+// typeCodecs caches compiled little endian codecs by type.
+// A nil *fieldCodec is stored for a type that can not be compiled.
+var typeCodecs sync.Map
+
+// typeCodecOf returns a compiled codec for "t", or nil if "t" can not be
+// compiled.
+func typeCodecOf(t reflect.Type) *fieldCodec {
+	c, ok := typeCodecs.Load(t)
+	if !ok {
+		fc, err := compileType(t)
+		if err != nil {
+			fc = nil
+		}
+		c, _ = typeCodecs.LoadOrStore(t, fc)
+	}
+	return c.(*fieldCodec)
+}
@@ -0,0 +1,135 @@
+package encode
+
+import (
+	"encoding/binary"
+)
+
+// UVarint converts uint64 to a var-length slice of bytes and back.
+// It uses the same format as binary.PutUvarint: 7 bits per byte, the most
+// significant bit of a byte is set if more bytes follow.
+// A small value takes less space: a value less than 128 takes 1 byte.
+//
+// Since 0.5.13
+type UVarint struct{}
+
+// Encode converts uint64 to a var-length slice of 1 to 10 bytes.
+func (c UVarint) Encode(d interface{}) []byte {
+	b := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(b, d.(uint64))
+	return b[:n]
+}
+
+// Decode converts a var-length slice of bytes to uint64.
+// It returns number bytes consumed and an uint64.
+func (c UVarint) Decode(b []byte) (int, interface{}) {
+	v, n := binary.Uvarint(b)
+	return n, v
+}
+
+// GetSize returns the size in byte after encoding v.
+func (c UVarint) GetSize(d interface{}) int {
+	return uvarintSize(d.(uint64))
+}
+
+// GetEncodedSize returns the size of the encoded value at the start of "b",
+// by finding the first byte without the continuation bit.
+func (c UVarint) GetEncodedSize(b []byte) int {
+	for i, x := range b {
+		if x < 0x80 {
+			return i + 1
+		}
+	}
+	panic("incomplete varint")
+}
+
+// AppendEncode appends uvarint of uint64 to dst.
+//
+// Since 0.5.13
+func (c UVarint) AppendEncode(dst []byte, d interface{}) []byte {
+	return appendUvarint(dst, d.(uint64))
+}
+
+// DecodeInto decodes into a *uint64.
+// It returns number bytes consumed.
+//
+// Since 0.5.13
+func (c UVarint) DecodeInto(b []byte, dst interface{}) int {
+	v, n := binary.Uvarint(b)
+	*dst.(*uint64) = v
+	return n
+}
+
+// Describe returns the registered name "UVarint" and no params.
+//
+// Since 0.5.13
+func (c UVarint) Describe() (string, []byte) {
+	return "UVarint", nil
+}
+
+// Varint converts int64 to a var-length slice of bytes and back.
+// It uses zigzag encoding the same as binary.PutVarint, thus a value with
+// small absolute value, positive or negative, takes less space.
+//
+// Since 0.5.13
+type Varint struct{}
+
+// Encode converts int64 to a var-length slice of 1 to 10 bytes.
+func (c Varint) Encode(d interface{}) []byte {
+	b := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutVarint(b, d.(int64))
+	return b[:n]
+}
+
+// Decode converts a var-length slice of bytes to int64.
+// It returns number bytes consumed and an int64.
+func (c Varint) Decode(b []byte) (int, interface{}) {
+	v, n := binary.Varint(b)
+	return n, v
+}
+
+// GetSize returns the size in byte after encoding v.
+func (c Varint) GetSize(d interface{}) int {
+	v := d.(int64)
+	return uvarintSize(uint64(v<<1) ^ uint64(v>>63))
+}
+
+// GetEncodedSize returns the size of the encoded value at the start of "b",
+// by finding the first byte without the continuation bit.
+func (c Varint) GetEncodedSize(b []byte) int {
+	return UVarint{}.GetEncodedSize(b)
+}
+
+// AppendEncode appends zigzag varint of int64 to dst.
+//
+// Since 0.5.13
+func (c Varint) AppendEncode(dst []byte, d interface{}) []byte {
+	v := d.(int64)
+	return appendUvarint(dst, uint64(v<<1)^uint64(v>>63))
+}
+
+// DecodeInto decodes into a *int64.
+// It returns number bytes consumed.
+//
+// Since 0.5.13
+func (c Varint) DecodeInto(b []byte, dst interface{}) int {
+	v, n := binary.Varint(b)
+	*dst.(*int64) = v
+	return n
+}
+
+// Describe returns the registered name "Varint" and no params.
+//
+// Since 0.5.13
+func (c Varint) Describe() (string, []byte) {
+	return "Varint", nil
+}
+
+// uvarintSize returns the number of bytes binary.PutUvarint uses for v.
+func uvarintSize(v uint64) int {
+	n := 1
+	for v >= 0x80 {
+		v >>= 7
+		n++
+	}
+	return n
+}
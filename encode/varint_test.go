@@ -0,0 +1,80 @@
+package encode_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/openacid/slim/encode"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUVarint(t *testing.T) {
+
+	ta := require.New(t)
+
+	cases := []struct {
+		input uint64
+		want  string
+	}{
+		{0, string([]byte{0})},
+		{1, string([]byte{1})},
+		{127, string([]byte{0x7f})},
+		{128, string([]byte{0x80, 0x01})},
+		{300, string([]byte{0xac, 0x02})},
+		{math.MaxUint64, string([]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01})},
+	}
+
+	m := encode.UVarint{}
+
+	for _, c := range cases {
+		rst := m.Encode(c.input)
+		ta.Equal(c.want, string(rst))
+
+		size := len(c.want)
+		ta.Equal(size, m.GetSize(c.input))
+		ta.Equal(size, m.GetEncodedSize(rst))
+
+		// trailing bytes does not affect size
+		ta.Equal(size, m.GetEncodedSize(append(rst, 0x81, 0)))
+
+		n, v := m.Decode(rst)
+		ta.Equal(c.input, v)
+		ta.Equal(size, n)
+	}
+
+	ta.Panics(func() { m.GetEncodedSize([]byte{0x80, 0x81}) })
+}
+
+func TestVarint(t *testing.T) {
+
+	ta := require.New(t)
+
+	cases := []struct {
+		input int64
+		want  string
+	}{
+		{0, string([]byte{0})},
+		{-1, string([]byte{1})},
+		{1, string([]byte{2})},
+		{-64, string([]byte{0x7f})},
+		{64, string([]byte{0x80, 0x01})},
+		{math.MaxInt64, string([]byte{0xfe, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01})},
+		{math.MinInt64, string([]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01})},
+	}
+
+	m := encode.Varint{}
+
+	for _, c := range cases {
+		rst := m.Encode(c.input)
+		ta.Equal(c.want, string(rst))
+
+		size := len(c.want)
+		ta.Equal(size, m.GetSize(c.input))
+		ta.Equal(size, m.GetEncodedSize(rst))
+		ta.Equal(size, m.GetEncodedSize(append(rst, 0x81, 0)))
+
+		n, v := m.Decode(rst)
+		ta.Equal(c.input, v)
+		ta.Equal(size, n)
+	}
+}
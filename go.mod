@@ -14,6 +14,7 @@ require (
 	github.com/openacid/testkeys v0.1.7
 	github.com/openacid/testutil v0.1.3
 	github.com/stretchr/testify v1.11.1
+	google.golang.org/protobuf v1.33.0
 )
 
 require (
@@ -23,7 +24,6 @@ require (
 	github.com/mattn/go-runewidth v0.0.4 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/rogpeppe/go-internal v1.9.0 // indirect
-	google.golang.org/protobuf v1.33.0 // indirect
 	gopkg.in/check.v1 v1.0.0-20190902080502-41f04d3bba15 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,249 @@
+// Package pbstream reads and writes a protobuf message record by record, so
+// that a large message does not need to be in memory as a single byte slice.
+//
+// A record is a tag followed by its value. A message is the concatenation of
+// its records thus a message can be written and read record by record.
+package pbstream
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+
+	proto "github.com/golang/protobuf/proto"
+	"github.com/openacid/errors"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// ErrInvalidRecord indicates a record can not be parsed.
+var ErrInvalidRecord = errors.New("invalid protobuf record")
+
+// Reader reads records one by one from an io.Reader.
+// It never reads more bytes than a record needs, thus the underlying reader
+// can be used to read other data after the last record.
+//
+// If the io.Reader is a *bytes.Buffer, records are sub-slices of its unread
+// data and are not copied.
+type Reader struct {
+	r io.Reader
+
+	// src is the same as r if r is a *bytes.Buffer.
+	src *bytes.Buffer
+
+	// remaining is the number of bytes of records left or -1 if records span
+	// to EOF.
+	remaining int64
+
+	// N is the number of bytes read.
+	N int64
+
+	b [1]byte
+}
+
+// NewReader creates a Reader that reads records of total size `size` from r.
+// If size is -1, it reads records until EOF.
+func NewReader(r io.Reader, size int64) *Reader {
+	rr := &Reader{r: r, remaining: size}
+	if b, ok := r.(*bytes.Buffer); ok {
+		rr.src = b
+	}
+	return rr
+}
+
+// Next returns the field number and the entire record, including the tag.
+// It returns io.EOF if there is no more record.
+// A record that is truncated results in io.ErrUnexpectedEOF, a record that
+// exceeds the size of records results in ErrInvalidRecord.
+func (r *Reader) Next() (protowire.Number, []byte, error) {
+
+	if r.remaining == 0 {
+		return 0, nil, io.EOF
+	}
+
+	rec := r.newRecord()
+
+	tag, rec, err := r.readVarint(rec)
+	if err != nil {
+		if errors.Cause(err) == io.ErrUnexpectedEOF && len(rec) == 0 && r.remaining < 0 {
+			return 0, nil, io.EOF
+		}
+		return 0, nil, err
+	}
+
+	num, typ := protowire.DecodeTag(tag)
+	if !num.IsValid() {
+		return 0, nil, errors.Wrapf(ErrInvalidRecord, "field number: %d", num)
+	}
+
+	switch typ {
+	case protowire.VarintType:
+		_, rec, err = r.readVarint(rec)
+	case protowire.Fixed32Type:
+		rec, err = r.readN(rec, 4)
+	case protowire.Fixed64Type:
+		rec, err = r.readN(rec, 8)
+	case protowire.BytesType:
+		var l uint64
+		l, rec, err = r.readVarint(rec)
+		if err == nil {
+			rec, err = r.readN(rec, l)
+		}
+	default:
+		err = errors.Wrapf(ErrInvalidRecord, "wire type: %d", typ)
+	}
+
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return num, rec, nil
+}
+
+// Read reads records of total size `size` from r and merges them into msg.
+// If size is -1, it reads records until EOF.
+func Read(r io.Reader, size int64, msg proto.Message) (int64, error) {
+
+	rr := NewReader(r, size)
+
+	for {
+		_, rec, err := rr.Next()
+		if err == io.EOF {
+			return rr.N, nil
+		}
+		if err != nil {
+			return rr.N, err
+		}
+
+		err = proto.UnmarshalMerge(rec, msg)
+		if err != nil {
+			return rr.N, errors.WithStack(err)
+		}
+	}
+}
+
+// ReadN reads exactly n bytes from r.
+// Unlike io.ReadFull with a buffer of size n, it allocates memory as data
+// arrives, thus a malformed size does not allocate a huge buffer.
+//
+// If r is a *bytes.Buffer, the returned bytes are a sub-slice of its unread
+// data.
+func ReadN(r io.Reader, n int64) ([]byte, error) {
+	rr := NewReader(r, -1)
+	return rr.readN(rr.newRecord(), uint64(n))
+}
+
+// newRecord returns an empty slice to append a record to.
+// If reading from a *bytes.Buffer, it is the unread data of the buffer
+// truncated to 0, so that readN extends it without copying.
+func (r *Reader) newRecord() []byte {
+	if r.src != nil {
+		return r.src.Bytes()[:0]
+	}
+	return make([]byte, 0, 16)
+}
+
+// readVarint reads a varint byte by byte and appends it to rec.
+func (r *Reader) readVarint(rec []byte) (uint64, []byte, error) {
+
+	var v uint64
+
+	for i := 0; ; i++ {
+		if i == binary.MaxVarintLen64 {
+			return 0, rec, errors.Wrapf(ErrInvalidRecord, "varint overflow")
+		}
+
+		rec2, err := r.readN(rec, 1)
+		if err != nil {
+			return 0, rec, err
+		}
+		rec = rec2
+
+		c := rec[len(rec)-1]
+		v |= uint64(c&0x7f) << (7 * uint(i))
+		if c < 0x80 {
+			return v, rec, nil
+		}
+	}
+}
+
+// readN reads n bytes and appends them to rec.
+// It grows rec with the data actually read, thus a malformed huge length does
+// not allocate a huge buffer.
+//
+// If reading from a *bytes.Buffer, rec must be a slice returned by newRecord
+// and it is extended in place.
+func (r *Reader) readN(rec []byte, n uint64) ([]byte, error) {
+
+	if r.remaining >= 0 && n > uint64(r.remaining) {
+		return rec, errors.Wrapf(ErrInvalidRecord, "record size: %d, remaining: %d", n, r.remaining)
+	}
+
+	if r.src != nil {
+		l := len(rec)
+		if n > uint64(r.src.Len()) {
+			nn := r.src.Len()
+			r.src.Next(nn)
+			r.consumed(int64(nn))
+			return rec[:l+nn], errors.WithStack(io.ErrUnexpectedEOF)
+		}
+		r.src.Next(int(n))
+		r.consumed(int64(n))
+		return rec[:l+int(n)], nil
+	}
+
+	if n == 1 {
+		_, err := io.ReadFull(r.r, r.b[:])
+		if err != nil {
+			return rec, unexpectedEOF(err)
+		}
+		r.consumed(1)
+		return append(rec, r.b[0]), nil
+	}
+
+	const chunk = 1 << 20
+
+	for n > 0 {
+
+		// Read at most as many bytes as already read, thus rec grows
+		// exponentially and every byte is copied O(1) times.
+		c := uint64(len(rec))
+		if c < chunk {
+			c = chunk
+		}
+		if c > n {
+			c = n
+		}
+
+		l := len(rec)
+		if uint64(cap(rec)-l) < c {
+			grown := make([]byte, l, uint64(l)+c)
+			copy(grown, rec)
+			rec = grown
+		}
+		rec = rec[:l+int(c)]
+
+		nn, err := io.ReadFull(r.r, rec[l:])
+		r.consumed(int64(nn))
+		if err != nil {
+			return rec[:l+nn], unexpectedEOF(err)
+		}
+
+		n -= c
+	}
+
+	return rec, nil
+}
+
+func (r *Reader) consumed(n int64) {
+	r.N += n
+	if r.remaining >= 0 {
+		r.remaining -= n
+	}
+}
+
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return errors.WithStack(err)
+}
@@ -0,0 +1,222 @@
+package pbstream
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	proto "github.com/golang/protobuf/proto"
+	"github.com/openacid/errors"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestWriteRead(t *testing.T) {
+
+	ta := require.New(t)
+
+	d := &durationpb.Duration{Seconds: 300, Nanos: 5}
+	want, err := proto.Marshal(d)
+	ta.NoError(err)
+
+	records := map[protowire.Number]*bytes.Buffer{}
+	w := bytes.NewBuffer(nil)
+	n, err := Write(w, d, func(num protowire.Number) io.Writer {
+		records[num] = bytes.NewBuffer(nil)
+		return records[num]
+	})
+	ta.NoError(err)
+	ta.Equal(int64(len(want)), n)
+	ta.Equal(want, w.Bytes())
+	ta.Equal(2, len(records))
+	ta.Equal(want, append(records[1].Bytes(), records[2].Bytes()...))
+
+	// followed by other data
+	w.WriteString("foo")
+
+	r := bytes.NewReader(w.Bytes())
+	got := &durationpb.Duration{}
+	n, err = Read(r, int64(len(want)), got)
+	ta.NoError(err)
+	ta.Equal(int64(len(want)), n)
+	ta.True(proto.Equal(d, got))
+	ta.Equal(3, r.Len())
+
+	// until EOF
+
+	got = &durationpb.Duration{}
+	n, err = Read(bytes.NewReader(want), -1, got)
+	ta.NoError(err)
+	ta.Equal(int64(len(want)), n)
+	ta.True(proto.Equal(d, got))
+}
+
+func TestWrite_sameAsMarshal(t *testing.T) {
+
+	ta := require.New(t)
+
+	st, err := structpb.NewStruct(map[string]interface{}{
+		"a": 1.5,
+		"b": []interface{}{"x", true, nil, -2.0},
+		"c": map[string]interface{}{"d": "e"},
+	})
+	ta.NoError(err)
+
+	msgs := []proto.Message{
+		&durationpb.Duration{},
+		&durationpb.Duration{Seconds: -1, Nanos: -2},
+		wrapperspb.Bytes(bytes.Repeat([]byte("abc"), bufSize)),
+		wrapperspb.String("foo"),
+		wrapperspb.Float(-1.5),
+		wrapperspb.UInt64(1 << 63),
+		// messages, strings, enums, and packed and non-packed lists
+		protodesc.ToFileDescriptorProto(descriptorpb.File_google_protobuf_descriptor_proto),
+	}
+
+	for i, m := range msgs {
+		want, err := proto.Marshal(m)
+		ta.NoError(err)
+
+		w := bytes.NewBuffer(nil)
+		n, err := Write(w, m, nil)
+		ta.NoError(err, "%d-th", i)
+		ta.Equal(int64(len(want)), n, "%d-th", i)
+		ta.Equal(string(want), w.String(), "%d-th", i)
+	}
+
+	// a map is marshaled in random order
+
+	w := bytes.NewBuffer(nil)
+	_, err = Write(w, st, nil)
+	ta.NoError(err)
+
+	got := &structpb.Struct{}
+	ta.NoError(proto.Unmarshal(w.Bytes(), got))
+	ta.True(proto.Equal(st, got))
+
+	_, err = Write(io.Discard, wrapperspb.String("\xff"), nil)
+	ta.Error(err)
+}
+
+func TestWrite_error(t *testing.T) {
+
+	ta := require.New(t)
+
+	myErr := errors.New("foo")
+	_, err := Write(io.Discard, &durationpb.Duration{Seconds: 1}, func(num protowire.Number) io.Writer {
+		return errWriter{myErr}
+	})
+	ta.Equal(myErr, errors.Cause(err))
+
+	_, err = Write(errWriter{myErr}, &durationpb.Duration{Seconds: 1}, nil)
+	ta.Equal(myErr, errors.Cause(err))
+}
+
+type errWriter struct {
+	err error
+}
+
+func (w errWriter) Write(p []byte) (int, error) {
+	return 0, w.err
+}
+
+func TestReader_Next(t *testing.T) {
+
+	ta := require.New(t)
+
+	v := &wrapperspb.BytesValue{Value: []byte("hello")}
+	b, err := proto.Marshal(v)
+	ta.NoError(err)
+
+	rr := NewReader(bytes.NewReader(b), -1)
+	num, rec, err := rr.Next()
+	ta.NoError(err)
+	ta.Equal(protowire.Number(1), num)
+	ta.Equal(b, rec)
+	ta.Equal(int64(len(b)), rr.N)
+
+	_, _, err = rr.Next()
+	ta.Equal(io.EOF, err)
+
+	cases := []struct {
+		name  string
+		input []byte
+		size  int64
+		want  error
+	}{
+		{"truncated-tag", []byte{0x80}, -1, io.ErrUnexpectedEOF},
+		{"truncated-value", b[:len(b)-1], -1, io.ErrUnexpectedEOF},
+		{"truncated-sized", b[:len(b)-1], int64(len(b)), io.ErrUnexpectedEOF},
+		{"exceed-size", b, int64(len(b) - 1), ErrInvalidRecord},
+		{"exceed-size-varint", []byte{0x08, 0x80, 0x01}, 2, ErrInvalidRecord},
+		{"huge-length", []byte{0x0a, 0xff, 0xff, 0xff, 0xff, 0x0f}, 6, ErrInvalidRecord},
+		{"field-0", []byte{0x00, 0x00}, -1, ErrInvalidRecord},
+		{"group", []byte{0x0b}, -1, ErrInvalidRecord},
+		{"varint-overflow", bytes.Repeat([]byte{0xff}, 11), -1, ErrInvalidRecord},
+	}
+
+	for _, c := range cases {
+		rr := NewReader(bytes.NewReader(c.input), c.size)
+		_, _, err := rr.Next()
+		ta.Equal(c.want, errors.Cause(err), c.name)
+
+		rr = NewReader(bytes.NewBuffer(c.input), c.size)
+		_, _, err = rr.Next()
+		ta.Equal(c.want, errors.Cause(err), "in place: %s", c.name)
+	}
+}
+
+func TestReader_Next_inPlace(t *testing.T) {
+
+	ta := require.New(t)
+
+	d := &durationpb.Duration{Seconds: 300, Nanos: 5}
+	b, err := proto.Marshal(d)
+	ta.NoError(err)
+
+	data := append(b, "foo"...)
+	buf := bytes.NewBuffer(data)
+	rr := NewReader(buf, int64(len(b)))
+
+	// records are not copied
+	_, rec, err := rr.Next()
+	ta.NoError(err)
+	ta.True(&data[0] == &rec[0])
+
+	_, rec2, err := rr.Next()
+	ta.NoError(err)
+	ta.Equal(b, append(rec, rec2...))
+	ta.Equal("foo", buf.String())
+
+	_, _, err = rr.Next()
+	ta.Equal(io.EOF, err)
+}
+
+func TestReadN(t *testing.T) {
+
+	ta := require.New(t)
+
+	b := bytes.Repeat([]byte("abc"), 1<<20)
+
+	got, err := ReadN(bytes.NewReader(b), int64(len(b)))
+	ta.NoError(err)
+	ta.Equal(b, got)
+
+	_, err = ReadN(bytes.NewReader(b), 1<<40)
+	ta.Equal(io.ErrUnexpectedEOF, errors.Cause(err))
+
+	// in place
+
+	got, err = ReadN(bytes.NewBuffer(b), int64(len(b)))
+	ta.NoError(err)
+	ta.True(&b[0] == &got[0])
+	ta.Equal(len(b), len(got))
+
+	_, err = ReadN(bytes.NewBuffer(b), 1<<40)
+	ta.Equal(io.ErrUnexpectedEOF, errors.Cause(err))
+}
@@ -0,0 +1,288 @@
+package pbstream
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"sort"
+	"unicode/utf8"
+
+	proto "github.com/golang/protobuf/proto"
+	"github.com/openacid/errors"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// bufSize is the size of the buffer for small records.
+// A bytes or string value not smaller than it is written without copying.
+const bufSize = 32 << 10
+
+// Write writes msg to w record by record.
+// The output is the same as proto.Marshal, but the entire marshaled msg is
+// never built in memory: a bytes or string field is written to w directly
+// after its tag and length, and other records are written through a small
+// buffer.
+//
+// tee, if not nil, is called with the field number of every top-level record
+// and every byte of the record is also written to the io.Writer it returns,
+// e.g., to calculate checksums in the same pass.
+func Write(w io.Writer, msg proto.Message, tee func(num protowire.Number) io.Writer) (int64, error) {
+
+	e := &encoder{w: w, buf: make([]byte, 0, bufSize)}
+
+	m := proto.MessageReflect(msg)
+	for _, fd := range populatedFields(m) {
+		if tee != nil {
+			e.flush()
+			e.tee = tee(fd.Number())
+		}
+		e.field(m, fd)
+	}
+
+	if tee != nil {
+		e.flush()
+		e.tee = nil
+	}
+	e.unknown(m)
+	e.flush()
+
+	return e.n, e.err
+}
+
+// encoder writes records to w and tee. Once an error occurs, all following
+// writes are ignored.
+type encoder struct {
+	w   io.Writer
+	tee io.Writer
+	buf []byte
+	n   int64
+	err error
+}
+
+func (e *encoder) flush() {
+	if len(e.buf) > 0 {
+		e.write(e.buf)
+		e.buf = e.buf[:0]
+	}
+}
+
+func (e *encoder) write(b []byte) {
+
+	if e.err != nil {
+		return
+	}
+
+	if e.tee != nil {
+		_, err := e.tee.Write(b)
+		if err != nil {
+			e.err = err
+			return
+		}
+	}
+
+	n, err := e.w.Write(b)
+	e.n += int64(n)
+	if err != nil {
+		e.err = errors.WithStack(err)
+	}
+}
+
+// raw writes b, a large b is written without copying.
+func (e *encoder) raw(b []byte) {
+
+	if len(b) > cap(e.buf)-len(e.buf) {
+		e.flush()
+	}
+
+	if len(b) >= bufSize {
+		e.write(b)
+		return
+	}
+	e.buf = append(e.buf, b...)
+}
+
+func (e *encoder) tag(num protowire.Number, typ protowire.Type) {
+	e.varint(protowire.EncodeTag(num, typ))
+}
+
+func (e *encoder) varint(v uint64) {
+	if cap(e.buf)-len(e.buf) < protowire.SizeVarint(v) {
+		e.flush()
+	}
+	e.buf = protowire.AppendVarint(e.buf, v)
+}
+
+// message writes all records of m, in field number order, the same as
+// proto.Marshal does.
+func (e *encoder) message(m protoreflect.Message) {
+	for _, fd := range populatedFields(m) {
+		e.field(m, fd)
+	}
+	e.unknown(m)
+}
+
+func (e *encoder) unknown(m protoreflect.Message) {
+	e.raw(m.GetUnknown())
+}
+
+func (e *encoder) field(m protoreflect.Message, fd protoreflect.FieldDescriptor) {
+
+	if e.err != nil {
+		return
+	}
+
+	v := m.Get(fd)
+	num := fd.Number()
+
+	if fd.IsMap() {
+		// A map entry is small: marshal them with proto.Marshal.
+		part := m.New()
+		part.Set(fd, v)
+		b, err := proto.Marshal(proto.MessageV1(part.Interface()))
+		if err != nil {
+			e.err = errors.WithStack(err)
+			return
+		}
+		e.raw(b)
+		return
+	}
+
+	if !fd.IsList() {
+		e.value(fd, num, v)
+		return
+	}
+
+	l := v.List()
+
+	if !fd.IsPacked() {
+		for i := 0; i < l.Len(); i++ {
+			e.value(fd, num, l.Get(i))
+		}
+		return
+	}
+
+	size := 0
+	for i := 0; i < l.Len(); i++ {
+		size += scalarSize(fd.Kind(), l.Get(i))
+	}
+
+	e.tag(num, protowire.BytesType)
+	e.varint(uint64(size))
+	for i := 0; i < l.Len(); i++ {
+		e.scalar(fd.Kind(), l.Get(i))
+	}
+}
+
+// value writes a record of a non-list, non-map field, or of an element of a
+// non-packed list.
+func (e *encoder) value(fd protoreflect.FieldDescriptor, num protowire.Number, v protoreflect.Value) {
+
+	switch fd.Kind() {
+	case protoreflect.MessageKind:
+		m := v.Message()
+		e.tag(num, protowire.BytesType)
+		e.varint(uint64(proto.Size(proto.MessageV1(m.Interface()))))
+		e.message(m)
+
+	case protoreflect.GroupKind:
+		e.tag(num, protowire.StartGroupType)
+		e.message(v.Message())
+		e.tag(num, protowire.EndGroupType)
+
+	case protoreflect.StringKind:
+		s := v.String()
+		if fd.Syntax() == protoreflect.Proto3 && !utf8.ValidString(s) {
+			e.err = errors.Errorf("field %s contains invalid UTF-8", fd.FullName())
+			return
+		}
+		e.tag(num, protowire.BytesType)
+		e.varint(uint64(len(s)))
+		e.raw([]byte(s))
+
+	case protoreflect.BytesKind:
+		b := v.Bytes()
+		e.tag(num, protowire.BytesType)
+		e.varint(uint64(len(b)))
+		e.raw(b)
+
+	default:
+		e.tag(num, wireType(fd.Kind()))
+		e.scalar(fd.Kind(), v)
+	}
+}
+
+// scalar writes the value of a scalar without tag.
+func (e *encoder) scalar(k protoreflect.Kind, v protoreflect.Value) {
+	if cap(e.buf)-len(e.buf) < binary.MaxVarintLen64 {
+		e.flush()
+	}
+	e.buf = appendScalar(e.buf, k, v)
+}
+
+func scalarSize(k protoreflect.Kind, v protoreflect.Value) int {
+	var b [binary.MaxVarintLen64]byte
+	return len(appendScalar(b[:0], k, v))
+}
+
+func wireType(k protoreflect.Kind) protowire.Type {
+	switch k {
+	case protoreflect.Fixed32Kind, protoreflect.Sfixed32Kind, protoreflect.FloatKind:
+		return protowire.Fixed32Type
+	case protoreflect.Fixed64Kind, protoreflect.Sfixed64Kind, protoreflect.DoubleKind:
+		return protowire.Fixed64Type
+	default:
+		return protowire.VarintType
+	}
+}
+
+func appendScalar(b []byte, k protoreflect.Kind, v protoreflect.Value) []byte {
+
+	switch k {
+	case protoreflect.BoolKind:
+		return protowire.AppendVarint(b, protowire.EncodeBool(v.Bool()))
+	case protoreflect.EnumKind:
+		return protowire.AppendVarint(b, uint64(v.Enum()))
+	case protoreflect.Int32Kind:
+		return protowire.AppendVarint(b, uint64(int32(v.Int())))
+	case protoreflect.Int64Kind:
+		return protowire.AppendVarint(b, uint64(v.Int()))
+	case protoreflect.Uint32Kind, protoreflect.Uint64Kind:
+		return protowire.AppendVarint(b, v.Uint())
+	case protoreflect.Sint32Kind:
+		return protowire.AppendVarint(b, protowire.EncodeZigZag(int64(int32(v.Int()))))
+	case protoreflect.Sint64Kind:
+		return protowire.AppendVarint(b, protowire.EncodeZigZag(v.Int()))
+	case protoreflect.Fixed32Kind:
+		return protowire.AppendFixed32(b, uint32(v.Uint()))
+	case protoreflect.Sfixed32Kind:
+		return protowire.AppendFixed32(b, uint32(v.Int()))
+	case protoreflect.FloatKind:
+		return protowire.AppendFixed32(b, math.Float32bits(float32(v.Float())))
+	case protoreflect.Fixed64Kind:
+		return protowire.AppendFixed64(b, v.Uint())
+	case protoreflect.Sfixed64Kind:
+		return protowire.AppendFixed64(b, uint64(v.Int()))
+	case protoreflect.DoubleKind:
+		return protowire.AppendFixed64(b, math.Float64bits(v.Float()))
+	default:
+		panic("unknown scalar kind: " + k.String())
+	}
+}
+
+// populatedFields returns fields of m that are marshaled, in field number
+// order.
+func populatedFields(m protoreflect.Message) []protoreflect.FieldDescriptor {
+
+	fields := m.Descriptor().Fields()
+
+	var fds []protoreflect.FieldDescriptor
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if m.Has(fd) {
+			fds = append(fds, fd)
+		}
+	}
+
+	sort.Slice(fds, func(i, j int) bool { return fds[i].Number() < fds[j].Number() })
+	return fds
+}
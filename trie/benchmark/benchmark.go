@@ -51,10 +51,10 @@ type MSABResult struct {
 	Btree    int `tw-title:"Btree"`
 }
 
-// FPRResult represent the false positive rate.
+// FPRResult represent the false positive rate, a ratio from 0 to 1.
 type FPRResult struct {
 	KeyCount int     `tw-title:"key-count"`
-	FPR      float64 `tw-title:"fpr" tw-fmt:"%.3f%%"`
+	FPR      float64 `tw-title:"fpr" tw-fmt:"%.5f"`
 }
 
 // MemResult is a alias of GetResult
@@ -25,3 +25,27 @@ func (b *Bitmap) indexit(opts ...string) {
 		}
 	}
 }
+
+// indexit rebuilds rank and select indexes of all bitmaps in a Slim.
+func (ns *Slim) indexit() {
+
+	indexBM := func(b *Bitmap, opt string) {
+		if b != nil {
+			b.indexit(opt)
+		}
+	}
+
+	indexVLen := func(va *VLenArray, presenceOpt string) {
+		if va != nil {
+			indexBM(va.PresenceBM, presenceOpt)
+			indexBM(va.PositionBM, "s32")
+		}
+	}
+
+	indexBM(ns.NodeTypeBM, "r64")
+	indexBM(ns.Inners, "r128")
+	indexBM(ns.ShortBM, "r64")
+	indexVLen(ns.InnerPrefixes, "r128")
+	indexVLen(ns.LeafPrefixes, "r64")
+	indexVLen(ns.Leaves, "r64")
+}
@@ -10,4 +10,29 @@ var (
 	// ErrIncompatible means it is trying to unmarshal data from an incompatible
 	// version.
 	ErrIncompatible = errors.New("incompatible with marshaled data")
+
+	// ErrEncoderMismatch means the encoder of a SlimTrie is not the same as
+	// the one recorded in marshaled data.
+	ErrEncoderMismatch = errors.New("encoder mismatch with marshaled data")
+
+	// ErrCorrupted means marshaled data does not match its checksum.
+	// The error message tells which section is corrupted.
+	ErrCorrupted = errors.New("marshaled data corrupted")
+
+	// ErrMalformed means the internal structure of a SlimTrie is inconsistent,
+	// e.g., it is unmarshaled from crafted or truncated data.
+	// The error message tells which component is malformed.
+	ErrMalformed = errors.New("malformed slim data")
+
+	// ErrUnsupportedVersion means MarshalVersion is asked to marshal to a
+	// version it does not support.
+	ErrUnsupportedVersion = errors.New("unsupported version to marshal")
+
+	// ErrUnrepresentable means a feature of a SlimTrie can not be represented
+	// in the format of the version to marshal to.
+	// The error message tells which feature it is.
+	ErrUnrepresentable = errors.New("unrepresentable in the version")
+
+	// ErrOverBudget means ChooseOpt finds no option that fits the Budget.
+	ErrOverBudget = errors.New("no option fits the budget")
 )
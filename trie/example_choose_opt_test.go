@@ -0,0 +1,28 @@
+package trie
+
+import (
+	"fmt"
+
+	"github.com/openacid/slim/encode"
+)
+
+func ExampleChooseOpt() {
+
+	keys := []string{"abc", "abcd", "abd", "abde", "bc", "bcd", "bcde", "cde"}
+	values := []int32{0, 1, 2, 3, 4, 5, 6, 7}
+
+	sz, _ := EstimateSize(keys, 4, Opt{Complete: Bool(true)})
+	fmt.Println("complete:", sz)
+
+	// Not enough memory for a complete SlimTrie, accept some false positives.
+	opt, _ := ChooseOpt(encode.I32{}, keys, values, Budget{MaxBytes: 150, MaxFPR: 0.01})
+	fmt.Println("inner prefix:", *opt.InnerPrefix)
+
+	st, _ := NewSlimTrie(encode.I32{}, keys, values, opt)
+	fmt.Printf("expected fpr: %.4f\n", st.Stat().ExpectedFPR)
+
+	// Output:
+	// complete: 174
+	// inner prefix: true
+	// expected fpr: 0.0039
+}
@@ -0,0 +1,38 @@
+package trie
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/openacid/slim/encode"
+)
+
+func ExampleReadMetadata() {
+
+	st, _ := NewSlimTrie(encode.I32{}, []string{"a", "b"}, []int32{1, 2})
+	st.Metadata = map[string][]byte{"source": []byte("keys.csv")}
+
+	buf, _ := st.Marshal()
+
+	md, _ := ReadMetadata(bytes.NewReader(buf))
+	fmt.Println(string(md["source"]))
+
+	// Output:
+	// keys.csv
+}
+
+func ExampleReadHeader() {
+
+	st, _ := NewSlimTrie(encode.I32{}, []string{"a", "b"}, []int32{1, 2}, Opt{InnerPrefix: Bool(true)})
+	st.Metadata = map[string][]byte{"source": []byte("keys.csv")}
+
+	buf, _ := st.Marshal()
+
+	opt, md, _ := ReadHeader(bytes.NewReader(buf))
+	fmt.Println(*opt.InnerPrefix, *opt.LeafPrefix)
+	fmt.Println(string(md["source"]))
+
+	// Output:
+	// true false
+	// keys.csv
+}
@@ -147,7 +147,7 @@ func fprGet() {
 		script := `
 fn = "report/fpr_get.data"
 set yr [0:0.05]
-set format y "%g%%"
+set format y "%g"
 set xlabel 'key-count: n'
 set ylabel 'false positive'
 `
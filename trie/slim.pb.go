@@ -43,7 +43,7 @@ func (m *Bitmap) Reset()         { *m = Bitmap{} }
 func (m *Bitmap) String() string { return proto.CompactTextString(m) }
 func (*Bitmap) ProtoMessage()    {}
 func (*Bitmap) Descriptor() ([]byte, []int) {
-	return fileDescriptor_slim_a15a3a1219580880, []int{0}
+	return fileDescriptor_slim_e1a7ee7b3f7448c6, []int{0}
 }
 func (m *Bitmap) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Bitmap.Unmarshal(m, b)
@@ -121,7 +121,7 @@ func (m *VLenArray) Reset()         { *m = VLenArray{} }
 func (m *VLenArray) String() string { return proto.CompactTextString(m) }
 func (*VLenArray) ProtoMessage()    {}
 func (*VLenArray) Descriptor() ([]byte, []int) {
-	return fileDescriptor_slim_a15a3a1219580880, []int{1}
+	return fileDescriptor_slim_e1a7ee7b3f7448c6, []int{1}
 }
 func (m *VLenArray) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_VLenArray.Unmarshal(m, b)
@@ -264,7 +264,7 @@ func (m *Slim) Reset()         { *m = Slim{} }
 func (m *Slim) String() string { return proto.CompactTextString(m) }
 func (*Slim) ProtoMessage()    {}
 func (*Slim) Descriptor() ([]byte, []int) {
-	return fileDescriptor_slim_a15a3a1219580880, []int{2}
+	return fileDescriptor_slim_e1a7ee7b3f7448c6, []int{2}
 }
 func (m *Slim) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Slim.Unmarshal(m, b)
@@ -347,39 +347,334 @@ func (m *Slim) GetLeaves() *VLenArray {
 	return nil
 }
 
+// SlimHeader stores info about how to use a Slim.
+// Since 0.5.13 it is marshaled before Slim.
+//
+// Since 0.5.13
+type SlimHeader struct {
+	// Encoder is the registered name of the encoder of leaf values.
+	// See encode.Describer.
+	// It is empty if the encoder can not be described.
+	//
+	// Since 0.5.13
+	Encoder string `protobuf:"bytes,10,opt,name=Encoder,proto3" json:"Encoder,omitempty"`
+	// EncoderParams is the params to rebuild the encoder of leaf values.
+	//
+	// Since 0.5.13
+	EncoderParams []byte `protobuf:"bytes,11,opt,name=EncoderParams,proto3" json:"EncoderParams,omitempty"`
+	// Checksums of sections of the marshaled Slim that follows, and of the
+	// other fields of this SlimHeader.
+	// A section is all of the records of a field in Slim or SlimHeader.
+	// It is empty if data is not checksummed.
+	//
+	// Since 0.5.13
+	Checksums []*SectionChecksum `protobuf:"bytes,20,rep,name=Checksums,proto3" json:"Checksums,omitempty"`
+	// Opt is the options used to create the SlimTrie.
+	// It is absent if the SlimTrie is loaded from data of a version before
+	// 0.5.13, which does not record it.
+	//
+	// Since 0.5.13
+	Opt *SlimOpt `protobuf:"bytes,30,opt,name=Opt,proto3" json:"Opt,omitempty"`
+	// Metadata is user defined key-value pairs, sorted by key.
+	//
+	// Since 0.5.13
+	Metadata []*MetadataEntry `protobuf:"bytes,31,rep,name=Metadata,proto3" json:"Metadata,omitempty"`
+	// Compact indicates the Slim that follows is written by MarshalCompact:
+	// RankIndex and SelectIndex of every Bitmap are omitted and Bytes of
+	// every VLenArray are compressed with flate.
+	//
+	// Since 0.5.13
+	Compact              bool     `protobuf:"varint,40,opt,name=Compact,proto3" json:"Compact,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SlimHeader) Reset()         { *m = SlimHeader{} }
+func (m *SlimHeader) String() string { return proto.CompactTextString(m) }
+func (*SlimHeader) ProtoMessage()    {}
+func (*SlimHeader) Descriptor() ([]byte, []int) {
+	return fileDescriptor_slim_e1a7ee7b3f7448c6, []int{3}
+}
+func (m *SlimHeader) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SlimHeader.Unmarshal(m, b)
+}
+func (m *SlimHeader) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SlimHeader.Marshal(b, m, deterministic)
+}
+func (dst *SlimHeader) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SlimHeader.Merge(dst, src)
+}
+func (m *SlimHeader) XXX_Size() int {
+	return xxx_messageInfo_SlimHeader.Size(m)
+}
+func (m *SlimHeader) XXX_DiscardUnknown() {
+	xxx_messageInfo_SlimHeader.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SlimHeader proto.InternalMessageInfo
+
+func (m *SlimHeader) GetEncoder() string {
+	if m != nil {
+		return m.Encoder
+	}
+	return ""
+}
+
+func (m *SlimHeader) GetEncoderParams() []byte {
+	if m != nil {
+		return m.EncoderParams
+	}
+	return nil
+}
+
+func (m *SlimHeader) GetChecksums() []*SectionChecksum {
+	if m != nil {
+		return m.Checksums
+	}
+	return nil
+}
+
+func (m *SlimHeader) GetOpt() *SlimOpt {
+	if m != nil {
+		return m.Opt
+	}
+	return nil
+}
+
+func (m *SlimHeader) GetMetadata() []*MetadataEntry {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+func (m *SlimHeader) GetCompact() bool {
+	if m != nil {
+		return m.Compact
+	}
+	return false
+}
+
+// SlimOpt is the serialized form of a normalized Opt.
+//
+// Since 0.5.13
+type SlimOpt struct {
+	DedupValue           bool     `protobuf:"varint,10,opt,name=DedupValue,proto3" json:"DedupValue,omitempty"`
+	InnerPrefix          bool     `protobuf:"varint,11,opt,name=InnerPrefix,proto3" json:"InnerPrefix,omitempty"`
+	LeafPrefix           bool     `protobuf:"varint,12,opt,name=LeafPrefix,proto3" json:"LeafPrefix,omitempty"`
+	Complete             bool     `protobuf:"varint,13,opt,name=Complete,proto3" json:"Complete,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SlimOpt) Reset()         { *m = SlimOpt{} }
+func (m *SlimOpt) String() string { return proto.CompactTextString(m) }
+func (*SlimOpt) ProtoMessage()    {}
+func (*SlimOpt) Descriptor() ([]byte, []int) {
+	return fileDescriptor_slim_e1a7ee7b3f7448c6, []int{4}
+}
+func (m *SlimOpt) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SlimOpt.Unmarshal(m, b)
+}
+func (m *SlimOpt) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SlimOpt.Marshal(b, m, deterministic)
+}
+func (dst *SlimOpt) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SlimOpt.Merge(dst, src)
+}
+func (m *SlimOpt) XXX_Size() int {
+	return xxx_messageInfo_SlimOpt.Size(m)
+}
+func (m *SlimOpt) XXX_DiscardUnknown() {
+	xxx_messageInfo_SlimOpt.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SlimOpt proto.InternalMessageInfo
+
+func (m *SlimOpt) GetDedupValue() bool {
+	if m != nil {
+		return m.DedupValue
+	}
+	return false
+}
+
+func (m *SlimOpt) GetInnerPrefix() bool {
+	if m != nil {
+		return m.InnerPrefix
+	}
+	return false
+}
+
+func (m *SlimOpt) GetLeafPrefix() bool {
+	if m != nil {
+		return m.LeafPrefix
+	}
+	return false
+}
+
+func (m *SlimOpt) GetComplete() bool {
+	if m != nil {
+		return m.Complete
+	}
+	return false
+}
+
+// MetadataEntry is a user defined key-value pair.
+//
+// Since 0.5.13
+type MetadataEntry struct {
+	Key                  string   `protobuf:"bytes,10,opt,name=Key,proto3" json:"Key,omitempty"`
+	Value                []byte   `protobuf:"bytes,11,opt,name=Value,proto3" json:"Value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MetadataEntry) Reset()         { *m = MetadataEntry{} }
+func (m *MetadataEntry) String() string { return proto.CompactTextString(m) }
+func (*MetadataEntry) ProtoMessage()    {}
+func (*MetadataEntry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_slim_e1a7ee7b3f7448c6, []int{5}
+}
+func (m *MetadataEntry) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MetadataEntry.Unmarshal(m, b)
+}
+func (m *MetadataEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MetadataEntry.Marshal(b, m, deterministic)
+}
+func (dst *MetadataEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MetadataEntry.Merge(dst, src)
+}
+func (m *MetadataEntry) XXX_Size() int {
+	return xxx_messageInfo_MetadataEntry.Size(m)
+}
+func (m *MetadataEntry) XXX_DiscardUnknown() {
+	xxx_messageInfo_MetadataEntry.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MetadataEntry proto.InternalMessageInfo
+
+func (m *MetadataEntry) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *MetadataEntry) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+// SectionChecksum is the checksum of a section in marshaled data.
+//
+// Since 0.5.13
+type SectionChecksum struct {
+	// Section is the name of the section, such as "Inners".
+	//
+	// Since 0.5.13
+	Section string `protobuf:"bytes,10,opt,name=Section,proto3" json:"Section,omitempty"`
+	// CRC32C is the CRC-32 checksum with Castagnoli polynomial.
+	//
+	// Since 0.5.13
+	CRC32C               uint32   `protobuf:"fixed32,11,opt,name=CRC32C,proto3" json:"CRC32C,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SectionChecksum) Reset()         { *m = SectionChecksum{} }
+func (m *SectionChecksum) String() string { return proto.CompactTextString(m) }
+func (*SectionChecksum) ProtoMessage()    {}
+func (*SectionChecksum) Descriptor() ([]byte, []int) {
+	return fileDescriptor_slim_e1a7ee7b3f7448c6, []int{6}
+}
+func (m *SectionChecksum) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SectionChecksum.Unmarshal(m, b)
+}
+func (m *SectionChecksum) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SectionChecksum.Marshal(b, m, deterministic)
+}
+func (dst *SectionChecksum) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SectionChecksum.Merge(dst, src)
+}
+func (m *SectionChecksum) XXX_Size() int {
+	return xxx_messageInfo_SectionChecksum.Size(m)
+}
+func (m *SectionChecksum) XXX_DiscardUnknown() {
+	xxx_messageInfo_SectionChecksum.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SectionChecksum proto.InternalMessageInfo
+
+func (m *SectionChecksum) GetSection() string {
+	if m != nil {
+		return m.Section
+	}
+	return ""
+}
+
+func (m *SectionChecksum) GetCRC32C() uint32 {
+	if m != nil {
+		return m.CRC32C
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*Bitmap)(nil), "Bitmap")
 	proto.RegisterType((*VLenArray)(nil), "VLenArray")
 	proto.RegisterType((*Slim)(nil), "Slim")
-}
-
-func init() { proto.RegisterFile("slim.proto", fileDescriptor_slim_a15a3a1219580880) }
-
-var fileDescriptor_slim_a15a3a1219580880 = []byte{
-	// 388 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x92, 0x41, 0xaf, 0x93, 0x40,
-	0x14, 0x85, 0x83, 0xa5, 0xbc, 0xbe, 0x5b, 0x78, 0x92, 0x49, 0xa3, 0xb3, 0x30, 0xed, 0xc8, 0x42,
-	0x59, 0x11, 0xa3, 0x3b, 0xa3, 0x0b, 0x31, 0x9a, 0xb4, 0x69, 0x9b, 0x66, 0x68, 0x34, 0x71, 0x61,
-	0x42, 0xcb, 0xad, 0x4e, 0xa4, 0x43, 0x33, 0x4c, 0x4c, 0xeb, 0x1f, 0xf3, 0x47, 0xf9, 0x27, 0x0c,
-	0x43, 0x05, 0x6a, 0xdf, 0x8e, 0xfb, 0x9d, 0x73, 0x0f, 0xe7, 0x06, 0x00, 0xca, 0x5c, 0xec, 0xa3,
-	0x83, 0x2a, 0x74, 0x11, 0x7c, 0x05, 0x27, 0x16, 0x7a, 0x9f, 0x1e, 0xc8, 0x08, 0xfa, 0x9f, 0x0b,
-	0x95, 0x95, 0x74, 0xc4, 0x7a, 0xa1, 0xcd, 0xeb, 0x81, 0x3c, 0x81, 0x5b, 0x9e, 0xca, 0x1f, 0x53,
-	0x99, 0xe1, 0x91, 0x8e, 0x59, 0x2f, 0xec, 0xf3, 0x16, 0x10, 0x06, 0xc3, 0x04, 0x73, 0xdc, 0xea,
-	0x5a, 0x0f, 0x8d, 0xde, 0x45, 0xc1, 0x6f, 0x0b, 0x6e, 0x3f, 0xcd, 0x51, 0xbe, 0x53, 0x2a, 0x3d,
-	0x11, 0x17, 0xac, 0x25, 0x05, 0x66, 0x85, 0x7d, 0x6e, 0x2d, 0xc9, 0x23, 0x70, 0x3e, 0xe4, 0xfa,
-	0xbd, 0xd4, 0x74, 0x68, 0xd0, 0x79, 0x22, 0xcf, 0x01, 0x56, 0x0a, 0x4b, 0x94, 0x5b, 0x8c, 0x17,
-	0xf4, 0x2d, 0xb3, 0xc2, 0xe1, 0xcb, 0x9b, 0xa8, 0xae, 0xc9, 0x3b, 0x92, 0x31, 0x16, 0xa5, 0xd0,
-	0xa2, 0x90, 0xf1, 0x82, 0x8e, 0xfe, 0x37, 0x36, 0x52, 0x75, 0xc5, 0x47, 0x71, 0xc4, 0x2c, 0x11,
-	0xbf, 0x90, 0x3e, 0x36, 0x2f, 0x6b, 0x41, 0x75, 0x79, 0x7c, 0xd2, 0x58, 0xd2, 0x31, 0xb3, 0x42,
-	0x97, 0xd7, 0x43, 0xf0, 0xe7, 0x01, 0xd8, 0x49, 0x2e, 0xf6, 0xd5, 0x91, 0xb1, 0xf8, 0x36, 0x95,
-	0x12, 0x55, 0xdb, 0xb5, 0x8b, 0xaa, 0xf8, 0xe4, 0x7b, 0xa1, 0xb4, 0x89, 0xbf, 0xab, 0xe3, 0x1b,
-	0x50, 0xb5, 0x5c, 0x16, 0x19, 0xae, 0x4f, 0x07, 0xbc, 0xa7, 0x65, 0x2b, 0x91, 0x09, 0x38, 0x26,
-	0xb2, 0x2e, 0xd2, 0x31, 0x9d, 0x31, 0x79, 0x0a, 0x37, 0x26, 0x36, 0x5e, 0xd0, 0xc9, 0xa5, 0xe3,
-	0x1f, 0x27, 0x63, 0x00, 0xf3, 0xb8, 0x4e, 0x37, 0x39, 0x52, 0xc6, 0x7a, 0xa1, 0xc7, 0x3b, 0x84,
-	0xbc, 0x00, 0xcf, 0x84, 0xad, 0x14, 0xee, 0xc4, 0x11, 0x4b, 0xfa, 0xcc, 0x04, 0x41, 0xd4, 0x7c,
-	0x24, 0x7e, 0x69, 0x20, 0x11, 0xb8, 0x73, 0x4c, 0x77, 0xcd, 0xc2, 0xeb, 0xab, 0x85, 0x0b, 0x9d,
-	0x04, 0xe0, 0xcc, 0x31, 0xfd, 0x89, 0x25, 0x7d, 0x73, 0xe5, 0x3c, 0x2b, 0x33, 0x7b, 0xe0, 0xfa,
-	0xde, 0xcc, 0x1e, 0x78, 0xfe, 0xdd, 0xcc, 0x1e, 0x3c, 0xf4, 0xfd, 0xd8, 0xf9, 0x62, 0x6b, 0x25,
-	0x70, 0xe3, 0x98, 0xdf, 0xf2, 0xd5, 0xdf, 0x00, 0x00, 0x00, 0xff, 0xff, 0x00, 0x30, 0xd4, 0xc4,
-	0xa4, 0x02, 0x00, 0x00,
+	proto.RegisterType((*SlimHeader)(nil), "SlimHeader")
+	proto.RegisterType((*SlimOpt)(nil), "SlimOpt")
+	proto.RegisterType((*MetadataEntry)(nil), "MetadataEntry")
+	proto.RegisterType((*SectionChecksum)(nil), "SectionChecksum")
+}
+
+func init() { proto.RegisterFile("slim.proto", fileDescriptor_slim_e1a7ee7b3f7448c6) }
+
+var fileDescriptor_slim_e1a7ee7b3f7448c6 = []byte{
+	// 605 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x54, 0xdd, 0x6e, 0xd3, 0x4c,
+	0x10, 0x95, 0xbf, 0xa4, 0x89, 0x33, 0x89, 0xdb, 0x68, 0x55, 0x7d, 0xac, 0x2a, 0xd4, 0x1a, 0x0b,
+	0x81, 0xc5, 0x85, 0x85, 0xda, 0x0b, 0x24, 0x04, 0x17, 0xd8, 0x14, 0xd1, 0xd2, 0xb4, 0xd5, 0xa6,
+	0x2a, 0x12, 0x17, 0x48, 0xdb, 0x78, 0x4a, 0xad, 0xfa, 0x4f, 0xeb, 0x2d, 0x4a, 0x78, 0x01, 0x1e,
+	0x89, 0xb7, 0xe1, 0x05, 0x78, 0x09, 0xb4, 0xeb, 0xdf, 0xb4, 0xdc, 0xed, 0x39, 0x67, 0x7c, 0x7c,
+	0x66, 0x66, 0x6d, 0x80, 0x22, 0x8e, 0x12, 0x2f, 0x17, 0x99, 0xcc, 0x9c, 0xaf, 0x30, 0xf0, 0x23,
+	0x99, 0xf0, 0x9c, 0x6c, 0xc3, 0xc6, 0xe7, 0x4c, 0x84, 0x05, 0xdd, 0xb6, 0x7b, 0x6e, 0x9f, 0x95,
+	0x80, 0x3c, 0x86, 0x11, 0xe3, 0xe9, 0xed, 0x51, 0x1a, 0xe2, 0x92, 0xee, 0xda, 0x3d, 0x77, 0x83,
+	0xb5, 0x04, 0xb1, 0x61, 0x3c, 0xc7, 0x18, 0x17, 0xb2, 0xd4, 0x5d, 0xad, 0x77, 0x29, 0xe7, 0x97,
+	0x01, 0xa3, 0xcb, 0x13, 0x4c, 0xdf, 0x09, 0xc1, 0x57, 0x64, 0x02, 0xc6, 0x29, 0x05, 0xdb, 0x70,
+	0x37, 0x98, 0x71, 0x4a, 0xfe, 0x87, 0xc1, 0x61, 0x2c, 0x83, 0x54, 0xd2, 0xb1, 0xa6, 0x2a, 0x44,
+	0x9e, 0x03, 0x9c, 0x0b, 0x2c, 0x30, 0x5d, 0xa0, 0x3f, 0xa3, 0x6f, 0x6d, 0xc3, 0x1d, 0xef, 0x0f,
+	0xbd, 0x32, 0x26, 0xeb, 0x48, 0xba, 0x30, 0x2b, 0x22, 0x19, 0x65, 0xa9, 0x3f, 0xa3, 0xdb, 0xf7,
+	0x0b, 0x1b, 0x49, 0x75, 0xf1, 0x21, 0x5a, 0x62, 0x38, 0x8f, 0x7e, 0x20, 0x7d, 0xa4, 0x5f, 0xd6,
+	0x12, 0xaa, 0x73, 0x7f, 0x25, 0xb1, 0xa0, 0xbb, 0xb6, 0xe1, 0x4e, 0x58, 0x09, 0x9c, 0x3f, 0xff,
+	0x41, 0x7f, 0x1e, 0x47, 0x89, 0x6a, 0xd2, 0x8f, 0xbe, 0x1d, 0xa5, 0x29, 0x8a, 0x36, 0x6b, 0x97,
+	0x52, 0xf6, 0xf3, 0x9b, 0x4c, 0x48, 0x6d, 0xbf, 0x59, 0xda, 0x37, 0x84, 0x4a, 0x79, 0x9a, 0x85,
+	0x78, 0xb1, 0xca, 0xf1, 0x1f, 0x29, 0x5b, 0x89, 0xec, 0xc1, 0x40, 0x5b, 0x96, 0x41, 0x3a, 0x45,
+	0x15, 0x4d, 0x9e, 0xc0, 0x50, 0xdb, 0xfa, 0x33, 0xba, 0xb7, 0x5e, 0x51, 0xf3, 0x64, 0x17, 0x40,
+	0x1f, 0x2f, 0xf8, 0x55, 0x8c, 0xd4, 0xb6, 0x7b, 0xae, 0xc5, 0x3a, 0x0c, 0x79, 0x09, 0x96, 0x36,
+	0x3b, 0x17, 0x78, 0x1d, 0x2d, 0xb1, 0xa0, 0xcf, 0xb4, 0x11, 0x78, 0xcd, 0x92, 0xd8, 0x7a, 0x01,
+	0xf1, 0x60, 0x72, 0x82, 0xfc, 0xba, 0x79, 0xe0, 0xf5, 0x83, 0x07, 0xd6, 0x74, 0xe2, 0xc0, 0xe0,
+	0x04, 0xf9, 0x77, 0x2c, 0xe8, 0x9b, 0x07, 0x95, 0x95, 0x72, 0xdc, 0x37, 0x27, 0x53, 0xeb, 0xb8,
+	0x6f, 0x5a, 0xd3, 0xcd, 0xe3, 0xbe, 0xb9, 0x35, 0x9d, 0x3a, 0xbf, 0x0d, 0x00, 0x35, 0xed, 0x8f,
+	0xc8, 0x43, 0x14, 0x84, 0xc2, 0xf0, 0x30, 0x5d, 0x64, 0x21, 0x0a, 0x7d, 0x5d, 0x46, 0xac, 0x86,
+	0xe4, 0x29, 0x58, 0xd5, 0xf1, 0x9c, 0x0b, 0x9e, 0x14, 0x7a, 0x1f, 0x13, 0xb6, 0x4e, 0x12, 0x0f,
+	0x46, 0xc1, 0x0d, 0x2e, 0x6e, 0x8b, 0xbb, 0xa4, 0xbc, 0xd0, 0xe3, 0xfd, 0xa9, 0x37, 0xc7, 0x85,
+	0xba, 0x0f, 0xb5, 0xc0, 0xda, 0x12, 0xb2, 0x03, 0xbd, 0xb3, 0x5c, 0x56, 0x73, 0x37, 0x3d, 0x95,
+	0xe4, 0x2c, 0x97, 0x4c, 0x91, 0xe4, 0x05, 0x98, 0x33, 0x94, 0x3c, 0xe4, 0x92, 0xd3, 0x3d, 0x6d,
+	0xb5, 0xe9, 0xd5, 0xc4, 0x61, 0x2a, 0xc5, 0x8a, 0x35, 0xba, 0xca, 0x1d, 0x64, 0x49, 0xce, 0x17,
+	0x92, 0xba, 0xb6, 0xe1, 0x9a, 0xac, 0x86, 0xce, 0x4f, 0x03, 0x86, 0x95, 0xad, 0x5a, 0xd2, 0x7b,
+	0x0c, 0xef, 0xf2, 0x4b, 0x1e, 0xdf, 0xa1, 0x6e, 0xd0, 0x64, 0x1d, 0x46, 0xdd, 0xb8, 0xce, 0x0e,
+	0x74, 0x87, 0x26, 0xeb, 0x52, 0xca, 0xa1, 0x1d, 0x3a, 0x9d, 0x94, 0x0e, 0x2d, 0x43, 0x76, 0xc0,
+	0x54, 0x2f, 0x8e, 0x51, 0x22, 0xb5, 0xb4, 0xda, 0x60, 0xe7, 0x15, 0x58, 0x6b, 0xf1, 0xc9, 0x14,
+	0x7a, 0x9f, 0x70, 0x55, 0x0d, 0x5a, 0x1d, 0xd5, 0x17, 0x51, 0x66, 0x2b, 0x87, 0x5b, 0x02, 0x27,
+	0x80, 0xad, 0x7b, 0x23, 0x54, 0xfd, 0x56, 0x54, 0xbd, 0xa7, 0x0a, 0xaa, 0x8f, 0x3b, 0x60, 0xc1,
+	0xc1, 0x7e, 0xa0, 0x3d, 0x86, 0xac, 0x42, 0xfe, 0xe0, 0x4b, 0x5f, 0x8a, 0x08, 0xaf, 0x06, 0xfa,
+	0xff, 0x73, 0xf0, 0x77, 0x00, 0x5e, 0xb9, 0xa9, 0x80, 0x8d, 0x04, 0x00, 0x00,
 }
@@ -78,6 +78,22 @@ type SlimTrie struct {
 	vars    *slimVars
 	levels  []levelInfo
 	encoder encode.Encoder
+
+	// opt is the normalized Opt used to create it.
+	// It is nil if it is loaded from data of a version before 0.5.13.
+	opt *Opt
+
+	// fpr caches ExpectedFPR, it is reset when the SlimTrie is built or
+	// loaded.
+	fpr *fprCache
+
+	// Metadata is user defined key-value pairs, such as where the keys come
+	// from or when it is built.
+	// It is persisted by Marshal and restored by Unmarshal, and can be read
+	// without loading the SlimTrie with ReadMetadata.
+	//
+	// Since 0.5.13
+	Metadata map[string][]byte
 }
 
 // Opt specifies options for creating a SlimTrie.
@@ -165,10 +181,22 @@ func (ns *Slim) GetVersion() string {
 	return slimtrieVersion
 }
 
+func (h *SlimHeader) GetVersion() string {
+	return slimtrieVersion
+}
+
 func (st *SlimTrie) GetVersion() string {
 	return slimtrieVersion
 }
 
+// Encoder returns the encoder of values.
+// It is nil if it is created or loaded without an encoder.
+//
+// Since 0.5.13
+func (st *SlimTrie) Encoder() encode.Encoder {
+	return st.encoder
+}
+
 func (st *SlimTrie) compatibleVersions() []string {
 	return []string{
 		"==1.0.0", // before 0.5.8 it is "1.0.0" for historical reason.
@@ -176,6 +204,7 @@ func (st *SlimTrie) compatibleVersions() []string {
 		"==0.5.9",
 		"==0.5.10",
 		"==0.5.11",
+		"==0.5.12",
 		"==" + slimtrieVersion,
 	}
 }
@@ -224,6 +253,7 @@ func NewSlimTrie(e encode.Encoder, keys []string, values interface{}, opts ...Op
 	st := &SlimTrie{
 		inner:   ns,
 		encoder: e,
+		opt:     &opt,
 	}
 	st.init()
 	return st, nil
@@ -250,4 +280,5 @@ func (st *SlimTrie) content() []string {
 func (st *SlimTrie) init() {
 	st.initVars()
 	st.initLevels()
+	st.fpr = &fprCache{}
 }
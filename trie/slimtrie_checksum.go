@@ -0,0 +1,155 @@
+package trie
+
+import (
+	"fmt"
+	"hash/crc32"
+	"io"
+
+	"github.com/openacid/errors"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+var crc32cTable = crc32.MakeTable(crc32.Castagnoli)
+
+// slimSections maps field number in Slim to section name.
+var slimSections = map[protowire.Number]string{
+	11: "BigInnerCnt",
+	14: "ShortSize",
+	20: "NodeTypeBM",
+	30: "Inners",
+	31: "ShortBM",
+	32: "ShortTable",
+	38: "InnerPrefixes",
+	58: "LeafPrefixes",
+	60: "Leaves",
+}
+
+// headerSections maps field number in SlimHeader to section name.
+// Checksums, field 20, is not checksummed.
+var headerSections = map[protowire.Number]string{
+	10: "SlimHeader.Encoder",
+	11: "SlimHeader.EncoderParams",
+	30: "SlimHeader.Opt",
+	31: "SlimHeader.Metadata",
+	40: "SlimHeader.Compact",
+}
+
+// checksumsField is the field number of SlimHeader.Checksums.
+const checksumsField protowire.Number = 20
+
+// sectionHasher calculates CRC32C of every section of a marshaled Slim or
+// SlimHeader, in the order a section first appears.
+// A section is all records, including tags, of a field.
+type sectionHasher struct {
+	sums   []*SectionChecksum
+	idx    map[protowire.Number]int
+	names  map[protowire.Number]string
+	prefix string
+
+	// skip is the field number not to hash, 0 for none.
+	skip protowire.Number
+}
+
+func newSectionHasher() *sectionHasher {
+	return &sectionHasher{
+		idx:   make(map[protowire.Number]int),
+		names: slimSections,
+	}
+}
+
+// newHeaderHasher creates a sectionHasher for SlimHeader, which hashes every
+// field except Checksums.
+func newHeaderHasher() *sectionHasher {
+	return &sectionHasher{
+		idx:    make(map[protowire.Number]int),
+		names:  headerSections,
+		prefix: "SlimHeader.",
+		skip:   checksumsField,
+	}
+}
+
+func (sh *sectionHasher) sectionName(num protowire.Number) string {
+	name, ok := sh.names[num]
+	if !ok {
+		name = fmt.Sprintf("%sfield-%d", sh.prefix, num)
+	}
+	return name
+}
+
+// add hashes a record, or the next part of a record, of field num.
+func (sh *sectionHasher) add(num protowire.Number, rec []byte) {
+
+	if num == sh.skip {
+		return
+	}
+
+	i, ok := sh.idx[num]
+	if !ok {
+		i = len(sh.sums)
+		sh.idx[num] = i
+		sh.sums = append(sh.sums, &SectionChecksum{Section: sh.sectionName(num)})
+	}
+	sh.sums[i].CRC32C = crc32.Update(sh.sums[i].CRC32C, crc32cTable, rec)
+}
+
+// writer returns an io.Writer that hashes data written to it as records of
+// field num.
+func (sh *sectionHasher) writer(num protowire.Number) io.Writer {
+	return &sectionWriter{sh: sh, num: num}
+}
+
+type sectionWriter struct {
+	sh  *sectionHasher
+	num protowire.Number
+}
+
+func (w *sectionWriter) Write(p []byte) (int, error) {
+	if len(p) > 0 {
+		w.sh.add(w.num, p)
+	}
+	return len(p), nil
+}
+
+// addRecords hashes every record in b.
+func (sh *sectionHasher) addRecords(b []byte) error {
+
+	for len(b) > 0 {
+		num, _, n := protowire.ConsumeField(b)
+		if n < 0 {
+			return errors.Wrapf(ErrCorrupted, "malformed record: %v", protowire.ParseError(n))
+		}
+
+		sh.add(num, b[:n])
+		b = b[n:]
+	}
+
+	return nil
+}
+
+// verifyChecksums checks the hashed sections against the recorded checksums.
+// A section that is not recorded or is missing is considered corrupted too.
+func verifyChecksums(got, want []*SectionChecksum) error {
+
+	wantCRC := make(map[string]uint32, len(want))
+	for _, c := range want {
+		wantCRC[c.Section] = c.CRC32C
+	}
+
+	for _, c := range got {
+		w, ok := wantCRC[c.Section]
+		if !ok {
+			return errors.Wrapf(ErrCorrupted, "section: %s: no checksum", c.Section)
+		}
+		if w != c.CRC32C {
+			return errors.Wrapf(ErrCorrupted, "section: %s: checksum: %08x, expected: %08x",
+				c.Section, c.CRC32C, w)
+		}
+		delete(wantCRC, c.Section)
+	}
+
+	for s := range wantCRC {
+		return errors.Wrapf(ErrCorrupted, "section: %s: missing", s)
+	}
+
+	return nil
+}
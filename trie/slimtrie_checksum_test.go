@@ -0,0 +1,183 @@
+package trie
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/openacid/errors"
+	"github.com/openacid/low/pbcmpl"
+	"github.com/openacid/slim/encode"
+	"github.com/openacid/slim/internal/pbstream"
+	"github.com/stretchr/testify/require"
+)
+
+func newChecksumTestTrie(t *testing.T) (*SlimTrie, []byte, int) {
+
+	ta := require.New(t)
+
+	keys := getKeys("20kl10")[:500]
+	values := makeI32s(len(keys))
+
+	st, err := NewSlimTrie(encode.I32{}, keys, values, Opt{Complete: Bool(true)})
+	ta.NoError(err)
+
+	buf, err := st.Marshal()
+	ta.NoError(err)
+
+	n, h, err := pbcmpl.ReadHeader(bytes.NewReader(buf))
+	ta.NoError(err)
+
+	// offset of Slim body
+	bodyOffset := int(n+h.GetBodySize()) + pbcmpl.HeaderSize(st.inner)
+
+	return st, buf, bodyOffset
+}
+
+func TestSlimTrie_Marshal_checksums(t *testing.T) {
+
+	ta := require.New(t)
+
+	_, buf, _ := newChecksumTestTrie(t)
+
+	h := &SlimHeader{}
+	_, _, err := pbcmpl.Unmarshal(bytes.NewReader(buf), h)
+	ta.NoError(err)
+
+	var sections []string
+	for _, c := range h.Checksums {
+		sections = append(sections, c.Section)
+	}
+	ta.Equal([]string{
+		"SlimHeader.Encoder",
+		"SlimHeader.Opt",
+		"BigInnerCnt",
+		"ShortSize",
+		"NodeTypeBM",
+		"Inners",
+		"ShortBM",
+		"ShortTable",
+		"InnerPrefixes",
+		"LeafPrefixes",
+		"Leaves",
+	}, sections)
+}
+
+func TestSlimTrie_Unmarshal_corrupted(t *testing.T) {
+
+	ta := require.New(t)
+
+	st, buf, bodyOffset := newChecksumTestTrie(t)
+
+	for i := bodyOffset; i < len(buf); i++ {
+		bad := append([]byte{}, buf...)
+		bad[i] ^= 0x10
+
+		st2 := &SlimTrie{encoder: encode.I32{}}
+		err := st2.Unmarshal(bad)
+		ta.Equal(ErrCorrupted, errors.Cause(err), "flip byte at: %d", i)
+	}
+
+	// The last byte is in Leaves
+
+	bad := append([]byte{}, buf...)
+	bad[len(bad)-1] ^= 0x10
+
+	st2 := &SlimTrie{encoder: encode.I32{}}
+	err := st2.Unmarshal(bad)
+	ta.Contains(err.Error(), "section: Leaves")
+
+	// opt out
+
+	st2 = &SlimTrie{encoder: encode.I32{}}
+	err = st2.UnmarshalWithOpt(bad, UnmarshalOpt{SkipChecksum: true, SkipValidate: true})
+	ta.NoError(err)
+	ta.Equal(st.inner.Inners.Words, st2.inner.Inners.Words)
+}
+
+func TestSlimTrie_Unmarshal_corruptedHeader(t *testing.T) {
+
+	ta := require.New(t)
+
+	st, _, _ := newChecksumTestTrie(t)
+	st.Metadata = map[string][]byte{"source": []byte("keys.csv")}
+
+	buf, err := st.Marshal()
+	ta.NoError(err)
+
+	cases := []struct {
+		field   string
+		section string
+	}{
+		{"keys.csv", "SlimHeader.Metadata"},
+		{"I32", "SlimHeader.Encoder"},
+	}
+
+	for _, c := range cases {
+		i := bytes.Index(buf, []byte(c.field))
+		ta.True(i > 0)
+
+		bad := append([]byte{}, buf...)
+		bad[i] ^= 0x01
+
+		st2 := &SlimTrie{encoder: encode.I32{}}
+		err := st2.Unmarshal(bad)
+		ta.Equal(ErrCorrupted, errors.Cause(err), "%s", c.field)
+		ta.Contains(err.Error(), "section: "+c.section)
+	}
+
+	// opt out
+
+	i := bytes.Index(buf, []byte("keys.csv"))
+	bad := append([]byte{}, buf...)
+	bad[i] ^= 0x01
+
+	st2 := &SlimTrie{encoder: encode.I32{}}
+	err = st2.UnmarshalWithOpt(bad, UnmarshalOpt{SkipChecksum: true})
+	ta.NoError(err)
+	ta.Equal("jeys.csv", string(st2.Metadata["source"]))
+}
+
+func TestSlimTrie_Unmarshal_noChecksum(t *testing.T) {
+
+	ta := require.New(t)
+
+	st, _, _ := newChecksumTestTrie(t)
+
+	// A header with checksums stripped
+
+	h := st.newHeader()
+	var b bytes.Buffer
+	_, err := pbcmpl.Marshal(&b, h)
+	ta.NoError(err)
+
+	bodySize, err := pbstream.Write(io.Discard, st.inner, nil)
+	ta.NoError(err)
+	_, err = writeFrameHeader(&b, st.inner.GetVersion(), bodySize)
+	ta.NoError(err)
+	_, err = pbstream.Write(&b, st.inner, nil)
+	ta.NoError(err)
+
+	st2 := &SlimTrie{encoder: encode.I32{}}
+	err = st2.Unmarshal(b.Bytes())
+	ta.Equal(ErrCorrupted, errors.Cause(err))
+	ta.Contains(err.Error(), "no checksum")
+
+	// opt out
+
+	st2 = &SlimTrie{encoder: encode.I32{}}
+	err = st2.UnmarshalWithOpt(b.Bytes(), UnmarshalOpt{SkipChecksum: true})
+	ta.NoError(err)
+	ta.True(st.Equal(st2))
+}
+
+func TestSlimTrie_Unmarshal_truncated(t *testing.T) {
+
+	ta := require.New(t)
+
+	_, buf, bodyOffset := newChecksumTestTrie(t)
+
+	st2 := &SlimTrie{encoder: encode.I32{}}
+	err := st2.Unmarshal(buf[:bodyOffset+10])
+	ta.Error(err)
+}
@@ -0,0 +1,145 @@
+package trie
+
+import (
+	"bytes"
+	"compress/flate"
+	"io"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/openacid/errors"
+)
+
+// MarshalCompact serializes it to a smaller byte stream than Marshal, for cold
+// storage or network transfer.
+//
+// RankIndex and SelectIndex of every bitmap are omitted since they can be
+// rebuilt from the bitmap, and Bytes of inner prefixes, leaf prefixes and
+// leaves are compressed with flate.
+//
+// The output is loaded by Unmarshal, which detects the compact format from the
+// header, decompresses Bytes and rebuilds indexes.
+// Thus loading it takes more time than loading the output of Marshal.
+//
+// Since 0.5.13
+func (st *SlimTrie) MarshalCompact() ([]byte, error) {
+
+	ns, err := st.inner.compact()
+	if err != nil {
+		return nil, err
+	}
+
+	h := st.newHeader()
+	h.Compact = true
+
+	writer := bytes.NewBuffer(nil)
+	_, err = st.writeTo(writer, ns, h)
+	if err != nil {
+		return nil, err
+	}
+
+	return writer.Bytes(), nil
+}
+
+// compact returns a copy of ns without indexes and with compressed Bytes.
+func (ns *Slim) compact() (*Slim, error) {
+
+	c := proto.Clone(ns).(*Slim)
+
+	for _, b := range c.bitmaps() {
+		b.RankIndex = nil
+		b.SelectIndex = nil
+	}
+
+	for _, va := range c.vlenArrays() {
+		if len(va.Bytes) == 0 {
+			continue
+		}
+
+		buf := bytes.NewBuffer(nil)
+		w, err := flate.NewWriter(buf, flate.BestCompression)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		_, err = w.Write(va.Bytes)
+		if err == nil {
+			err = w.Close()
+		}
+		if err != nil {
+			return nil, errors.WithMessage(errors.WithStack(err), "failed to compress")
+		}
+
+		va.Bytes = buf.Bytes()
+	}
+
+	return c, nil
+}
+
+// uncompact reverts compact: it decompresses Bytes and rebuilds indexes.
+func (ns *Slim) uncompact() error {
+
+	for _, va := range ns.vlenArrays() {
+		if len(va.Bytes) == 0 {
+			continue
+		}
+
+		// The size of Bytes is bounded by the positions or the number of
+		// fixed-size elements, to defend against a compression bomb.
+		var max int64
+		if va.PositionBM != nil {
+			max = int64(len(va.PositionBM.Words)) * 64
+		} else {
+			max = int64(va.FixedSize) * int64(va.EltCnt)
+		}
+
+		r := flate.NewReader(bytes.NewReader(va.Bytes))
+		b, err := io.ReadAll(io.LimitReader(r, max+1))
+		if err != nil {
+			return errors.Wrapf(ErrMalformed, "failed to decompress: %v", err)
+		}
+		if int64(len(b)) > max {
+			return errors.Wrapf(ErrMalformed, "decompressed size exceeds: %d", max)
+		}
+
+		va.Bytes = b
+	}
+
+	ns.indexit()
+	return nil
+}
+
+// bitmaps returns all of the non-nil bitmaps in ns.
+func (ns *Slim) bitmaps() []*Bitmap {
+
+	var bms []*Bitmap
+
+	for _, b := range []*Bitmap{ns.NodeTypeBM, ns.Inners, ns.ShortBM} {
+		if b != nil {
+			bms = append(bms, b)
+		}
+	}
+
+	for _, va := range ns.vlenArrays() {
+		for _, b := range []*Bitmap{va.PresenceBM, va.PositionBM} {
+			if b != nil {
+				bms = append(bms, b)
+			}
+		}
+	}
+
+	return bms
+}
+
+// vlenArrays returns all of the non-nil VLenArray in ns.
+func (ns *Slim) vlenArrays() []*VLenArray {
+
+	var vas []*VLenArray
+
+	for _, va := range []*VLenArray{ns.InnerPrefixes, ns.LeafPrefixes, ns.Leaves} {
+		if va != nil {
+			vas = append(vas, va)
+		}
+	}
+
+	return vas
+}
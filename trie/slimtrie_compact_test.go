@@ -0,0 +1,115 @@
+package trie
+
+import (
+	"bytes"
+	"compress/flate"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/openacid/errors"
+	"github.com/openacid/slim/encode"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlimTrie_MarshalCompact(t *testing.T) {
+
+	ta := require.New(t)
+
+	keySets := map[string][]string{"50kl10": getKeys("50kl10")[:5000]}
+	for name, c := range iterCases {
+		keySets[name] = c.keys
+	}
+
+	for typ, keys := range keySets {
+
+		values := makeI32s(len(keys))
+
+		for _, opt := range []Opt{{}, {Complete: Bool(true)}, {InnerPrefix: Bool(true)}} {
+
+			st, err := NewSlimTrie(encode.I32{}, keys, values, opt)
+			ta.NoError(err, "%s", typ)
+
+			full, err := st.Marshal()
+			ta.NoError(err, "%s", typ)
+
+			buf, err := st.MarshalCompact()
+			ta.NoError(err, "%s", typ)
+
+			if len(keys) > 100 {
+				ta.Less(len(buf), len(full)*3/4, "%s %+v", typ, opt)
+			}
+
+			got := &SlimTrie{}
+			err = got.Unmarshal(buf)
+			ta.NoError(err, "%s", typ)
+
+			ta.True(proto.Equal(st.inner, got.inner), "%s: indexes should be rebuilt", typ)
+			testPresentKeysGRS(t, got, keys, values)
+
+			got = &SlimTrie{}
+			n, err := got.ReadFrom(bytes.NewReader(buf))
+			ta.NoError(err, "%s", typ)
+			ta.Equal(int64(len(buf)), n)
+			ta.True(proto.Equal(st.inner, got.inner), "%s", typ)
+		}
+	}
+}
+
+func TestSlimTrie_MarshalCompact_varlen(t *testing.T) {
+
+	ta := require.New(t)
+
+	keys := getKeys("50kl10")[:1000]
+	values := make([]string, len(keys))
+	for i := range keys {
+		values[i] = keys[i] + "-value"
+	}
+
+	st, err := NewSlimTrie(encode.String16{}, keys, values, Opt{Complete: Bool(true)})
+	ta.NoError(err)
+
+	buf, err := st.MarshalCompact()
+	ta.NoError(err)
+
+	got, err := Load(buf)
+	ta.NoError(err)
+
+	for i, k := range keys {
+		v, found := got.Get(k)
+		ta.True(found)
+		ta.Equal(values[i], v)
+	}
+}
+
+func TestSlimTrie_MarshalCompact_malformed(t *testing.T) {
+
+	ta := require.New(t)
+
+	st, err := NewSlimTrie(encode.I32{}, []string{"a", "b", "c"}, []int32{1, 2, 3})
+	ta.NoError(err)
+
+	ns, err := st.inner.compact()
+	ta.NoError(err)
+
+	// decompressed Bytes larger than the number of values
+
+	b := bytes.NewBuffer(nil)
+	w, err := flate.NewWriter(b, flate.BestCompression)
+	ta.NoError(err)
+	_, err = w.Write(make([]byte, 1<<20))
+	ta.NoError(err)
+	ta.NoError(w.Close())
+
+	bomb := proto.Clone(ns).(*Slim)
+	bomb.Leaves.Bytes = b.Bytes()
+	ta.Equal(ErrMalformed, errors.Cause(bomb.uncompact()))
+
+	// not flate
+
+	bad := proto.Clone(ns).(*Slim)
+	bad.Leaves.Bytes = []byte("foo")
+	ta.Equal(ErrMalformed, errors.Cause(bad.uncompact()))
+
+	ta.NoError(ns.uncompact())
+	ta.True(proto.Equal(st.inner, ns))
+}
@@ -0,0 +1,58 @@
+package trie
+
+import (
+	"compress/flate"
+	"fmt"
+	"testing"
+
+	"github.com/openacid/slim/encode"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlimTrie_compressedValue(t *testing.T) {
+
+	ta := require.New(t)
+
+	keys := getKeys("20kvl10")[:1000]
+	values := make([][]byte, len(keys))
+	for i := range values {
+		values[i] = []byte(fmt.Sprintf(`{"key":%q,"id":%d,"tags":["a","b","c"]}`, keys[i], i))
+	}
+
+	dict := encode.TrainDict(values[:100], 4096)
+
+	cases := []encode.Encoder{
+		encode.Compressed(nil, flate.BestCompression),
+		encode.CompressedDict(nil, flate.BestCompression, dict),
+	}
+
+	for _, e := range cases {
+
+		st, err := NewSlimTrie(e, keys, values, Opt{Complete: Bool(true)})
+		ta.NoError(err)
+
+		buf, err := st.Marshal()
+		ta.NoError(err)
+
+		st2, err := NewSlimTrie(e, nil, nil)
+		ta.NoError(err)
+		ta.NoError(st2.Unmarshal(buf))
+
+		for _, s := range []*SlimTrie{st, st2} {
+			for i, key := range keys {
+				v, found := s.Get(key)
+				ta.True(found, "Get:%v", key)
+				ta.Equal(values[i], v, "Get:%v", key)
+			}
+
+			i := 0
+			s.ScanFrom("", true, true, func(k, v []byte) bool {
+				_, x := e.Decode(v)
+				ta.Equal(values[i], x, "Scan:%s", k)
+				i++
+				return true
+			})
+			ta.Equal(len(keys), i)
+		}
+	}
+}
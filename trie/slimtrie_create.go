@@ -2,6 +2,7 @@ package trie
 
 import (
 	"bytes"
+	"math"
 	"math/bits"
 	"reflect"
 	"sort"
@@ -22,6 +23,10 @@ type subset struct {
 	keyEnd     int32
 	fromKeyBit int32
 	level      int32
+
+	// fpr is what an absent key has matched to reach this node, it is used
+	// only if creator.withFPR is true.
+	fpr fprLeaf
 }
 
 type creator struct {
@@ -43,6 +48,10 @@ type creator struct {
 
 	withLeaves bool
 
+	// withFPR specifies whether to collect fprLeaves for estimating the false
+	// positive rate.
+	withFPR bool
+
 	// options
 
 	option *Opt
@@ -73,6 +82,9 @@ type creator struct {
 	leafPrefixes      []byte
 	prefs             map[string]int32
 
+	// fprLeaves is the same as SlimTrie.fprLeaves() returns, in node id order.
+	fprLeaves []fprLeaf
+
 	// stats those affect creating
 
 	innerBMCnt []map[uint64]int32
@@ -464,6 +476,27 @@ func newSlim(keys []string, bytesValues [][]byte, opt *Opt) (*Slim, error) {
 		return &Slim{}, nil
 	}
 
+	tokeep := newToKeep(n, bytesValues, opt)
+
+	c, err := scanKeys(keys, tokeep, bytesValues != nil, false, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	slim := c.build()
+	slim.Leaves = c.buildLeaves(bytesValues)
+
+	return slim, nil
+}
+
+// scanKeys splits keys into nodes and records them in a creator, without
+// building the bitmaps.
+// If withFPR is true, it also collects creator.fprLeaves.
+// keys must not be empty.
+func scanKeys(keys []string, tokeep []bool, withLeaves, withFPR bool, opt *Opt) (*creator, error) {
+
+	n := len(keys)
+
 	for i := 0; i < n-1; i++ {
 		if keys[i] >= keys[i+1] {
 			return nil, errors.Wrapf(ErrKeyOutOfOrder,
@@ -471,13 +504,12 @@ func newSlim(keys []string, bytesValues [][]byte, opt *Opt) (*Slim, error) {
 		}
 	}
 
-	tokeep := newToKeep(n, bytesValues, opt)
-
 	sb := sigbits.New(keys)
-	c := newCreator(n, bytesValues != nil, opt)
+	c := newCreator(n, withLeaves, opt)
+	c.withFPR = withFPR
 
 	queue := make([]subset, 0, n*2)
-	queue = append(queue, subset{0, int32(n), 0, 1})
+	queue = append(queue, subset{0, int32(n), 0, 1, fprLeaf{p: 1, allCompared: true}})
 
 	for i := 0; i < len(queue); i++ {
 		nid := int32(i)
@@ -489,6 +521,9 @@ func newSlim(keys []string, bytesValues [][]byte, opt *Opt) (*Slim, error) {
 			must.Be.True(tokeep[s])
 			c.addLeafIndex(nid, s)
 			c.setLeafPrefix(nid, keys[s], o.fromKeyBit)
+			if c.withFPR {
+				c.addFPRLeaf(o, keys[s])
+			}
 			continue
 		}
 
@@ -587,15 +622,70 @@ func newSlim(keys []string, bytesValues [][]byte, opt *Opt) (*Slim, error) {
 
 				level: o.level + 1,
 			}
+			if c.withFPR {
+				p.fpr = c.childFPR(o, wordStart, pth)
+			}
 			queue = append(queue, p)
 			s = j
 		}
 	}
 
-	slim := c.build()
-	slim.Leaves = c.buildLeaves(bytesValues)
+	return c, nil
+}
 
-	return slim, nil
+// childFPR returns what an absent key has to match to reach a child of the
+// inner node of subset o, through the label pth.
+// It is the same as SlimTrie.fprLeaves() does when walking through a
+// SlimTrie.
+func (c *creator) childFPR(o subset, wordStart int32, pth uint64) fprLeaf {
+
+	lf := o.fpr
+
+	if *c.option.InnerPrefix {
+		lf.p *= math.Exp2(-float64(wordStart - o.fromKeyBit))
+	} else if wordStart > o.fromKeyBit {
+		lf.allCompared = false
+	}
+
+	w := bmtree.PathLen(pth)
+	if w == 0 {
+		// the terminal branch
+		lf.exactLen = true
+		lf.keyLen = wordStart >> 3
+		if wordStart&7 != 0 {
+			lf.p = 0
+		}
+	} else {
+		lf.p *= math.Exp2(-float64(w))
+	}
+
+	return lf
+}
+
+// addFPRLeaf adds the fprLeaf of the leaf of subset o, which has only key.
+func (c *creator) addFPRLeaf(o subset, key string) {
+
+	lf := o.fpr
+	i := o.fromKeyBit
+
+	if !lf.exactLen {
+		if !*c.option.LeafPrefix {
+			lf.keyLen = (i + 7) >> 3
+			lf.allCompared = false
+		} else {
+			lf.exactLen = true
+			lf.keyLen = i >> 3
+
+			if n := int32(len(key)) - i>>3; n > 0 {
+				lf.keyLen += n
+				lf.p *= math.Exp2(-float64(8*n - i&7))
+			} else if i&7 != 0 {
+				lf.p = 0
+			}
+		}
+	}
+
+	c.fprLeaves = append(c.fprLeaves, lf)
 }
 
 func encodeValues(n int, values interface{}, e encode.Encoder) [][]byte {
@@ -606,10 +696,15 @@ func encodeValues(n int, values interface{}, e encode.Encoder) [][]byte {
 	vals := make([][]byte, 0, n)
 	rvals := reflect.ValueOf(values)
 
+	// Encode all values into one buffer to reduce allocation.
+	// A value slice has its cap limited so that appending to buf never
+	// overrides it.
+	var buf []byte
 	for i := 0; i < n; i++ {
 		v := getV(rvals, int32(i))
-		bs := e.Encode(v)
-		vals = append(vals, bs)
+		start := len(buf)
+		buf = encode.AppendEncode(e, buf, v)
+		vals = append(vals, buf[start:len(buf):len(buf)])
 	}
 	return vals
 }
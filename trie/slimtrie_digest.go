@@ -0,0 +1,218 @@
+package trie
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"hash"
+)
+
+// digestPrefix is hashed before the content, to identify the canonical form.
+// It must be changed if the canonical form changes.
+const digestPrefix = "slimtrie-digest-1"
+
+// Digest returns the SHA-256 of a canonical serialization of the content of
+// the SlimTrie, i.e., the trie structure, prefixes and leaf values, and the
+// Opt it is created with.
+//
+// The canonical form does not depend on protobuf encoding or on any map
+// iteration order, and it excludes derivable indexes.
+// Thus two SlimTries with the same content have the same digest, no matter it
+// is built, or loaded by Unmarshal or from the output of MarshalCompact.
+//
+// Two SlimTries have the same digest if and only if Equal returns true,
+// barring hash collisions.
+// Metadata and the encoder are not part of the digest.
+//
+// Leaf values are hashed in encoded form, thus the value encoder must be
+// deterministic, i.e., encode equal values to the same bytes.
+// All built-in encoders are, except GobEncoder for values containing maps.
+//
+// Since 0.5.13
+func (st *SlimTrie) Digest() [32]byte {
+
+	h := sha256.New()
+	h.Write([]byte(digestPrefix))
+
+	for _, v := range st.canonical() {
+		writeCanonical(h, v)
+	}
+
+	var d [32]byte
+	copy(d[:], h.Sum(nil))
+	return d
+}
+
+// Equal returns true if two SlimTries have the same content and are created
+// with the same Opt.
+// It compares content directly without marshaling.
+// It compares the same things as Digest does, thus two equal SlimTries have
+// the same digest.
+//
+// A SlimTrie loaded from data of a version before 0.5.13 has a zero Opt, see
+// SlimTrie.Opt.
+// Metadata and the encoder are not compared.
+//
+// Since 0.5.13
+func (st *SlimTrie) Equal(other *SlimTrie) bool {
+
+	if st == other {
+		return true
+	}
+	if st == nil || other == nil {
+		return false
+	}
+
+	a := st.canonical()
+	b := other.canonical()
+
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if !canonicalEqual(a[i], b[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// canonical returns the content of st followed by its Opt, in a fixed order.
+func (st *SlimTrie) canonical() []interface{} {
+
+	c := st.inner.canonical()
+
+	o := st.Opt()
+	for _, v := range []*bool{o.DedupValue, o.InnerPrefix, o.LeafPrefix, o.Complete} {
+		c = append(c, v != nil)
+		if v != nil {
+			c = append(c, *v)
+		}
+	}
+
+	return c
+}
+
+// canonical returns the content of ns in a fixed order.
+// Every element is one of bool, int32, []uint32, []uint64 or []byte.
+// A bool tells whether an optional component is present, which is followed by
+// its content only if it is present.
+func (ns *Slim) canonical() []interface{} {
+
+	var c []interface{}
+
+	addBitmap := func(b *Bitmap) {
+		c = append(c, b != nil)
+		if b != nil {
+			c = append(c, b.Words)
+		}
+	}
+
+	addVLen := func(va *VLenArray) {
+		c = append(c, va != nil)
+		if va != nil {
+			c = append(c, va.N, va.EltCnt, va.FixedSize)
+			addBitmap(va.PresenceBM)
+			addBitmap(va.PositionBM)
+			c = append(c, va.Bytes)
+		}
+	}
+
+	c = append(c, ns != nil)
+	if ns == nil {
+		return c
+	}
+
+	c = append(c, ns.BigInnerCnt, ns.ShortSize)
+	addBitmap(ns.NodeTypeBM)
+	addBitmap(ns.Inners)
+	addBitmap(ns.ShortBM)
+	c = append(c, ns.ShortTable)
+	addVLen(ns.InnerPrefixes)
+	addVLen(ns.LeafPrefixes)
+	addVLen(ns.Leaves)
+
+	return c
+}
+
+// writeCanonical writes an element returned by canonical in little endian.
+// A slice is prefixed with its length.
+func writeCanonical(h hash.Hash, v interface{}) {
+
+	var b [8]byte
+
+	switch v := v.(type) {
+	case bool:
+		b[0] = 0
+		if v {
+			b[0] = 1
+		}
+		h.Write(b[:1])
+	case int32:
+		binary.LittleEndian.PutUint32(b[:], uint32(v))
+		h.Write(b[:4])
+	case []uint32:
+		binary.LittleEndian.PutUint64(b[:], uint64(len(v)))
+		h.Write(b[:])
+		for _, x := range v {
+			binary.LittleEndian.PutUint32(b[:], x)
+			h.Write(b[:4])
+		}
+	case []uint64:
+		binary.LittleEndian.PutUint64(b[:], uint64(len(v)))
+		h.Write(b[:])
+		for _, x := range v {
+			binary.LittleEndian.PutUint64(b[:], x)
+			h.Write(b[:])
+		}
+	case []byte:
+		binary.LittleEndian.PutUint64(b[:], uint64(len(v)))
+		h.Write(b[:])
+		h.Write(v)
+	default:
+		panic("unknown canonical type")
+	}
+}
+
+// canonicalEqual compares two elements returned by canonical.
+// A nil slice equals an empty one, as they are serialized the same.
+func canonicalEqual(a, b interface{}) bool {
+
+	switch a := a.(type) {
+	case bool:
+		b, ok := b.(bool)
+		return ok && a == b
+	case int32:
+		b, ok := b.(int32)
+		return ok && a == b
+	case []uint32:
+		b, ok := b.([]uint32)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if a[i] != b[i] {
+				return false
+			}
+		}
+		return true
+	case []uint64:
+		b, ok := b.([]uint64)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if a[i] != b[i] {
+				return false
+			}
+		}
+		return true
+	case []byte:
+		b, ok := b.([]byte)
+		return ok && bytes.Equal(a, b)
+	default:
+		panic("unknown canonical type")
+	}
+}
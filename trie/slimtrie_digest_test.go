@@ -0,0 +1,167 @@
+package trie
+
+import (
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/openacid/slim/encode"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlimTrie_Marshal_deterministic(t *testing.T) {
+
+	ta := require.New(t)
+
+	keySets := map[string][]string{"50kl10": getKeys("50kl10")[:5000]}
+	for name, c := range iterCases {
+		keySets[name] = c.keys
+	}
+
+	for typ, keys := range keySets {
+
+		values := makeI32s(len(keys))
+
+		for _, opt := range []Opt{{}, {Complete: Bool(true)}, {InnerPrefix: Bool(true)}, {DedupValue: Bool(false)}} {
+
+			st, err := NewSlimTrie(encode.I32{}, keys, values, opt)
+			ta.NoError(err, "%s", typ)
+
+			want, err := st.Marshal()
+			ta.NoError(err, "%s", typ)
+
+			// build again with copies of input
+
+			keys2 := append([]string{}, keys...)
+			values2 := append([]int32{}, values...)
+			st2, err := NewSlimTrie(encode.I32{}, keys2, values2, opt)
+			ta.NoError(err, "%s", typ)
+
+			got, err := st2.Marshal()
+			ta.NoError(err, "%s", typ)
+			ta.Equal(want, got, "%s: building twice should output the same bytes", typ)
+
+			// marshal a loaded one
+
+			loaded := &SlimTrie{}
+			ta.NoError(loaded.Unmarshal(want))
+
+			got, err = loaded.Marshal()
+			ta.NoError(err, "%s", typ)
+			ta.Equal(want, got, "%s: marshaling a loaded one should output the same bytes", typ)
+
+			// digest and equality
+
+			compact, err := st.MarshalCompact()
+			ta.NoError(err, "%s", typ)
+			fromCompact := &SlimTrie{}
+			ta.NoError(fromCompact.Unmarshal(compact))
+
+			for _, other := range []*SlimTrie{st2, loaded, fromCompact} {
+				ta.Equal(st.Digest(), other.Digest(), "%s", typ)
+				ta.True(st.Equal(other), "%s", typ)
+				ta.True(other.Equal(st), "%s", typ)
+			}
+		}
+	}
+}
+
+func TestSlimTrie_Marshal_deterministicMapValue(t *testing.T) {
+
+	ta := require.New(t)
+
+	keys := getKeys("50kl10")[:1000]
+
+	build := func() *SlimTrie {
+		values := make([]map[string]int, len(keys))
+		for i := range values {
+			values[i] = map[string]int{}
+			for j := 0; j < 10; j++ {
+				values[i][fmt.Sprintf("f%d", j)] = i + j
+			}
+		}
+
+		st, err := NewSlimTrie(encode.JSON(reflect.TypeOf(map[string]int{})), keys, values)
+		ta.NoError(err)
+		return st
+	}
+
+	st, st2 := build(), build()
+
+	want, err := st.Marshal()
+	ta.NoError(err)
+	got, err := st2.Marshal()
+	ta.NoError(err)
+
+	ta.Equal(want, got, "building twice should output the same bytes")
+	ta.Equal(st.Digest(), st2.Digest())
+}
+
+func TestSlimTrie_Digest(t *testing.T) {
+
+	ta := require.New(t)
+
+	keys := []string{"abc", "abcd", "abd", "abde", "bc", "bcd", "bcde", "cde"}
+	values := makeI32s(len(keys))
+
+	st, err := NewSlimTrie(encode.I32{}, keys, values, Opt{Complete: Bool(true)})
+	ta.NoError(err)
+
+	// The canonical form must not change, otherwise content-addressed data
+	// built by an older version can not be found.
+	d := st.Digest()
+	ta.Equal("a35dd59ee5dbea4b6ef4ead4a6094ac76b4054c25c53f3dab681a999f8bea3ce", hex.EncodeToString(d[:]))
+
+	// metadata is not included
+
+	st.Metadata = map[string][]byte{"foo": []byte("bar")}
+	ta.Equal(d, st.Digest())
+
+	// different values
+
+	values2 := makeI32s(len(keys))
+	values2[3] = 100
+	st2, err := NewSlimTrie(encode.I32{}, keys, values2, Opt{Complete: Bool(true)})
+	ta.NoError(err)
+	ta.NotEqual(d, st2.Digest())
+	ta.False(st.Equal(st2))
+
+	// different keys
+
+	st3, err := NewSlimTrie(encode.I32{}, keys[1:], values[1:], Opt{Complete: Bool(true)})
+	ta.NoError(err)
+	ta.NotEqual(d, st3.Digest())
+	ta.False(st.Equal(st3))
+
+	// Opt is included in both Digest and Equal, although no value is
+	// deduplicated and the content is the same.
+
+	st4, err := NewSlimTrie(encode.I32{}, keys, values, Opt{Complete: Bool(true), DedupValue: Bool(false)})
+	ta.NoError(err)
+	ta.Equal(st.inner.canonical(), st4.inner.canonical())
+	ta.NotEqual(d, st4.Digest())
+	ta.False(st.Equal(st4))
+
+	// A SlimTrie loaded from an older version has a zero Opt.
+
+	b, err := st.MarshalVersion("0.5.12")
+	ta.NoError(err)
+	st5, err := NewSlimTrie(encode.I32{}, nil, nil)
+	ta.NoError(err)
+	ta.NoError(st5.Unmarshal(b))
+	ta.Equal(Opt{}, st5.Opt())
+	ta.NotEqual(d, st5.Digest())
+	ta.False(st.Equal(st5))
+
+	// empty
+
+	e1, err := NewSlimTrie(encode.I32{}, nil, nil)
+	ta.NoError(err)
+	e2, err := NewSlimTrie(encode.I32{}, []string{}, []int32{})
+	ta.NoError(err)
+	ta.Equal(e1.Digest(), e2.Digest())
+	ta.True(e1.Equal(e2))
+	ta.False(e1.Equal(st))
+	ta.False(e1.Equal(nil))
+}
@@ -0,0 +1,226 @@
+package trie
+
+import (
+	"github.com/openacid/errors"
+	"github.com/openacid/slim/encode"
+)
+
+// Budget limits the size and the false positive rate of a SlimTrie, for
+// ChooseOpt.
+//
+// Since 0.5.13
+type Budget struct {
+
+	// MaxBytes is the max size in byte, the same as StatDetail.TotalBytes.
+	// 0 means unlimited.
+	MaxBytes int64
+
+	// MaxFPR is the max expected false positive rate, see
+	// SlimTrie.ExpectedFPR.
+	// 0 means no false positive is allowed.
+	MaxFPR float64
+}
+
+// EstimateSize estimates the size in byte of a SlimTrie created from keys with
+// opt, and with values of valueSize bytes each, without building it.
+// The size is the same as StatDetail.TotalBytes, which does not include the
+// overhead of Go structs.
+//
+// It assumes no two adjacent keys have the same value, i.e., no key is removed
+// by Opt.DedupValue.
+//
+// Since 0.5.13
+func EstimateSize(keys []string, valueSize int, opt Opt) (int64, error) {
+
+	if len(keys) == 0 {
+		return 0, nil
+	}
+
+	opt.DedupValue = Bool(false)
+	normalizeOpt(&opt)
+
+	tokeep := newToKeep(len(keys), nil, &opt)
+
+	c, err := scanKeys(keys, tokeep, true, false, &opt)
+	if err != nil {
+		return 0, err
+	}
+
+	return c.size(func(int32) int32 { return int32(valueSize) }), nil
+}
+
+// ChooseOpt chooses the Opt with the lowest ExpectedFPR whose size fits in
+// b.MaxBytes.
+// Ties are broken by size.
+//
+// It tries every combination of Opt.InnerPrefix and Opt.LeafPrefix, i.e.,
+// Opt{}, Opt{InnerPrefix}, Opt{LeafPrefix} and Opt{Complete}, which is the same
+// as Opt{InnerPrefix, LeafPrefix}.
+// Opt.DedupValue is always the default true, which never makes a SlimTrie
+// larger.
+// Sizes and ExpectedFPR are estimated without building a SlimTrie.
+//
+// The arguments e, keys and values are the same as NewSlimTrie.
+// The returned Opt is normalized, with every field set.
+//
+// It returns an error of ErrOverBudget if no option fits b.
+//
+// Since 0.5.13
+func ChooseOpt(e encode.Encoder, keys []string, values interface{}, b Budget) (Opt, error) {
+
+	vals := encodeValues(len(keys), values, e)
+
+	var best *Opt
+	bestFPR, bestSize := float64(0), int64(0)
+	minSize := int64(-1)
+
+	for _, inner := range []bool{true, false} {
+		for _, leaf := range []bool{true, false} {
+
+			opt := &Opt{
+				InnerPrefix: Bool(inner),
+				LeafPrefix:  Bool(leaf),
+			}
+			if inner && leaf {
+				opt.Complete = Bool(true)
+			}
+			normalizeOpt(opt)
+
+			sz, fpr, err := estimate(keys, vals, opt)
+			if err != nil {
+				return Opt{}, err
+			}
+
+			if minSize == -1 || sz < minSize {
+				minSize = sz
+			}
+
+			if b.MaxBytes > 0 && sz > b.MaxBytes {
+				continue
+			}
+
+			if best == nil || fpr < bestFPR || (fpr == bestFPR && sz < bestSize) {
+				best = opt
+				bestFPR, bestSize = fpr, sz
+			}
+		}
+	}
+
+	if best == nil {
+		return Opt{}, errors.Wrapf(ErrOverBudget, "MaxBytes: %d, the smallest: %d", b.MaxBytes, minSize)
+	}
+
+	if bestFPR > b.MaxFPR {
+		return Opt{}, errors.Wrapf(ErrOverBudget, "MaxFPR: %g, the lowest within MaxBytes: %g", b.MaxFPR, bestFPR)
+	}
+
+	return *best, nil
+}
+
+// estimate returns the size and the ExpectedFPR of a SlimTrie created from
+// keys and encoded values with a normalized opt.
+func estimate(keys []string, vals [][]byte, opt *Opt) (int64, float64, error) {
+
+	if len(keys) == 0 {
+		return 0, 0, nil
+	}
+
+	tokeep := newToKeep(len(keys), vals, opt)
+
+	complete := *opt.InnerPrefix && *opt.LeafPrefix
+
+	c, err := scanKeys(keys, tokeep, vals != nil, !complete, opt)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	sz := c.size(func(i int32) int32 { return int32(len(vals[i])) })
+	return sz, fprOf(c.fprLeaves), nil
+}
+
+// size calculates the size in byte of the Slim that c.build() and
+// c.buildLeaves() create, the same as StatDetail.TotalBytes.
+// valueSize returns the size of the encoded value of the i-th key.
+func (c *creator) size(valueSize func(i int32) int32) int64 {
+
+	innerCnt := int32(len(c.innerIndexes))
+
+	sorted := sortedBMCounts(c.innerBMCnt)
+	shortSize, shortCnt := findMinShortSize(sorted)
+
+	normalCnt := innerCnt - c.bigCnt - shortCnt
+	innerBits := c.bigCnt*bigInnerSize + normalCnt*innerSize + shortCnt*shortSize
+
+	sz := int64(0)
+
+	sz += bmSize(c.nodeCnt, 0, "r64")
+	sz += bmSize(innerBits, 0, "r128")
+	sz += bmSize(innerCnt, 0, "r64")
+	sz += int64(4) << uint(shortSize)
+
+	sz += bmSize(innerCnt, 0, "r128")
+	if *c.option.InnerPrefix {
+		sz += int64(len(c.prefixes))
+		sz += bmSize(int32(len(c.prefixes))+1, int32(len(c.prefixByteLens))+1, "s32")
+	} else {
+		sz += int64(len(c.prefix4BitLens))
+	}
+
+	if *c.option.LeafPrefix {
+		sz += int64(len(c.leafPrefixes))
+		// Without values, the presence bitmap ends at the last leaf prefix.
+		nbits := c.leafCnt
+		if n := len(c.leafPrefixIndexes); n > 0 && c.leafPrefixIndexes[n-1] >= nbits {
+			nbits = c.leafPrefixIndexes[n-1] + 1
+		}
+		sz += bmSize(nbits, 0, "r64")
+		sz += bmSize(int32(len(c.leafPrefixes))+1, int32(len(c.leafPrefixLens))+1, "s32")
+	}
+
+	// Leaves, see newVLenArray
+	total, nonEmpty := int32(0), int32(0)
+	allEqual := true
+	prevSize := int32(-1)
+	for _, idx := range c.leafIndexes {
+		vs := valueSize(idx)
+		total += vs
+		if vs > 0 {
+			nonEmpty++
+			if prevSize != -1 && prevSize != vs {
+				allEqual = false
+			}
+			prevSize = vs
+		}
+	}
+
+	if total > 0 {
+		sz += int64(total)
+		sz += bmSize(int32(len(c.leafIndexes)), 0, "r64")
+		if !allEqual {
+			sz += bmSize(total+1, nonEmpty+1, "s32")
+		}
+	}
+
+	return sz
+}
+
+// bmSize returns the size in byte of the words and the index of a bitmap of
+// nbits bits, with ones "1", the same as newBM creates.
+func bmSize(nbits, ones int32, index string) int64 {
+
+	w := int64(nbits+63) >> 6
+
+	idx := int64(0)
+	switch index {
+	case "r64":
+		idx = w
+	case "r128":
+		idx = w/2 + 1
+	case "s32":
+		idx = int64(ones+31)/32 + w + 1
+	default:
+		panic("unknown " + index)
+	}
+
+	return w*8 + idx*4
+}
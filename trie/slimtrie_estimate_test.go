@@ -0,0 +1,169 @@
+package trie
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/openacid/errors"
+	"github.com/openacid/slim/benchhelper"
+	"github.com/openacid/slim/encode"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateSize(t *testing.T) {
+
+	ta := require.New(t)
+
+	opts := []Opt{
+		{},
+		{InnerPrefix: Bool(true)},
+		{LeafPrefix: Bool(true)},
+		{Complete: Bool(true)},
+	}
+
+	for _, n := range []int{1, 2, 100, 5000} {
+
+		keys := benchhelper.RandSortedStrings(n, 12, nil)
+		values := makeI32s(len(keys))
+
+		for _, opt := range opts {
+
+			st, err := NewSlimTrie(encode.I32{}, keys, values, opt)
+			ta.NoError(err)
+
+			sz, err := EstimateSize(keys, 4, opt)
+			ta.NoError(err)
+			ta.Equal(st.StatDetail().TotalBytes, sz, "n: %d, opt: %+v", n, opt)
+		}
+	}
+
+	sz, err := EstimateSize(nil, 4, Opt{})
+	ta.NoError(err)
+	ta.Equal(int64(0), sz)
+
+	_, err = EstimateSize([]string{"b", "a"}, 4, Opt{})
+	ta.Equal(ErrKeyOutOfOrder, errors.Cause(err))
+}
+
+func TestEstimateSize_varLenValues(t *testing.T) {
+
+	ta := require.New(t)
+
+	keys := benchhelper.RandSortedStrings(3000, 10, nil)
+	values := make([]string, len(keys))
+	for i := range values {
+		// adjacent duplicates are removed with DedupValue
+		values[i] = keys[i/3][:i%5]
+	}
+
+	for _, opt := range []Opt{{}, {DedupValue: Bool(false)}, {Complete: Bool(true)}} {
+
+		st, err := NewSlimTrie(encode.String16{}, keys, values, opt)
+		ta.NoError(err)
+
+		normalizeOpt(&opt)
+		sz, fpr, err := estimate(keys, encodeValues(len(keys), values, encode.String16{}), &opt)
+		ta.NoError(err)
+		ta.Equal(st.StatDetail().TotalBytes, sz, "opt: %+v", opt)
+		ta.InDelta(st.ExpectedFPR(), fpr, 1e-12, "opt: %+v", opt)
+	}
+}
+
+func TestEstimate_fpr(t *testing.T) {
+
+	ta := require.New(t)
+
+	opts := []Opt{
+		{},
+		{InnerPrefix: Bool(true)},
+		{LeafPrefix: Bool(true)},
+		{Complete: Bool(true)},
+	}
+
+	keySets := [][]string{
+		statCases["singleKey"].keys,
+		statCases["simple"].keys,
+		benchhelper.RandSortedStrings(2, 12, nil),
+		benchhelper.RandSortedStrings(5000, 12, nil),
+		benchhelper.RandSortedStrings(1000, 12, []byte("ab")),
+		benchhelper.RandSortedStrings(5000, 12, []byte("abcdefghijklmnopqrstuvwxyz")),
+	}
+
+	// keys of different lengths, some is a prefix of another.
+	var prefixed []string
+	for _, k := range benchhelper.RandSortedStrings(1000, 8, []byte("abc")) {
+		prefixed = append(prefixed, k[:2], k[:5], k)
+	}
+	sort.Strings(prefixed)
+	uniq := prefixed[:1]
+	for _, k := range prefixed[1:] {
+		if k != uniq[len(uniq)-1] {
+			uniq = append(uniq, k)
+		}
+	}
+	keySets = append(keySets, uniq)
+
+	for i, keys := range keySets {
+
+		values := makeI32s(len(keys))
+		vals := encodeValues(len(keys), values, encode.I32{})
+
+		for _, opt := range opts {
+
+			st, err := NewSlimTrie(encode.I32{}, keys, values, opt)
+			ta.NoError(err)
+
+			normalizeOpt(&opt)
+			_, fpr, err := estimate(keys, vals, &opt)
+			ta.NoError(err)
+			ta.InDelta(st.ExpectedFPR(), fpr, 1e-12, "%d-th keys, opt: %+v", i, opt)
+		}
+	}
+}
+
+func TestChooseOpt(t *testing.T) {
+
+	ta := require.New(t)
+
+	c := statCases["simple"]
+	values := makeI32s(len(c.keys))
+
+	// Size and ExpectedFPR:
+	//   Complete:    174 0
+	//   LeafPrefix:  153 0.0137
+	//   InnerPrefix: 137 0.0039
+	//   default:     116 0.0751
+	norm := func(o Opt) Opt {
+		normalizeOpt(&o)
+		return o
+	}
+
+	cases := []struct {
+		budget Budget
+		want   Opt
+	}{
+		{Budget{}, norm(Opt{Complete: Bool(true)})},
+		{Budget{MaxBytes: 174}, norm(Opt{Complete: Bool(true)})},
+		{Budget{MaxBytes: 173, MaxFPR: 1}, norm(Opt{InnerPrefix: Bool(true)})},
+		{Budget{MaxBytes: 136, MaxFPR: 1}, norm(Opt{})},
+		{Budget{MaxBytes: 136, MaxFPR: 0.08}, norm(Opt{})},
+	}
+
+	for i, c2 := range cases {
+		opt, err := ChooseOpt(encode.I32{}, c.keys, values, c2.budget)
+		ta.NoError(err, "%d-th: %+v", i+1, c2.budget)
+		ta.Equal(c2.want, opt, "%d-th: %+v", i+1, c2.budget)
+	}
+
+	for _, b := range []Budget{
+		{MaxBytes: 115, MaxFPR: 1},
+		{MaxBytes: 173},
+		{MaxBytes: 136, MaxFPR: 0.07},
+	} {
+		_, err := ChooseOpt(encode.I32{}, c.keys, values, b)
+		ta.Equal(ErrOverBudget, errors.Cause(err), "%+v", b)
+	}
+
+	_, err := ChooseOpt(encode.I32{}, []string{"b", "a"}, []int32{1, 2}, Budget{})
+	ta.Equal(ErrKeyOutOfOrder, errors.Cause(err))
+}
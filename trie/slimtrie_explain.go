@@ -0,0 +1,297 @@
+package trie
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/openacid/low/bitstr"
+)
+
+// TraceStep describes a node visited by a lookup in Explain.
+//
+// Since 0.5.13
+type TraceStep struct {
+
+	// NodeID is the id of the visited node.
+	NodeID int32
+
+	// Kind is one of "big", "normal" and "short" for an inner node, or "leaf".
+	Kind string
+
+	// BitPos is the position in bit in the key when reaching this node.
+	BitPos int32
+
+	// PrefixLen is the number of bits an inner node skips.
+	PrefixLen int32
+
+	// PrefixStored is true if the skipped bits are stored, i.e., the trie is
+	// created with Opt.InnerPrefix, and are compared with the key.
+	// Otherwise the skipped bits of the key are not checked at all.
+	PrefixStored bool
+
+	// Prefix and KeyPrefix are the stored prefix and the bits of the key it
+	// is compared with, in form of "0110...".
+	// The stored prefix starts at the byte boundary of BitPos.
+	Prefix, KeyPrefix string
+
+	// PrefixMatch is false if a stored prefix does not match the key.
+	PrefixMatch bool
+
+	// Label is the label extracted from the key, in form of "0110", or ""
+	// if the key has no more bits.
+	// LabelWidth is the number of bits in a label: 4 or 8.
+	Label      string
+	LabelWidth int32
+
+	// HasBranch is true if the inner node has a branch of Label.
+	HasBranch bool
+
+	// LeafPrefixStored is true if the trie stores leaf prefixes, i.e., it is
+	// created with Opt.LeafPrefix.
+	// In this case the rest of the key, KeyTail, is compared with LeafPrefix
+	// at the leaf.
+	LeafPrefixStored bool
+	LeafPrefix       string
+	KeyTail          string
+	LeafPrefixMatch  bool
+}
+
+// Trace records how Get looks up a key, to explain a surprising result, such
+// as a false positive.
+//
+// Since 0.5.13
+type Trace struct {
+
+	// Key is the explained key.
+	Key string
+
+	// Steps are the visited nodes from the root.
+	Steps []TraceStep
+
+	// Found is the same as the second return value of Get.
+	Found bool
+
+	// Exact is true if the key is found and every bit of the key is
+	// compared with what is stored, i.e., the key is definitely in the trie.
+	// If the key is found but Exact is false, it is only a possible match,
+	// which might be a false positive.
+	Exact bool
+
+	// NodeID is the id of the leaf found, or -1.
+	NodeID int32
+
+	// Reason explains the result in one sentence.
+	Reason string
+}
+
+// Explain looks up key the same way as Get and records every node it visits.
+// It is much slower than Get and is meant for troubleshooting.
+//
+// Since 0.5.13
+func (st *SlimTrie) Explain(key string) *Trace {
+
+	ns := st.inner
+
+	tr := &Trace{Key: key, NodeID: -1}
+
+	if ns.NodeTypeBM == nil {
+		tr.Reason = "the trie is empty"
+		return tr
+	}
+
+	l := int32(8 * len(key))
+	qr := &querySession{
+		keyBitLen: l,
+		key:       key,
+	}
+
+	// whether all of the skipped bits are compared
+	allCompared := true
+
+	// whether the leaf is reached through the 0-th branch, i.e., the stored
+	// key ends where the key ends.
+	ended := false
+
+	eqID := int32(0)
+	i := int32(0)
+
+	for {
+
+		st.getNode(eqID, qr)
+
+		step := TraceStep{
+			NodeID: eqID,
+			BitPos: i,
+		}
+
+		if qr.isInner == 0 {
+			step.Kind = "leaf"
+			tr.Steps = append(tr.Steps, step)
+			break
+		}
+
+		switch {
+		case qr.ithInner < ns.BigInnerCnt:
+			step.Kind = "big"
+		case qr.to-qr.from == ns.ShortSize:
+			step.Kind = "short"
+		default:
+			step.Kind = "normal"
+		}
+
+		step.PrefixLen = qr.innerPrefixLen
+		step.PrefixMatch = true
+
+		if qr.hasInnerPrefix {
+			step.PrefixStored = true
+			step.Prefix = bitstrString(qr.innerPrefix)
+			step.KeyPrefix = bitsOf(key, i&(^7), qr.innerPrefixLen)
+
+			if bitstr.CmpUpto([]byte(key[i>>3:]), qr.innerPrefix) != 0 {
+				step.PrefixMatch = false
+				tr.Steps = append(tr.Steps, step)
+				tr.Reason = fmt.Sprintf("the key does not match the inner prefix of node %d", eqID)
+				return tr
+			}
+			i = i&(^7) + qr.innerPrefixLen
+		} else {
+			if qr.innerPrefixLen > 0 {
+				allCompared = false
+			}
+			i += qr.innerPrefixLen
+		}
+
+		if i > l {
+			tr.Steps = append(tr.Steps, step)
+			tr.Reason = fmt.Sprintf("the key is shorter than the prefix of node %d", eqID)
+			return tr
+		}
+
+		step.LabelWidth = qr.wordSize
+		if i < l {
+			step.Label = bitsOf(key, i, qr.wordSize)
+		}
+
+		lchID, has := st.getLeftChildID(qr, i)
+		step.HasBranch = has != 0
+		tr.Steps = append(tr.Steps, step)
+
+		if has == 0 {
+			tr.Reason = fmt.Sprintf("node %d has no branch of label %q", eqID, step.Label)
+			return tr
+		}
+		eqID = lchID + 1
+
+		if i == l {
+			// the key ends and matches the 0-th bit, which must lead to a
+			// leaf without prefix.
+			st.getNode(eqID, qr)
+			tr.Steps = append(tr.Steps, TraceStep{NodeID: eqID, Kind: "leaf", BitPos: i})
+			ended = true
+			break
+		}
+
+		i += qr.wordSize
+	}
+
+	leaf := &tr.Steps[len(tr.Steps)-1]
+
+	if ns.LeafPrefixes != nil {
+
+		leaf.LeafPrefixStored = true
+		if qr.hasLeafPrefix {
+			leaf.LeafPrefix = string(qr.leafPrefix)
+		}
+		if i < l {
+			leaf.KeyTail = key[i>>3:]
+		}
+		leaf.LeafPrefixMatch = bytes.Equal([]byte(leaf.KeyTail), []byte(leaf.LeafPrefix))
+
+		if !leaf.LeafPrefixMatch {
+			tr.Reason = fmt.Sprintf("the rest of the key %q does not match the leaf prefix %q of node %d",
+				leaf.KeyTail, leaf.LeafPrefix, eqID)
+			return tr
+		}
+	}
+
+	tr.Found = true
+	tr.NodeID = eqID
+
+	switch {
+	case !allCompared:
+		tr.Reason = "possible match: some bits skipped by inner nodes are not stored, add Opt.InnerPrefix to compare them"
+	case ns.LeafPrefixes == nil && !ended:
+		// Even if the key ends at the leaf, the stored key may continue.
+		tr.Reason = "possible match: the rest of the key after the leaf is not stored, add Opt.LeafPrefix to compare it"
+	default:
+		tr.Exact = true
+		tr.Reason = "exact match: every bit of the key is compared"
+	}
+
+	return tr
+}
+
+// String returns a human readable multi-line description of the lookup.
+//
+// Since 0.5.13
+func (tr *Trace) String() string {
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "key: %q\n", tr.Key)
+
+	for _, s := range tr.Steps {
+
+		fmt.Fprintf(&b, "#%d %s at bit %d", s.NodeID, s.Kind, s.BitPos)
+
+		if s.Kind == "leaf" {
+			if s.LeafPrefixStored {
+				fmt.Fprintf(&b, ": leaf prefix %q vs key tail %q, match: %t", s.LeafPrefix, s.KeyTail, s.LeafPrefixMatch)
+			}
+			b.WriteString("\n")
+			continue
+		}
+
+		if s.PrefixLen > 0 {
+			if s.PrefixStored {
+				fmt.Fprintf(&b, ": prefix %d bits %s vs key %s, match: %t", s.PrefixLen, s.Prefix, s.KeyPrefix, s.PrefixMatch)
+			} else {
+				fmt.Fprintf(&b, ": skip %d bits unchecked", s.PrefixLen)
+			}
+			if !s.PrefixMatch {
+				b.WriteString("\n")
+				continue
+			}
+			b.WriteString(",")
+		} else {
+			b.WriteString(":")
+		}
+
+		if s.Label == "" {
+			b.WriteString(" key ends")
+		} else {
+			fmt.Fprintf(&b, " label %s", s.Label)
+		}
+		fmt.Fprintf(&b, ", branch: %t\n", s.HasBranch)
+	}
+
+	fmt.Fprintf(&b, "found: %t, exact: %t: %s", tr.Found, tr.Exact, tr.Reason)
+
+	return b.String()
+}
+
+// bitsOf returns n bits of s from the from-th bit, in form of "0110".
+// It stops at the end of s.
+func bitsOf(s string, from, n int32) string {
+
+	var b strings.Builder
+	for i := from; i < from+n && i < int32(8*len(s)); i++ {
+		if s[i>>3]&(0x80>>uint(i&7)) != 0 {
+			b.WriteByte('1')
+		} else {
+			b.WriteByte('0')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,177 @@
+package trie
+
+import (
+	"testing"
+
+	"github.com/openacid/slim/benchhelper"
+	"github.com/openacid/slim/encode"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlimTrie_Explain(t *testing.T) {
+
+	ta := require.New(t)
+
+	c := statCases["simple"]
+	st, err := NewSlimTrie(encode.I32{}, c.keys, makeI32s(len(c.keys)), Opt{Complete: Bool(true)})
+	ta.NoError(err)
+
+	cases := []struct {
+		key  string
+		want string
+	}{
+		{"bcdef", trim(`
+key: "bcdef"
+#0 normal at bit 0: prefix 4 bits 0110 vs key 0110, match: true, label 0010, branch: true
+#2 normal at bit 8: prefix 8 bits 01100011 vs key 01100011, match: true, label 0110, branch: true
+#7 normal at bit 20: prefix 8 bits 01100100 vs key 01100100, match: true, label 0110, branch: true
+#13 leaf at bit 28: leaf prefix "e" vs key tail "ef", match: false
+found: false, exact: false: the rest of the key "ef" does not match the leaf prefix "e" of node 13`)},
+		{"bc", trim(`
+key: "bc"
+#0 normal at bit 0: prefix 4 bits 0110 vs key 0110, match: true, label 0010, branch: true
+#2 normal at bit 8: prefix 8 bits 01100011 vs key 01100011, match: true, key ends, branch: true
+#6 leaf at bit 16: leaf prefix "" vs key tail "", match: true
+found: true, exact: true: exact match: every bit of the key is compared`)},
+		{"a", trim(`
+key: "a"
+#0 normal at bit 0: prefix 4 bits 0110 vs key 0110, match: true, label 0001, branch: true
+#1 normal at bit 8: prefix 12 bits 011000100110 vs key , match: false
+found: false, exact: false: the key does not match the inner prefix of node 1`)},
+	}
+
+	for i, c := range cases {
+		ta.Equal(c.want, st.Explain(c.key).String(), "%d-th: %q", i+1, c.key)
+	}
+
+	t.Run("possibleMatch", func(t *testing.T) {
+		ta := require.New(t)
+
+		c := statCases["simple"]
+		st, err := NewSlimTrie(encode.I32{}, c.keys, makeI32s(len(c.keys)))
+		ta.NoError(err)
+
+		// "bxdf" is a false positive: "x" is skipped
+		want := trim(`
+key: "bxdf"
+#0 normal at bit 0: skip 4 bits unchecked, label 0010, branch: true
+#2 normal at bit 8: skip 8 bits unchecked, label 0110, branch: true
+#7 normal at bit 20: skip 4 bits unchecked, label 0110, branch: true
+#13 leaf at bit 28
+found: true, exact: false: possible match: some bits skipped by inner nodes are not stored, add Opt.InnerPrefix to compare them`)
+
+		tr := st.Explain("bxdf")
+		ta.Equal(want, tr.String())
+		ta.Equal(int32(13), tr.NodeID)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		ta := require.New(t)
+
+		st, err := NewSlimTrie(encode.I32{}, nil, nil)
+		ta.NoError(err)
+
+		tr := st.Explain("foo")
+		ta.False(tr.Found)
+		ta.Equal(int32(-1), tr.NodeID)
+		ta.Equal("the trie is empty", tr.Reason)
+	})
+}
+
+func TestSlimTrie_Explain_random(t *testing.T) {
+
+	keys := benchhelper.RandSortedStrings(2000, 10, nil)
+	testExplainKeys(t, keys[:1000:1000], keys[1000:])
+}
+
+func TestSlimTrie_Explain_varLength(t *testing.T) {
+
+	ta := require.New(t)
+
+	present := getKeys("50kl10")[:1000]
+
+	// Absent keys of different lengths. Some prefix of a present key ends
+	// exactly at the leaf of it, without reaching the end of the stored key.
+	isPresent := map[string]bool{}
+	for _, k := range present {
+		isPresent[k] = true
+	}
+
+	var absent []string
+	for _, k := range present {
+		for j := 1; j < len(k); j++ {
+			if !isPresent[k[:j]] {
+				absent = append(absent, k[:j])
+			}
+		}
+		absent = append(absent, k+"a")
+	}
+
+	testExplainKeys(t, present, absent)
+
+	st, err := NewSlimTrie(encode.I32{}, present, makeI32s(len(present)), Opt{InnerPrefix: Bool(true)})
+	ta.NoError(err)
+
+	endAtLeaf := 0
+	for _, k := range absent {
+		tr := st.Explain(k)
+		ta.False(tr.Exact, "key: %q", k)
+
+		leaf := tr.Steps[len(tr.Steps)-1]
+		if tr.Found && leaf.BitPos == int32(8*len(k)) {
+			endAtLeaf++
+		}
+	}
+	ta.Greater(endAtLeaf, 0)
+}
+
+func testExplainKeys(t *testing.T, present, absent []string) {
+
+	ta := require.New(t)
+
+	values := makeI32s(len(present))
+
+	isPresent := map[string]bool{}
+	for _, k := range present {
+		isPresent[k] = true
+	}
+
+	keys := append(append([]string{}, present...), absent...)
+
+	opts := []Opt{
+		{},
+		{InnerPrefix: Bool(true)},
+		{LeafPrefix: Bool(true)},
+		{Complete: Bool(true)},
+	}
+
+	for _, opt := range opts {
+
+		st, err := NewSlimTrie(encode.I32{}, present, values, opt)
+		ta.NoError(err)
+
+		for _, k := range keys {
+			tr := st.Explain(k)
+			id := st.GetID(k)
+
+			ta.Equal(id != -1, tr.Found, "key: %q, opt: %+v", k, opt)
+			ta.Equal(id, tr.NodeID, "key: %q, opt: %+v", k, opt)
+			if tr.Exact {
+				ta.True(isPresent[k], "key: %q, opt: %+v", k, opt)
+			}
+			if opt.Complete != nil {
+				ta.Equal(tr.Found, tr.Exact)
+			}
+		}
+
+		for _, k := range present {
+			ta.True(st.Explain(k).Found)
+		}
+
+		if opt.Complete != nil {
+			for _, k := range absent {
+				ta.False(st.Explain(k).Found)
+			}
+		}
+	}
+}
@@ -0,0 +1,312 @@
+package trie
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/openacid/errors"
+	"github.com/openacid/low/bitmap"
+	"github.com/openacid/low/bitstr"
+	"github.com/openacid/low/bmtree"
+)
+
+// ExportOpt specifies which part of a SlimTrie to export and what to show, for
+// ExportDOT and ExportJSON.
+// A zero ExportOpt exports the entire trie structure without labels, prefixes
+// or values.
+//
+// Since 0.5.13
+type ExportOpt struct {
+
+	// Prefix selects the subtree to export: the top most node at which every
+	// key with Prefix could be found.
+	// If no key could have Prefix, nothing is exported.
+	//
+	// Since 0.5.13
+	Prefix string
+
+	// MaxDepth limits the depth of exported nodes, relative to the subtree
+	// root, whose depth is 0.
+	// An inner node at MaxDepth is exported with `truncated` but without its
+	// children.
+	// 0 means unlimited.
+	//
+	// Since 0.5.13
+	MaxDepth int
+
+	// Labels shows the label of every branch, in bits, such as "0110".
+	//
+	// Since 0.5.13
+	Labels bool
+
+	// InnerPrefixes shows the number of bits an inner node skips, and the
+	// prefix bits if it is stored, i.e., with Opt.InnerPrefix.
+	//
+	// Since 0.5.13
+	InnerPrefixes bool
+
+	// LeafPrefixes shows the stored leaf prefixes, i.e., with
+	// Opt.LeafPrefix.
+	//
+	// Since 0.5.13
+	LeafPrefixes bool
+
+	// Values shows the values of leaves.
+	//
+	// Since 0.5.13
+	Values bool
+}
+
+// exportNode is a node in the output of ExportJSON.
+type exportNode struct {
+	ID int32 `json:"id"`
+
+	// Kind is one of "big", "normal" and "short" for inner nodes, or "leaf".
+	Kind string `json:"kind"`
+
+	Step        int32        `json:"step,omitempty"`
+	InnerPrefix string       `json:"inner_prefix,omitempty"`
+	LeafPrefix  *string      `json:"leaf_prefix,omitempty"`
+	Value       interface{}  `json:"value,omitempty"`
+	Truncated   bool         `json:"truncated,omitempty"`
+	Children    []exportEdge `json:"children,omitempty"`
+}
+
+type exportEdge struct {
+	Label *string     `json:"label,omitempty"`
+	Node  *exportNode `json:"node"`
+}
+
+// ExportDOT writes the structure of the SlimTrie in Graphviz DOT language.
+// A node is labeled with its node id, and with the skipped bits, prefixes and
+// value as ExportOpt specifies.
+// Leaves are drawn in boxes.
+//
+// E.g., `dot -Tsvg` renders it to an SVG image.
+//
+// Since 0.5.13
+func (st *SlimTrie) ExportDOT(w io.Writer, opt ExportOpt) error {
+
+	root := st.exportTree(opt)
+
+	b := bytes.NewBuffer(nil)
+	b.WriteString("digraph slimtrie {\n")
+	b.WriteString("    node [shape=ellipse];\n")
+
+	var walk func(n *exportNode)
+	walk = func(n *exportNode) {
+
+		lines := []string{fmt.Sprintf("#%d", n.ID)}
+		attrs := ""
+
+		if n.Kind == "leaf" {
+			attrs = " shape=box"
+			if n.LeafPrefix != nil {
+				lines = append(lines, strconv.Quote(*n.LeafPrefix))
+			}
+			if opt.Values {
+				lines = append(lines, fmt.Sprintf("=%v", n.Value))
+			}
+		} else {
+			if opt.InnerPrefixes && n.Step > 0 {
+				lines = append(lines, fmt.Sprintf("+%d", n.Step))
+			}
+			if n.InnerPrefix != "" {
+				lines = append(lines, n.InnerPrefix)
+			}
+			if n.Truncated {
+				lines = append(lines, "...")
+				attrs = " style=dashed"
+			}
+		}
+
+		fmt.Fprintf(b, "    n%d [label=\"%s\"%s];\n", n.ID, escapeDOT(lines), attrs)
+
+		for _, e := range n.Children {
+			if e.Label != nil {
+				fmt.Fprintf(b, "    n%d -> n%d [label=\"%s\"];\n", n.ID, e.Node.ID, escapeDOT([]string{*e.Label}))
+			} else {
+				fmt.Fprintf(b, "    n%d -> n%d;\n", n.ID, e.Node.ID)
+			}
+			walk(e.Node)
+		}
+	}
+
+	if root != nil {
+		walk(root)
+	}
+
+	b.WriteString("}\n")
+
+	_, err := w.Write(b.Bytes())
+	return errors.WithStack(err)
+}
+
+// ExportJSON writes the structure of the SlimTrie as a JSON object with a
+// nested "root" node, which is null if nothing is exported.
+//
+// A node has an "id" and a "kind": "big", "normal", "short" for inner nodes, or
+// "leaf".
+// An inner node has "children", a list of {"label", "node"}, and "truncated"
+// if MaxDepth stops at it.
+// An inner node has "step", the number of bits it skips, and "inner_prefix" of
+// the skipped bits if they are stored.
+// A leaf has "leaf_prefix" and "value".
+// Labels, prefixes and values present only if ExportOpt specifies.
+//
+// Since 0.5.13
+func (st *SlimTrie) ExportJSON(w io.Writer, opt ExportOpt) error {
+
+	root := st.exportTree(opt)
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	err := enc.Encode(map[string]*exportNode{"root": root})
+	return errors.WithStack(err)
+}
+
+// exportTree builds the exported subtree.
+// It returns nil if there is no such subtree.
+func (st *SlimTrie) exportTree(opt ExportOpt) *exportNode {
+
+	rootID := st.subtreeRoot(opt.Prefix)
+	if rootID == -1 {
+		return nil
+	}
+
+	return st.exportNode(rootID, 0, opt)
+}
+
+// subtreeRoot returns the id of the top most node at which every key with
+// prefix could be found, or -1 if there is no such node.
+func (st *SlimTrie) subtreeRoot(prefix string) int32 {
+
+	if st.inner.NodeTypeBM == nil {
+		return -1
+	}
+
+	l := int32(8 * len(prefix))
+	qr := &querySession{
+		keyBitLen: l,
+		key:       prefix,
+	}
+
+	id := int32(0)
+	i := int32(0)
+
+	for {
+
+		if i >= l {
+			return id
+		}
+
+		st.getNode(id, qr)
+		if qr.isInner == 0 {
+			return id
+		}
+
+		if qr.hasInnerPrefix {
+			i = i&(^7) + qr.innerPrefixLen
+		} else {
+			i += qr.innerPrefixLen
+		}
+
+		if i >= l {
+			return id
+		}
+
+		lchID, has := st.getLeftChildID(qr, i)
+		if has == 0 {
+			return -1
+		}
+
+		id = lchID + 1
+		i += qr.wordSize
+	}
+}
+
+func (st *SlimTrie) exportNode(id int32, depth int, opt ExportOpt) *exportNode {
+
+	ns := st.inner
+
+	qr := &querySession{}
+	st.getNode(id, qr)
+
+	n := &exportNode{ID: id}
+
+	if qr.isInner == 0 {
+		n.Kind = "leaf"
+		if opt.LeafPrefixes && qr.hasLeafPrefix {
+			p := string(qr.leafPrefix)
+			n.LeafPrefix = &p
+		}
+		if opt.Values {
+			n.Value = st.getLeaf(id)
+		}
+		return n
+	}
+
+	switch {
+	case qr.ithInner < ns.BigInnerCnt:
+		n.Kind = "big"
+	case qr.to-qr.from == ns.ShortSize:
+		n.Kind = "short"
+	default:
+		n.Kind = "normal"
+	}
+
+	if opt.InnerPrefixes {
+		n.Step = qr.innerPrefixLen
+		if qr.hasInnerPrefix {
+			n.InnerPrefix = bitstrString(qr.innerPrefix)
+		}
+	}
+
+	if opt.MaxDepth > 0 && depth >= opt.MaxDepth {
+		n.Truncated = true
+		return n
+	}
+
+	firstChild, _ := bitmap.Rank128(ns.Inners.Words, ns.Inners.RankIndex, qr.from)
+	firstChild++
+
+	for i, label := range st.getLabels(qr) {
+		e := exportEdge{Node: st.exportNode(firstChild+int32(i), depth+1, opt)}
+		if opt.Labels {
+			l := bmtree.PathStr(label)
+			e.Label = &l
+		}
+		n.Children = append(n.Children, e)
+	}
+
+	return n
+}
+
+// bitstrString converts a bitstr to a string of "0" and "1".
+func bitstrString(bs []byte) string {
+
+	l := int(bitstr.Len(bs))
+
+	var s strings.Builder
+	for i := 0; i < l; i++ {
+		if bs[i>>3]&(0x80>>uint(i&7)) != 0 {
+			s.WriteByte('1')
+		} else {
+			s.WriteByte('0')
+		}
+	}
+	return s.String()
+}
+
+// escapeDOT joins lines into a DOT double-quoted string with line breaks.
+func escapeDOT(lines []string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	for i, l := range lines {
+		lines[i] = r.Replace(l)
+	}
+	return strings.Join(lines, `\n`)
+}
@@ -0,0 +1,183 @@
+package trie
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/openacid/slim/benchhelper"
+	"github.com/openacid/slim/encode"
+	"github.com/stretchr/testify/require"
+)
+
+var exportAll = ExportOpt{
+	Labels:        true,
+	InnerPrefixes: true,
+	LeafPrefixes:  true,
+	Values:        true,
+}
+
+func TestSlimTrie_ExportDOT(t *testing.T) {
+
+	ta := require.New(t)
+
+	c := statCases["simple"]
+	st, err := NewSlimTrie(encode.I32{}, c.keys, makeI32s(len(c.keys)), Opt{Complete: Bool(true)})
+	ta.NoError(err)
+
+	// see statCases["simple"].slimStr
+	want := trim(`
+digraph slimtrie {
+    node [shape=ellipse];
+    n0 [label="#0\n+4\n0110"];
+    n0 -> n1 [label="0001"];
+    n1 [label="#1\n+12\n011000100110"];
+    n1 -> n4 [label="0011"];
+    n4 [label="#4"];
+    n4 -> n8 [label=""];
+    n8 [label="#8\n=0" shape=box];
+    n4 -> n9 [label="0110"];
+    n9 [label="#9\n\"d\"\n=1" shape=box];
+    n1 -> n5 [label="0100"];
+    n5 [label="#5"];
+    n5 -> n10 [label=""];
+    n10 [label="#10\n=2" shape=box];
+    n5 -> n11 [label="0110"];
+    n11 [label="#11\n\"e\"\n=3" shape=box];
+    n0 -> n2 [label="0010"];
+    n2 [label="#2\n+8\n01100011"];
+    n2 -> n6 [label=""];
+    n6 [label="#6\n=4" shape=box];
+    n2 -> n7 [label="0110"];
+    n7 [label="#7\n+8\n01100100"];
+    n7 -> n12 [label=""];
+    n12 [label="#12\n=5" shape=box];
+    n7 -> n13 [label="0110"];
+    n13 [label="#13\n\"e\"\n=6" shape=box];
+    n0 -> n3 [label="0011"];
+    n3 [label="#3\n\"de\"\n=7" shape=box];
+}
+`)
+
+	b := bytes.NewBuffer(nil)
+	ta.NoError(st.ExportDOT(b, exportAll))
+	ta.Equal(want+"\n", b.String())
+
+	// subtree without labels, values and prefixes
+	want = trim(`
+digraph slimtrie {
+    node [shape=ellipse];
+    n2 [label="#2"];
+    n2 -> n6;
+    n6 [label="#6" shape=box];
+    n2 -> n7;
+    n7 [label="#7\n..." style=dashed];
+}
+`)
+
+	b.Reset()
+	ta.NoError(st.ExportDOT(b, ExportOpt{Prefix: "bc", MaxDepth: 1}))
+	ta.Equal(want+"\n", b.String())
+
+	b.Reset()
+	ta.NoError(st.ExportDOT(b, ExportOpt{Prefix: "x"}))
+	ta.Equal("digraph slimtrie {\n    node [shape=ellipse];\n}\n", b.String())
+}
+
+func TestSlimTrie_ExportJSON(t *testing.T) {
+
+	ta := require.New(t)
+
+	c := statCases["simple"]
+	st, err := NewSlimTrie(encode.I32{}, c.keys, makeI32s(len(c.keys)), Opt{Complete: Bool(true)})
+	ta.NoError(err)
+
+	opt := exportAll
+	opt.Prefix = "bc"
+	opt.MaxDepth = 1
+
+	want := trim(`
+{
+  "root": {
+    "id": 2,
+    "kind": "normal",
+    "step": 8,
+    "inner_prefix": "01100011",
+    "children": [
+      {
+        "label": "",
+        "node": {
+          "id": 6,
+          "kind": "leaf",
+          "value": 4
+        }
+      },
+      {
+        "label": "0110",
+        "node": {
+          "id": 7,
+          "kind": "normal",
+          "step": 8,
+          "inner_prefix": "01100100",
+          "truncated": true
+        }
+      }
+    ]
+  }
+}
+`)
+
+	b := bytes.NewBuffer(nil)
+	ta.NoError(st.ExportJSON(b, opt))
+	ta.Equal(want+"\n", b.String())
+
+	b.Reset()
+	ta.NoError(st.ExportJSON(b, ExportOpt{Prefix: "cde"}))
+	ta.Equal("{\n  \"root\": {\n    \"id\": 3,\n    \"kind\": \"leaf\"\n  }\n}\n", b.String())
+
+	empty, err := NewSlimTrie(encode.I32{}, nil, nil)
+	ta.NoError(err)
+
+	b.Reset()
+	ta.NoError(empty.ExportJSON(b, exportAll))
+	ta.Equal("{\n  \"root\": null\n}\n", b.String())
+}
+
+func TestSlimTrie_ExportJSON_allNodes(t *testing.T) {
+
+	ta := require.New(t)
+
+	keys := benchhelper.RandSortedStrings(3000, 10, nil)
+	st, err := NewSlimTrie(encode.I32{}, keys, makeI32s(len(keys)))
+	ta.NoError(err)
+
+	d := st.StatDetail()
+	ta.True(d.ShortInnerCnt > 0)
+	ta.True(d.BigInnerCnt > 0)
+
+	b := bytes.NewBuffer(nil)
+	ta.NoError(st.ExportJSON(b, exportAll))
+
+	var out struct{ Root *exportNode }
+	ta.NoError(json.Unmarshal(b.Bytes(), &out))
+
+	// every node is exported once, with the value of a key
+	ids := map[int32]bool{}
+	kinds := map[string]int32{}
+	var walk func(n *exportNode)
+	walk = func(n *exportNode) {
+		ta.False(ids[n.ID])
+		ids[n.ID] = true
+		kinds[n.Kind]++
+		for _, e := range n.Children {
+			walk(e.Node)
+		}
+	}
+	walk(out.Root)
+
+	ta.Equal(int(d.NodeCnt), len(ids))
+	ta.Equal(d.KeyCnt, kinds["leaf"])
+	ta.Equal(d.BigInnerCnt, kinds["big"])
+	ta.Equal(d.ShortInnerCnt, kinds["short"])
+	ta.Equal(d.NormalInnerCnt, kinds["normal"])
+}
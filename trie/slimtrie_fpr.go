@@ -0,0 +1,265 @@
+package trie
+
+import (
+	"math"
+	"sync"
+
+	"github.com/openacid/low/bitmap"
+	"github.com/openacid/low/bmtree"
+)
+
+// EstimateFPR measures the false positive rate of Get with sample keys that
+// are not in the SlimTrie: the ratio of sample keys that Get finds.
+// A sample key that is actually in the SlimTrie is counted as a false
+// positive.
+// It returns 0 if sampleAbsent is empty.
+//
+// Since 0.5.13
+func (st *SlimTrie) EstimateFPR(sampleAbsent []string) float64 {
+
+	if len(sampleAbsent) == 0 {
+		return 0
+	}
+
+	fp := 0
+	for _, k := range sampleAbsent {
+		if st.GetID(k) != -1 {
+			fp++
+		}
+	}
+
+	return float64(fp) / float64(len(sampleAbsent))
+}
+
+// fprCache stores the ExpectedFPR computed the first time it is called.
+type fprCache struct {
+	once sync.Once
+	v    float64
+}
+
+// fprLeaf describes what an absent key has to match to reach a leaf.
+type fprLeaf struct {
+
+	// p is the probability a random key of a proper length matches all the
+	// labels and the stored prefixes on the path to the leaf.
+	p float64
+
+	// keyLen is the length in byte a key must have to be found at the leaf.
+	// If exactLen is false a key must be at least keyLen bytes.
+	keyLen   int32
+	exactLen bool
+
+	// allCompared is true if every bit of a key is compared to find it at the
+	// leaf, in which case it finds only the key in the SlimTrie.
+	allCompared bool
+}
+
+// ExpectedFPR estimates the false positive rate of Get from the structure of
+// the SlimTrie, without sample keys.
+//
+// The model is the same as the FPR benchmark in trie/benchmark: an absent key
+// is a string of random bytes, whose length follows the length distribution
+// of the keys in the SlimTrie.
+// Such a key is found if it is long enough to reach a leaf and matches every
+// label, stored inner prefix and stored leaf prefix on the path.
+// The bits an inner node skips without storing them match any key.
+//
+// The length of a key is known if it ends at an inner node or if leaf
+// prefixes are stored, i.e., with Opt.LeafPrefix.
+// Otherwise a key is assumed to be long enough to reach any leaf.
+//
+// Keys of a small alphabet, such as letters, have more common bits than
+// random bytes, thus a higher false positive rate than estimated.
+// EstimateFPR with real absent keys measures it.
+//
+// It walks through every node the first time it is called, which is much
+// slower than Get. The result is cached in the SlimTrie.
+// A SlimTrie created with Opt.Complete always has an ExpectedFPR of 0.
+//
+// Since 0.5.13
+func (st *SlimTrie) ExpectedFPR() float64 {
+
+	c := st.fpr
+	if c == nil {
+		return st.expectedFPR()
+	}
+
+	c.once.Do(func() {
+		c.v = st.expectedFPR()
+	})
+	return c.v
+}
+
+// expectedFPR is ExpectedFPR without cache.
+func (st *SlimTrie) expectedFPR() float64 {
+	return fprOf(st.fprLeaves())
+}
+
+// fprOf calculates the expected false positive rate with the fprLeaf of every
+// leaf.
+func fprOf(leaves []fprLeaf) float64 {
+
+	if len(leaves) == 0 {
+		return 0
+	}
+
+	// Length distribution of keys.
+	// Keys of unknown length are counted in longCnt.
+	lenCnt := map[int32]int{}
+	longCnt := 0
+	for _, lf := range leaves {
+		if lf.exactLen {
+			lenCnt[lf.keyLen]++
+		} else {
+			longCnt++
+		}
+	}
+
+	n := float64(len(leaves))
+
+	// probability of a key being at least l bytes.
+	atLeast := func(l int32) float64 {
+		c := longCnt
+		for kl, cnt := range lenCnt {
+			if kl >= l {
+				c += cnt
+			}
+		}
+		return float64(c) / n
+	}
+
+	// cache of atLeast, leaves at the same depth share it.
+	atLeastCache := map[int32]float64{}
+
+	fpr := float64(0)
+	for _, lf := range leaves {
+		if lf.allCompared {
+			continue
+		}
+
+		if lf.exactLen {
+			fpr += lf.p * float64(lenCnt[lf.keyLen]) / n
+			continue
+		}
+
+		pl, ok := atLeastCache[lf.keyLen]
+		if !ok {
+			pl = atLeast(lf.keyLen)
+			atLeastCache[lf.keyLen] = pl
+		}
+		fpr += lf.p * pl
+	}
+
+	return math.Min(fpr, 1)
+}
+
+// fprLeaves walks through every node and returns fprLeaf of every leaf.
+func (st *SlimTrie) fprLeaves() []fprLeaf {
+
+	ns := st.inner
+
+	if ns.NodeTypeBM == nil {
+		return nil
+	}
+
+	hasLeafPrefix := ns.LeafPrefixes != nil
+
+	type visit struct {
+		id int32
+
+		// bit position in a key when reaching this node
+		i int32
+
+		fprLeaf
+	}
+
+	rst := make([]fprLeaf, 0)
+
+	stack := []visit{{id: 0, fprLeaf: fprLeaf{p: 1, allCompared: true}}}
+	qr := &querySession{}
+
+	for len(stack) > 0 {
+
+		v := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		st.getNode(v.id, qr)
+
+		if qr.isInner == 0 {
+
+			lf := v.fprLeaf
+
+			if v.exactLen {
+				// reached by the terminal branch: the key ends at an inner node.
+				rst = append(rst, lf)
+				continue
+			}
+
+			if !hasLeafPrefix {
+				// a key could be any length to reach here
+				lf.keyLen = (v.i + 7) >> 3
+				lf.allCompared = false
+				rst = append(rst, lf)
+				continue
+			}
+
+			lf.exactLen = true
+			lf.keyLen = v.i >> 3
+
+			if qr.hasLeafPrefix {
+				n := int32(len(qr.leafPrefix))
+				lf.keyLen += n
+				// the first byte of the leaf prefix is partially matched by
+				// the label.
+				lf.p *= math.Exp2(-float64(8*n - v.i&7))
+			} else if v.i&7 != 0 {
+				// a key without a leaf prefix must end here, but it can not end
+				// at the middle of a byte.
+				lf.p = 0
+			}
+
+			rst = append(rst, lf)
+			continue
+		}
+
+		i := v.i
+		if qr.hasInnerPrefix {
+			end := i&(^7) + qr.innerPrefixLen
+			v.p *= math.Exp2(-float64(end - i))
+			i = end
+		} else {
+			if qr.innerPrefixLen > 0 {
+				v.allCompared = false
+			}
+			i += qr.innerPrefixLen
+		}
+
+		firstChild, _ := bitmap.Rank128(ns.Inners.Words, ns.Inners.RankIndex, qr.from)
+		firstChild++
+
+		for j, label := range st.getLabels(qr) {
+
+			w := bmtree.PathLen(label)
+
+			child := v
+			child.id = firstChild + int32(j)
+			child.i = i + w
+
+			if w == 0 {
+				// the terminal branch: a key ends at i, which must be at the
+				// boundary of a byte.
+				child.exactLen = true
+				child.keyLen = i >> 3
+				if i&7 != 0 {
+					child.p = 0
+				}
+			} else {
+				child.p *= math.Exp2(-float64(w))
+			}
+
+			stack = append(stack, child)
+		}
+	}
+
+	return rst
+}
@@ -0,0 +1,144 @@
+package trie
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/openacid/slim/benchhelper"
+	"github.com/openacid/slim/encode"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlimTrie_EstimateFPR(t *testing.T) {
+
+	ta := require.New(t)
+
+	c := statCases["simple"]
+	absent := []string{"abcx", "bxdf", "b", "cxx", "ab", "x"}
+
+	cases := []struct {
+		opt  Opt
+		want float64
+	}{
+		// "bxdf" and "cxx"
+		{Opt{}, 2.0 / 6},
+		// "cxx"
+		{Opt{InnerPrefix: Bool(true)}, 1.0 / 6},
+		{Opt{LeafPrefix: Bool(true)}, 0},
+		{Opt{Complete: Bool(true)}, 0},
+	}
+
+	for i, c2 := range cases {
+		st, err := NewSlimTrie(encode.I32{}, c.keys, makeI32s(len(c.keys)), c2.opt)
+		ta.NoError(err)
+
+		ta.Equal(c2.want, st.EstimateFPR(absent), "%d-th: %+v", i+1, c2.opt)
+		ta.Equal(float64(0), st.EstimateFPR(nil))
+	}
+}
+
+func TestSlimTrie_ExpectedFPR(t *testing.T) {
+
+	ta := require.New(t)
+
+	c := statCases["simple"]
+
+	cases := []struct {
+		opt  Opt
+		want float64
+	}{
+		{Opt{}, 0.0750732421875},
+		{Opt{InnerPrefix: Bool(true)}, 0.003906255587935448},
+		{Opt{LeafPrefix: Bool(true)}, 0.013689517974853516},
+		{Opt{Complete: Bool(true)}, 0},
+	}
+
+	for i, c2 := range cases {
+		st, err := NewSlimTrie(encode.I32{}, c.keys, makeI32s(len(c.keys)), c2.opt)
+		ta.NoError(err)
+
+		ta.InDelta(c2.want, st.ExpectedFPR(), 1e-12, "%d-th: %+v", i+1, c2.opt)
+		ta.Equal(st.ExpectedFPR(), st.Stat().ExpectedFPR)
+	}
+
+	t.Run("empty", func(t *testing.T) {
+		ta := require.New(t)
+
+		st, err := NewSlimTrie(encode.I32{}, nil, nil)
+		ta.NoError(err)
+		ta.Equal(float64(0), st.ExpectedFPR())
+	})
+
+	t.Run("singleKey", func(t *testing.T) {
+		ta := require.New(t)
+
+		// every key reaches the only leaf
+		st, err := NewSlimTrie(encode.I32{}, []string{"foo"}, makeI32s(1))
+		ta.NoError(err)
+		ta.Equal(float64(1), st.ExpectedFPR())
+
+		st, err = NewSlimTrie(encode.I32{}, []string{"foo"}, makeI32s(1), Opt{Complete: Bool(true)})
+		ta.NoError(err)
+		ta.Equal(float64(0), st.ExpectedFPR())
+	})
+
+	t.Run("cache", func(t *testing.T) {
+		ta := require.New(t)
+
+		st, err := NewSlimTrie(encode.I32{}, c.keys, makeI32s(len(c.keys)))
+		ta.NoError(err)
+		ta.Equal(0.0750732421875, st.ExpectedFPR())
+
+		// the cache is reset when another SlimTrie is loaded into st.
+		complete, err := NewSlimTrie(encode.I32{}, c.keys, makeI32s(len(c.keys)), Opt{Complete: Bool(true)})
+		ta.NoError(err)
+		b, err := complete.Marshal()
+		ta.NoError(err)
+
+		ta.NoError(st.Unmarshal(b))
+		ta.Equal(float64(0), st.ExpectedFPR())
+		ta.Equal(float64(0), st.Stat().ExpectedFPR)
+
+		st.Reset()
+		ta.Equal(float64(0), st.ExpectedFPR())
+	})
+}
+
+func TestSlimTrie_ExpectedFPR_random(t *testing.T) {
+
+	ta := require.New(t)
+
+	// ExpectedFPR assumes keys of random bytes
+	alphabet := make([]byte, 256)
+	for i := range alphabet {
+		alphabet[i] = byte(i)
+	}
+
+	keys := benchhelper.RandSortedStrings(1000, 16, alphabet)
+
+	// keys of various length
+	values := makeI32s(len(keys))
+	for i, k := range keys {
+		keys[i] = k[:4+i%12]
+	}
+
+	absent := make([]string, 100*1000)
+	for i := range absent {
+		absent[i] = benchhelper.RandString(len(keys[rand.Intn(len(keys))]), alphabet)
+	}
+
+	opts := []Opt{
+		{},
+		{InnerPrefix: Bool(true)},
+		{LeafPrefix: Bool(true)},
+		{Complete: Bool(true)},
+	}
+
+	for _, opt := range opts {
+
+		st, err := NewSlimTrie(encode.I32{}, keys, values, opt)
+		ta.NoError(err)
+
+		ta.InDelta(st.EstimateFPR(absent), st.ExpectedFPR(), 0.01, "opt: %+v", opt)
+	}
+}
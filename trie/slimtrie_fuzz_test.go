@@ -0,0 +1,118 @@
+//go:build go1.18
+
+package trie
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/openacid/slim/encode"
+)
+
+var fuzzQueryKeys = []string{
+	"",
+	"a",
+	"ab",
+	"abc",
+	"b",
+	"zzzzzzzz",
+	"\x00",
+	"\xff\xff",
+}
+
+func fuzzSeeds(f *testing.F) {
+
+	keys := getKeys("20kl10")[:200]
+	values := makeI32s(len(keys))
+
+	opts := []Opt{
+		{},
+		{InnerPrefix: Bool(true)},
+		{Complete: Bool(true)},
+	}
+
+	for _, opt := range opts {
+		st, err := NewSlimTrie(encode.I32{}, keys, values, opt)
+		if err != nil {
+			f.Fatal(err)
+		}
+		buf, err := st.Marshal()
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(buf)
+	}
+
+	for _, ks := range [][]string{nil, {"a"}} {
+		st, err := NewSlimTrie(encode.I32{}, ks, makeI32s(len(ks)))
+		if err != nil {
+			f.Fatal(err)
+		}
+		buf, err := st.Marshal()
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(buf)
+	}
+
+	for _, fn := range []string{
+		"testdata/slimtrie-data-10vl5-0.5.9",
+		"testdata/slimtrie-data-10vl5-allpref-0.5.10",
+	} {
+		buf, err := ioutil.ReadFile(fn)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(buf)
+	}
+}
+
+// fuzzQuery runs all kinds of query on a SlimTrie.
+func fuzzQuery(st *SlimTrie) {
+
+	for _, k := range fuzzQueryKeys {
+		st.Get(k)
+		st.RangeGet(k)
+		st.Search(k)
+	}
+
+	ns := st.inner
+	if ns.NodeTypeBM != nil && ns.InnerPrefixes != nil && ns.InnerPrefixes.PositionBM != nil && ns.LeafPrefixes != nil {
+		for _, k := range fuzzQueryKeys {
+			n := 0
+			st.ScanFrom(k, true, true, func(key, value []byte) bool {
+				n++
+				return n < 100
+			})
+		}
+	}
+}
+
+func FuzzSlimTrie_Unmarshal(f *testing.F) {
+
+	fuzzSeeds(f)
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+
+		// Skip checksum to let malformed data reach validation.
+		for _, opt := range []UnmarshalOpt{{}, {SkipChecksum: true}} {
+			st := &SlimTrie{encoder: encode.I32{}}
+			err := st.UnmarshalWithOpt(data, opt)
+			if err == nil {
+				fuzzQuery(st)
+			}
+		}
+	})
+}
+
+func FuzzLoad(f *testing.F) {
+
+	fuzzSeeds(f)
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		st, err := Load(data)
+		if err == nil {
+			fuzzQuery(st)
+		}
+	})
+}
@@ -0,0 +1,63 @@
+package trie
+
+import "math"
+
+// GetF32 is same as Get() except it is optimized for float32.
+//
+// Since 0.5.13
+func (st *SlimTrie) GetF32(key string) (float32, bool) {
+
+	eqID := st.GetID(key)
+
+	if eqID == -1 {
+		return 0, false
+	}
+
+	ith, _ := st.getLeafIndex(eqID)
+	stIdx := ith << 2
+
+	b := st.inner.Leaves.Bytes[stIdx : stIdx+4]
+
+	v := uint32(b[0]) | uint32(b[1])<<8 | uint32(b[2])<<16 | uint32(b[3])<<24
+
+	return math.Float32frombits(v), true
+}
+
+// GetF64 is same as Get() except it is optimized for float64.
+//
+// Since 0.5.13
+func (st *SlimTrie) GetF64(key string) (float64, bool) {
+
+	eqID := st.GetID(key)
+
+	if eqID == -1 {
+		return 0, false
+	}
+
+	ith, _ := st.getLeafIndex(eqID)
+	stIdx := ith << 3
+
+	b := st.inner.Leaves.Bytes[stIdx : stIdx+8]
+
+	v := uint64(b[0]) | uint64(b[1])<<8 | uint64(b[2])<<16 | uint64(b[3])<<24 | uint64(b[4])<<32 | uint64(b[5])<<40 | uint64(b[6])<<48 | uint64(b[7])<<56
+
+	return math.Float64frombits(v), true
+}
+
+// GetBool is same as Get() except it is optimized for bool.
+//
+// Since 0.5.13
+func (st *SlimTrie) GetBool(key string) (bool, bool) {
+
+	eqID := st.GetID(key)
+
+	if eqID == -1 {
+		return false, false
+	}
+
+	ith, _ := st.getLeafIndex(eqID)
+
+	v := st.inner.Leaves.Bytes[ith] != 0
+
+	return v, true
+}
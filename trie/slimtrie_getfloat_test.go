@@ -0,0 +1,83 @@
+package trie
+
+import (
+	"math"
+	"testing"
+
+	"github.com/openacid/slim/encode"
+	"github.com/openacid/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlimTrie_GetF32(t *testing.T) {
+
+	ta := require.New(t)
+
+	keys := getKeys("20kvl10")
+	values := make([]float32, len(keys))
+	for i := 0; i < len(keys); i++ {
+		values[i] = math.Float32frombits(uint32(fibhash64(uint64(i))))
+		if values[i] != values[i] {
+			// NaN can not be compared with ta.Equal
+			values[i] = float32(i)
+		}
+	}
+
+	st, err := NewSlimTrie(encode.F32{}, keys, values)
+	ta.NoError(err)
+
+	testUnknownKeysGRS(t, st, testutil.RandStrSlice(len(keys)*5, 0, 10))
+
+	for i, key := range keys {
+
+		v, found := st.GetF32(key)
+		ta.True(found, "Get:%v", key)
+		ta.Equal(values[i], v, "Get:%v", key)
+	}
+}
+
+func TestSlimTrie_GetF64(t *testing.T) {
+
+	ta := require.New(t)
+
+	keys := getKeys("20kvl10")
+	values := make([]float64, len(keys))
+	for i := 0; i < len(keys); i++ {
+		values[i] = float64(fibhash64(uint64(i))) / 3
+	}
+
+	st, err := NewSlimTrie(encode.F64{}, keys, values)
+	ta.NoError(err)
+
+	testUnknownKeysGRS(t, st, testutil.RandStrSlice(len(keys)*5, 0, 10))
+
+	for i, key := range keys {
+
+		v, found := st.GetF64(key)
+		ta.True(found, "Get:%v", key)
+		ta.Equal(values[i], v, "Get:%v", key)
+	}
+}
+
+func TestSlimTrie_GetBool(t *testing.T) {
+
+	ta := require.New(t)
+
+	keys := getKeys("20kvl10")
+	values := make([]bool, len(keys))
+	for i := 0; i < len(keys); i++ {
+		values[i] = fibhash64(uint64(i))&1 == 1
+	}
+
+	st, err := NewSlimTrie(encode.Bool{}, keys, values)
+	ta.NoError(err)
+
+	testUnknownKeysGRS(t, st, testutil.RandStrSlice(len(keys)*5, 0, 10))
+
+	for i, key := range keys {
+
+		v, found := st.GetBool(key)
+		ta.True(found, "Get:%v", key)
+		ta.Equal(values[i], v, "Get:%v", key)
+	}
+}
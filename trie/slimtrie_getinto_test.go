@@ -0,0 +1,110 @@
+package trie
+
+import (
+	"testing"
+
+	"github.com/openacid/slim/encode"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlimTrie_GetInto(t *testing.T) {
+
+	ta := require.New(t)
+
+	keys := getKeys("20kvl10")
+
+	t.Run("u32", func(t *testing.T) {
+		values := make([]uint32, len(keys))
+		for i := range values {
+			values[i] = uint32(fibhash64(uint64(i)))
+		}
+
+		st, err := NewSlimTrie(encode.U32{}, keys, values)
+		ta.NoError(err)
+
+		v := new(uint32)
+		for i, key := range keys {
+			ta.True(st.GetInto(key, v), "GetInto:%v", key)
+			ta.Equal(values[i], *v, "GetInto:%v", key)
+		}
+
+		allocs := testing.AllocsPerRun(100, func() {
+			st.GetInto(keys[5], v)
+		})
+		ta.Equal(float64(0), allocs)
+	})
+
+	t.Run("varint", func(t *testing.T) {
+		values := make([]int64, len(keys))
+		for i := range values {
+			values[i] = int64(fibhash64(uint64(i))) >> uint(i%64)
+		}
+
+		st, err := NewSlimTrie(encode.Varint{}, keys, values, Opt{DedupValue: Bool(false)})
+		ta.NoError(err)
+
+		v := new(int64)
+		for i, key := range keys {
+			ta.True(st.GetInto(key, v), "GetInto:%v", key)
+			ta.Equal(values[i], *v, "GetInto:%v", key)
+		}
+
+		allocs := testing.AllocsPerRun(100, func() {
+			st.GetInto(keys[5], v)
+		})
+		ta.Equal(float64(0), allocs)
+	})
+
+	t.Run("string", func(t *testing.T) {
+		values := make([]string, len(keys))
+		for i := range values {
+			values[i] = keys[len(keys)-1-i]
+		}
+
+		st, err := NewSlimTrie(encode.StringVarint{}, keys, values)
+		ta.NoError(err)
+
+		s := new(string)
+		raw := new([]byte)
+		for i, key := range keys {
+			ta.True(st.GetInto(key, s), "GetInto:%v", key)
+			ta.Equal(values[i], *s, "GetInto:%v", key)
+
+			ta.True(st.GetInto(key, raw), "GetInto:%v", key)
+			ta.Equal(values[i], string(*raw), "GetInto:%v", key)
+		}
+
+		allocs := testing.AllocsPerRun(100, func() {
+			st.GetInto(keys[5], raw)
+		})
+		ta.Equal(float64(0), allocs)
+	})
+
+	t.Run("absent", func(t *testing.T) {
+		st, err := NewSlimTrie(encode.U32{}, []string{"a", "b"}, []uint32{1, 2})
+		ta.NoError(err)
+
+		v := uint32(7)
+		ta.False(st.GetInto("c", &v))
+		ta.Equal(uint32(7), v)
+	})
+}
+
+func BenchmarkSlimTrie_GetInto(b *testing.B) {
+
+	keys := getKeys("20kvl10")
+	values := make([]uint32, len(keys))
+
+	st, err := NewSlimTrie(encode.U32{}, keys, values)
+	if err != nil {
+		panic(err)
+	}
+
+	v := new(uint32)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		st.GetInto(keys[i%len(keys)], v)
+	}
+}
@@ -0,0 +1,152 @@
+package trie
+
+import (
+	"bytes"
+	"compress/flate"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/openacid/errors"
+	"github.com/openacid/low/pbcmpl"
+	"github.com/openacid/slim/encode"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+
+	ta := require.New(t)
+
+	keys := []string{"abc", "abcd", "abd", "abde", "bc", "bcd", "bcde", "cde"}
+
+	cases := []struct {
+		e      encode.Encoder
+		values interface{}
+	}{
+		{encode.I32{}, []int32{0, 1, 2, 3, 4, 5, 6, 7}},
+		{encode.StringVarint{}, []string{"a", "bb", "c", "dd", "e", "ff", "g", "hh"}},
+		{encode.Compressed(encode.String16{}, flate.BestSpeed),
+			[]string{"a", "bb", "c", "dd", "e", "ff", "g", "hh"}},
+		{encode.Proto(&wrappers.StringValue{}), []*wrappers.StringValue{
+			{Value: "a"}, {Value: "b"}, {Value: "c"}, {Value: "d"},
+			{Value: "e"}, {Value: "f"}, {Value: "g"}, {Value: "h"},
+		}},
+	}
+
+	for i, c := range cases {
+		st, err := NewSlimTrie(c.e, keys, c.values, Opt{Complete: Bool(true)})
+		ta.NoError(err, "%d-th", i+1)
+
+		buf, err := st.Marshal()
+		ta.NoError(err, "%d-th", i+1)
+
+		st2, err := Load(buf)
+		ta.NoError(err, "%d-th", i+1)
+
+		for _, k := range keys {
+			want, _ := st.Get(k)
+			got, found := st2.Get(k)
+			ta.True(found, "%d-th: key: %s", i+1, k)
+			if m, ok := want.(*wrappers.StringValue); ok {
+				ta.Equal(m.Value, got.(*wrappers.StringValue).Value, "%d-th: key: %s", i+1, k)
+			} else {
+				ta.Equal(want, got, "%d-th: key: %s", i+1, k)
+			}
+		}
+	}
+}
+
+func TestLoad_noValue(t *testing.T) {
+
+	ta := require.New(t)
+
+	st, err := NewSlimTrie(nil, []string{"a", "b"}, nil)
+	ta.NoError(err)
+
+	buf, err := st.Marshal()
+	ta.NoError(err)
+
+	st2, err := Load(buf)
+	ta.NoError(err)
+	ta.Equal(st.inner.String(), st2.inner.String())
+}
+
+// notDescribed is an encoder that can not be described thus is not recorded
+// in marshaled data.
+type notDescribed struct {
+	encode.I32
+}
+
+func (notDescribed) Describe() (string, []byte) { return "", nil }
+
+func TestLoad_unknownEncoder(t *testing.T) {
+
+	ta := require.New(t)
+
+	keys := []string{"a", "b"}
+	values := []int32{1, 2}
+
+	st, err := NewSlimTrie(notDescribed{}, keys, values)
+	ta.NoError(err)
+
+	buf, err := st.Marshal()
+	ta.NoError(err)
+
+	_, err = Load(buf)
+	ta.Equal(encode.ErrUnknownEncoder, errors.Cause(err))
+
+	// Unmarshal with a specified encoder still works.
+
+	st2, err := NewSlimTrie(notDescribed{}, nil, nil)
+	ta.NoError(err)
+	ta.NoError(st2.Unmarshal(buf))
+	v, found := st2.Get("b")
+	ta.True(found)
+	ta.Equal(int32(2), v)
+}
+
+func TestSlimTrie_Unmarshal_encoderMismatch(t *testing.T) {
+
+	ta := require.New(t)
+
+	st, err := NewSlimTrie(encode.I32{}, []string{"a", "b"}, []int32{1, 2})
+	ta.NoError(err)
+
+	buf, err := st.Marshal()
+	ta.NoError(err)
+
+	st2, err := NewSlimTrie(encode.I64{}, nil, nil)
+	ta.NoError(err)
+	err = st2.Unmarshal(buf)
+	ta.Equal(ErrEncoderMismatch, errors.Cause(err))
+}
+
+func TestSlimTrie_Unmarshal_0_5_12(t *testing.T) {
+
+	ta := require.New(t)
+
+	keys := []string{"abc", "abcd", "abd", "abde"}
+	values := []int32{0, 1, 2, 3}
+
+	st, err := NewSlimTrie(encode.I32{}, keys, values)
+	ta.NoError(err)
+
+	// 0.5.12 has only a Slim frame.
+	var b bytes.Buffer
+	_, err = pbcmpl.Marshal(&b, st.inner)
+	ta.NoError(err)
+	buf := b.Bytes()
+	copy(buf[:16], append([]byte("0.5.12"), make([]byte, 10)...))
+
+	_, err = Load(buf)
+	ta.Equal(encode.ErrUnknownEncoder, errors.Cause(err))
+
+	st2, err := NewSlimTrie(encode.I32{}, nil, nil)
+	ta.NoError(err)
+	ta.NoError(st2.Unmarshal(buf))
+
+	for i, k := range keys {
+		v, found := st2.Get(k)
+		ta.True(found)
+		ta.Equal(values[i], v)
+	}
+}
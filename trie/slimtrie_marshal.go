@@ -3,9 +3,7 @@ package trie
 import (
 	"bytes"
 	"encoding/binary"
-	fmt "fmt"
 	"math/bits"
-	"strings"
 
 	"github.com/openacid/errors"
 	"github.com/openacid/low/bitmap"
@@ -14,92 +12,183 @@ import (
 	"github.com/openacid/low/vers"
 	"github.com/openacid/must"
 	"github.com/openacid/slim/array"
+	"github.com/openacid/slim/encode"
 )
 
 // Marshal serializes it to byte stream.
 //
+// Since 0.5.13 it writes a SlimHeader before the Slim, which records the
+// name and params of the encoder if the encoder implements encode.Describer.
+// Thus Load can rebuild a SlimTrie without knowing the encoder.
+//
+// The SlimHeader also records CRC32C checksums of every section of the Slim,
+// which are verified by Unmarshal, the Opt used to create it, and Metadata.
+//
+// To write to a file without building the entire byte slice in memory, use
+// WriteTo.
+//
 // Since 0.4.3
 func (st *SlimTrie) Marshal() ([]byte, error) {
-	var buf []byte
-	writer := bytes.NewBuffer(buf)
 
-	_, err := pbcmpl.Marshal(writer, st.inner)
+	writer := bytes.NewBuffer(nil)
+	_, err := st.WriteTo(writer)
 	if err != nil {
-		return nil, errors.WithMessage(err, "failed to marshal st.inner")
+		return nil, err
 	}
 
 	return writer.Bytes(), nil
 }
 
-// Unmarshal a SlimTrie from a byte stream.
-//
-// Since 0.4.3
-func (st *SlimTrie) Unmarshal(buf []byte) error {
+// newHeader builds the SlimHeader to marshal.
+func (st *SlimTrie) newHeader() *SlimHeader {
+	h := &SlimHeader{
+		Opt:      newSlimOpt(st.opt),
+		Metadata: metadataEntries(st.Metadata),
+	}
 
-	st.inner = &Slim{}
+	if st.encoder != nil {
+		name, params, err := encode.Describe(st.encoder)
+		if err == nil {
+			h.Encoder = name
+			h.EncoderParams = params
+		}
+	}
 
-	reader := bytes.NewReader(buf)
+	return h
+}
 
-	_, h, err := pbcmpl.ReadHeader(reader)
+// Load creates a SlimTrie from a byte stream created by Marshal, with the
+// encoder recorded in it.
+//
+// If the recorded encoder is not registered, or no encoder is recorded while
+// there are values stored, it returns an error with cause
+// encode.ErrUnknownEncoder.
+// Data marshaled before 0.5.13 does not record an encoder, use Unmarshal with
+// a SlimTrie created with the right encoder instead.
+//
+// Since 0.5.13
+func Load(buf []byte) (*SlimTrie, error) {
+
+	_, h, err := pbcmpl.ReadHeader(bytes.NewReader(buf))
 	if err != nil {
-		return errors.WithMessage(err, "failed to unmarshal header")
+		return nil, errors.WithMessage(err, "failed to unmarshal header")
 	}
 
 	ver := h.GetVersion()
-	compatible := st.compatibleVersions()
+	if vers.IsCompatible(ver, []string{"<0.5.13"}) {
+		return nil, errors.Wrapf(encode.ErrUnknownEncoder,
+			"no encoder is recorded in version: %s", ver)
+	}
 
-	if !vers.IsCompatible(ver, compatible) {
-		return errors.Wrapf(ErrIncompatible,
-			fmt.Sprintf(`version: "%s", compatible versions:"%s"`,
-				ver,
-				strings.Join(compatible, " || ")))
+	st := &SlimTrie{}
+	err = st.Unmarshal(buf)
+	if err != nil {
+		return nil, err
 	}
 
-	reader = bytes.NewReader(buf)
+	if st.encoder == nil && st.inner.Leaves != nil {
+		return nil, errors.Wrapf(encode.ErrUnknownEncoder,
+			"no encoder is recorded for values")
+	}
 
-	// 0.5.10 and 0.5.11 share the same protobuf format:
+	return st, nil
+}
 
-	if vers.Check(ver, slimtrieVersion, "==0.5.10", "==0.5.11") {
-		_, _, err := pbcmpl.Unmarshal(reader, st.inner)
-		if err != nil {
-			return errors.WithMessage(err, "failed to unmarshal inner")
-		}
+// UnmarshalOpt specifies options for UnmarshalWithOpt.
+//
+// Since 0.5.13
+type UnmarshalOpt struct {
 
-		if vers.Check(ver, "<0.5.12") {
-			before000512InnerPrefixTobitstr(st)
-			before000512FixLeafSize(st)
-		}
+	// SkipChecksum disables verifying checksums recorded in marshaled data.
+	// It is for callers that already verify data integrity at a higher layer.
+	//
+	// Since 0.5.13
+	SkipChecksum bool
 
-		st.init()
-		return nil
-	}
+	// SkipValidate disables validating the structure of unmarshaled data with
+	// SlimTrie.Validate.
+	// Only skip it if the data is trusted. Querying a malformed SlimTrie may
+	// panic.
+	//
+	// Since 0.5.13
+	SkipValidate bool
+
+	// DeepValidate validates unmarshaled data with SlimTrie.ValidateDeep
+	// instead of SlimTrie.Validate, which also decodes every value.
+	// Rank and select indexes are always rebuilt from bitmaps when
+	// unmarshaling, whether it is set or not.
+	// It is much slower, use it only if the data is not protected by
+	// checksums. It is ignored if SkipValidate is set.
+	//
+	// Since 0.5.13
+	DeepValidate bool
+}
+
+// Unmarshal a SlimTrie from a byte stream.
+// It is the same as UnmarshalWithOpt with a zero UnmarshalOpt.
+//
+// Since 0.4.3
+func (st *SlimTrie) Unmarshal(buf []byte) error {
+	return st.UnmarshalWithOpt(buf, UnmarshalOpt{})
+}
 
-	// ver: "==1.0.0 || <0.5.10"
+// UnmarshalWithOpt unmarshals a SlimTrie from a byte stream with options.
+//
+// If checksums are recorded and one of them mismatches, it returns an error
+// with cause ErrCorrupted.
+// If the unmarshaled data does not pass SlimTrie.Validate, it returns an error
+// with cause ErrMalformed.
+//
+// Since 0.5.13
+func (st *SlimTrie) UnmarshalWithOpt(buf []byte, opt UnmarshalOpt) error {
+	// Records are decoded in place from a bytes.Buffer without being copied.
+	return st.read(bytes.NewBuffer(buf), opt)
+}
 
-	children := &array.Array32{}
-	steps := &array.U16{}
-	leaves := &array.Array{}
-	leaves.EltEncoder = st.encoder
+// recoverMalformed runs f and converts a panic into an error with cause
+// ErrMalformed.
+// It is used when converting data of old versions, which does not validate
+// its input.
+func recoverMalformed(f func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Wrapf(ErrMalformed, "failed to convert old version data: %v", r)
+		}
+	}()
 
-	_, _, err = pbcmpl.Unmarshal(reader, children)
-	if err != nil {
-		return errors.WithMessage(err, "failed to unmarshal children")
+	f()
+	return nil
+}
+
+// loadEncoder creates st.encoder from the recorded encoder in header if
+// st.encoder is nil.
+// Otherwise it checks if st.encoder is the same as the recorded one.
+func (st *SlimTrie) loadEncoder(h *SlimHeader) error {
+
+	if h.Encoder == "" {
+		return nil
 	}
 
-	_, _, err = pbcmpl.Unmarshal(reader, steps)
-	if err != nil {
-		return errors.WithMessage(err, "failed to unmarshal steps")
+	if st.encoder == nil {
+		e, err := encode.New(h.Encoder, h.EncoderParams)
+		if err != nil {
+			return err
+		}
+		st.encoder = e
+		return nil
 	}
 
-	_, _, err = pbcmpl.Unmarshal(reader, leaves)
+	name, params, err := encode.Describe(st.encoder)
 	if err != nil {
-		return errors.WithMessage(err, "failed to unmarshal leaves")
+		// Can not tell, trust the user.
+		return nil
 	}
 
-	// backward compatible:
-
-	before000510(st, ver, children, steps, leaves)
-
+	if name != h.Encoder || !bytes.Equal(params, h.EncoderParams) {
+		return errors.Wrapf(ErrEncoderMismatch,
+			"marshaled with: %s %x, unmarshal with: %s %x",
+			h.Encoder, h.EncoderParams, name, params)
+	}
 	return nil
 }
 
@@ -184,6 +273,9 @@ func (st *SlimTrie) Reset() {
 	st.inner = &Slim{}
 	st.vars = nil
 	st.levels = []levelInfo{{0, 0, 0, nil}}
+	st.opt = nil
+	st.fpr = nil
+	st.Metadata = nil
 }
 
 func before000510(st *SlimTrie, ver string, ch *array.Array32, steps *array.U16, lvs *array.Array) {
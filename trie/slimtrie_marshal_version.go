@@ -0,0 +1,507 @@
+package trie
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/bits"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/openacid/errors"
+	"github.com/openacid/low/bitmap"
+	"github.com/openacid/low/bitstr"
+	"github.com/openacid/low/vers"
+	"github.com/openacid/slim/array"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// marshalVersions are the versions MarshalVersion supports.
+var marshalVersions = []string{
+	">=0.5.1 <0.5.10",
+	"==0.5.10",
+	"==0.5.11",
+	"==0.5.12",
+	"==" + slimtrieVersion,
+}
+
+// MarshalVersion serializes it in the format of version ver, so that a reader
+// of an older version can load it, e.g., during a rolling upgrade.
+//
+// Supported versions are 0.5.1 through the current version.
+// Data of a version before 0.5.13 does not record the encoder, checksums, Opt
+// or Metadata.
+//
+// A SlimTrie created with inner prefix or leaf prefix, i.e., with
+// Opt.InnerPrefix, Opt.LeafPrefix or Opt.Complete, can not be marshaled to a
+// version before 0.5.10.
+// Before 0.5.12, only fixed-size values that present on every leaf are
+// supported.
+// If a feature can not be represented, it returns an error with cause
+// ErrUnrepresentable.
+// If ver is not supported, it returns an error with cause
+// ErrUnsupportedVersion.
+//
+// Since 0.5.13
+func (st *SlimTrie) MarshalVersion(ver string) ([]byte, error) {
+
+	if !vers.IsCompatible(ver, marshalVersions) {
+		return nil, errors.Wrapf(ErrUnsupportedVersion, "version: %q", ver)
+	}
+
+	if vers.Check(ver, slimtrieVersion) {
+		return st.Marshal()
+	}
+
+	var msgs []proto.Message
+	frameVer := ver
+
+	if vers.Check(ver, ">=0.5.10") {
+
+		ns, err := st.slimBefore000513(ver)
+		if err != nil {
+			return nil, err
+		}
+		msgs = []proto.Message{ns}
+
+	} else {
+
+		children, steps, leaves, err := st.arraysBefore000510(ver)
+		if err != nil {
+			return nil, err
+		}
+		msgs = []proto.Message{children, steps, leaves}
+
+		// From 0.5.8 it starts writing version to marshaled data.
+		if vers.Check(ver, "<0.5.8") {
+			frameVer = "1.0.0"
+		}
+	}
+
+	writer := bytes.NewBuffer(nil)
+
+	for _, msg := range msgs {
+		b, err := proto.Marshal(msg)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to marshal")
+		}
+
+		_, err = writeFrameHeader(writer, frameVer, int64(len(b)))
+		if err != nil {
+			return nil, err
+		}
+		writer.Write(b)
+	}
+
+	return writer.Bytes(), nil
+}
+
+// slimBefore000513 builds a Slim in the format of 0.5.10, 0.5.11 or 0.5.12.
+// The returned Slim shares data with st.inner thus it must not be modified.
+func (st *SlimTrie) slimBefore000513(ver string) (*Slim, error) {
+
+	ns := st.inner
+
+	if vers.Check(ver, "==0.5.12") {
+		return ns, nil
+	}
+
+	// 0.5.10 and 0.5.11
+
+	leaves, err := leavesBefore000512(ns.Leaves)
+	if err != nil {
+		return nil, err
+	}
+
+	ips := ns.InnerPrefixes
+	if ips != nil && ips.PositionBM != nil {
+		ips = proto.Clone(ips).(*VLenArray)
+		err := innerPrefixBefore000512(ips)
+		if err != nil {
+			return nil, err
+		}
+		selectIndexBefore000512(ips.PositionBM)
+	}
+
+	lps := ns.LeafPrefixes
+	if lps != nil && lps.PositionBM != nil {
+		lps = proto.Clone(lps).(*VLenArray)
+		selectIndexBefore000512(lps.PositionBM)
+	}
+
+	// Before 0.5.12 slimVars are stored in Slim, with field number 12, 13 and
+	// 15.
+	// A Slim with only XXX_unrecognized set is marshaled as is.
+
+	vars := st.vars
+	if ns.NodeTypeBM == nil {
+		// empty SlimTrie
+		vars = &slimVars{}
+	}
+
+	rawField := func(num protowire.Number, v uint64) *Slim {
+		if v == 0 {
+			return &Slim{}
+		}
+		b := protowire.AppendTag(nil, num, protowire.VarintType)
+		return &Slim{XXX_unrecognized: protowire.AppendVarint(b, v)}
+	}
+
+	sections := []proto.Message{
+		&Slim{BigInnerCnt: ns.BigInnerCnt},
+		rawField(12, uint64(int64(vars.BigInnerOffset))),
+		rawField(13, uint64(int64(vars.ShortMinusInner))),
+		&Slim{ShortSize: ns.ShortSize},
+		rawField(15, vars.ShortMask),
+		&Slim{NodeTypeBM: ns.NodeTypeBM},
+		&Slim{Inners: ns.Inners},
+		&Slim{ShortBM: ns.ShortBM},
+		&Slim{ShortTable: ns.ShortTable},
+		&Slim{InnerPrefixes: ips},
+		&Slim{LeafPrefixes: lps},
+		&Slim{Leaves: leaves},
+	}
+
+	var unrecognized []byte
+	for _, s := range sections {
+		b, err := proto.Marshal(s)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to marshal")
+		}
+		unrecognized = append(unrecognized, b...)
+	}
+
+	return &Slim{XXX_unrecognized: unrecognized}, nil
+}
+
+// leavesBefore000512 converts leaves to the format before 0.5.12, in which
+// only Bytes are stored and the size of a value is decided by the encoder.
+func leavesBefore000512(leaves *VLenArray) (*VLenArray, error) {
+
+	if leaves == nil {
+		return nil, nil
+	}
+
+	if leaves.PositionBM != nil || leaves.FixedSize == 0 {
+		return nil, errors.Wrapf(ErrUnrepresentable, "var-length values are not supported before 0.5.12")
+	}
+
+	if leaves.EltCnt != leaves.N {
+		return nil, errors.Wrapf(ErrUnrepresentable, "absent values are not supported before 0.5.12")
+	}
+
+	return &VLenArray{Bytes: leaves.Bytes}, nil
+}
+
+// innerPrefixBefore000512 converts inner prefixes from bitstr format to the
+// format before 0.5.12, which is a control byte followed by data bytes.
+// It is the reverse of before000512InnerPrefixTobitstr.
+func innerPrefixBefore000512(ips *VLenArray) error {
+
+	if len(ips.Bytes) == 0 {
+		return nil
+	}
+
+	pbm := ips.PositionBM
+
+	for i := int32(0); ; i++ {
+
+		from, to := bitmap.Select32R64(pbm.Words, pbm.SelectIndex, pbm.RankIndex, i)
+
+		pref := ips.Bytes[from:to]
+		bitLen := bitstr.Len(pref)
+
+		old := make([]byte, len(pref))
+		copy(old[1:], pref[:len(pref)-1])
+
+		if bitLen&7 != 0 {
+			// control byte
+			old[0] = 1
+			// append "1" after the prefix bits
+			old[len(old)-1] |= byte(0x80) >> uint(bitLen&7)
+		}
+
+		copy(pref, old)
+
+		if to == int32(len(ips.Bytes)) {
+			break
+		}
+	}
+
+	return nil
+}
+
+// selectIndexBefore000512 converts a select index to the format before
+// 0.5.12, in which an entry is the index of the word instead of the bit.
+func selectIndexBefore000512(pbm *Bitmap) {
+	for i, v := range pbm.SelectIndex {
+		pbm.SelectIndex[i] = v >> 6
+	}
+}
+
+// oldNode is a node in the format before 0.5.10, in which every inner node
+// has a 16-bit bitmap of 4-bit labels.
+type oldNode struct {
+	// nodeID is the id of the node in SlimTrie.
+	nodeID int32
+
+	// extraStep is the number of bits skipped by this node in addition to the
+	// prefix of the node.
+	extraStep int32
+
+	// hi is the higher 4 bits of labels of a big inner node if this node is
+	// split from a big inner node, otherwise it is -1.
+	hi int32
+}
+
+// arraysBefore000510 builds the children, steps and leaves arrays in the
+// format before 0.5.10.
+//
+// Before 0.5.10 every inner node has a 16-bit bitmap of 4-bit labels, and a
+// leaf is stored on its parent inner node if its label is empty.
+// A big inner node with 8-bit labels is split into 4-bit inner nodes.
+func (st *SlimTrie) arraysBefore000510(ver string) (*array.Array32, *array.Array32, *array.Array32, error) {
+
+	ns := st.inner
+
+	if ns.InnerPrefixes != nil && ns.InnerPrefixes.PositionBM != nil {
+		return nil, nil, nil, errors.Wrapf(ErrUnrepresentable, "inner prefix is not supported before 0.5.10")
+	}
+
+	if ns.LeafPrefixes != nil {
+		return nil, nil, nil, errors.Wrapf(ErrUnrepresentable, "leaf prefix is not supported before 0.5.10")
+	}
+
+	children := &array.Base{}
+	leaves := &array.Base{}
+
+	if ns.NodeTypeBM == nil {
+		// empty SlimTrie
+		if vers.Check(ver, ">=0.5.4 <0.5.7") {
+			// 0.5.4 to 0.5.6 always write the children bitmap.
+			children.Flags = array.ArrayFlagIsBitmap | 0x1
+			children.EltWidth = 16
+			children.BMElts = &array.Bits{RankIndex: bitmap.IndexRank128(nil)}
+		}
+		return &children.Array32, &array.Array32{}, &leaves.Array32, nil
+	}
+
+	var (
+		innerIDs []int32
+		bms      []uint16
+		// old id of the first child of every inner node
+		firsts   []int32
+		stepIDs  []int32
+		stepVals []uint16
+		leafIDs  []int32
+		leafVals [][]byte
+	)
+
+	addLeaf := func(oldID, nodeID int32) error {
+		ithLeaf, _ := st.getLeafIndex(nodeID)
+		v := st.getIthLeafBytes(ithLeaf)
+		if len(leafVals) > 0 && len(v) != len(leafVals[0]) {
+			return errors.Wrapf(ErrUnrepresentable, "var-length values are not supported before 0.5.12")
+		}
+		leafIDs = append(leafIDs, oldID)
+		leafVals = append(leafVals, v)
+		return nil
+	}
+
+	queue := []oldNode{{nodeID: 0, hi: -1}}
+	qr := &querySession{}
+
+	for oldID := int32(0); oldID < int32(len(queue)); oldID++ {
+
+		e := queue[oldID]
+
+		*qr = querySession{}
+		st.getNode(e.nodeID, qr)
+
+		if qr.isInner == 0 {
+			err := addLeaf(oldID, e.nodeID)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			continue
+		}
+
+		bm, size := st.getInnerBM(qr)
+		firstChild, _ := bitmap.Rank128(ns.Inners.Words, ns.Inners.RankIndex, qr.from)
+		firstChild++
+
+		// label bit position to child node id
+		labels := map[int32]int32{}
+		for i, b := range bitmap.ToArray(bm) {
+			if b >= size {
+				break
+			}
+			labels[b] = firstChild + int32(i)
+		}
+
+		step := qr.innerPrefixLen + e.extraStep
+
+		if leafID, ok := labels[0]; ok && e.hi == -1 {
+			err := addLeaf(oldID, leafID)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+		}
+
+		var bm16 uint16
+		var subs []oldNode
+
+		if size == innerSize {
+			for nibble := int32(0); nibble < 16; nibble++ {
+				if c, ok := labels[1+nibble]; ok {
+					bm16 |= 1 << uint(nibble)
+					subs = append(subs, oldNode{nodeID: c, hi: -1})
+				}
+			}
+		} else {
+
+			// big inner node: split 8-bit label into 2 4-bit labels.
+
+			groups := [16][]int32{}
+			nGroup := 0
+			for label := int32(1); label < bigInnerSize; label++ {
+				if _, ok := labels[label]; ok {
+					hi := (label - 1) >> 4
+					if len(groups[hi]) == 0 {
+						nGroup++
+					}
+					groups[hi] = append(groups[hi], label)
+				}
+			}
+
+			_, hasLeaf := labels[0]
+
+			if e.hi >= 0 || (nGroup == 1 && !hasLeaf) {
+
+				// An intermediate node of lower 4 bits, or a node that all
+				// labels share the same higher 4 bits.
+
+				hi := e.hi
+				if hi == -1 {
+					for h := range groups {
+						if len(groups[h]) > 0 {
+							hi = int32(h)
+						}
+					}
+					step += wordSize
+				} else {
+					step = 0
+				}
+
+				for _, label := range groups[hi] {
+					bm16 |= 1 << uint((label-1)&15)
+					subs = append(subs, oldNode{nodeID: labels[label], hi: -1})
+				}
+
+			} else {
+				for h, g := range groups {
+					if len(g) == 0 {
+						continue
+					}
+					bm16 |= 1 << uint(h)
+
+					if len(g) == 1 {
+						// skip the lower 4 bits
+						subs = append(subs, oldNode{nodeID: labels[g[0]], extraStep: wordSize, hi: -1})
+					} else {
+						subs = append(subs, oldNode{nodeID: e.nodeID, hi: int32(h)})
+					}
+				}
+			}
+		}
+
+		if step&(wordSize-1) != 0 {
+			return nil, nil, nil, errors.Wrapf(ErrUnrepresentable, "step: %d is not multiple of 4 bits", step)
+		}
+
+		if step > 0 {
+			// Before 0.5.10 step is in 4-bit, and includes the label.
+			s := step/wordSize + 1
+			if s > 0xffff {
+				return nil, nil, nil, errors.Wrapf(ErrUnrepresentable, "step: %d is too large", step)
+			}
+			stepIDs = append(stepIDs, oldID)
+			stepVals = append(stepVals, uint16(s))
+		}
+
+		if vers.Check(ver, "<0.5.4") && len(queue) > 0xffff {
+			return nil, nil, nil, errors.Wrapf(ErrUnrepresentable, "too many nodes for version: %s", ver)
+		}
+
+		innerIDs = append(innerIDs, oldID)
+		bms = append(bms, bm16)
+		firsts = append(firsts, int32(len(queue)))
+		queue = append(queue, subs...)
+	}
+
+	steps, err := array.NewU16(stepIDs, stepVals)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	err = leaves.InitIndex(leafIDs)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	for _, v := range leafVals {
+		leaves.Elts = append(leaves.Elts, v...)
+	}
+
+	err = children.InitIndex(innerIDs)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if vers.Check(ver, "==0.5.9") {
+		// The index of children and steps built by 0.5.9 covers all nodes.
+		padIndex(&children.Array32, len(queue))
+		padIndex(&steps.Array32, len(queue))
+	}
+
+	if vers.Check(ver, "<0.5.4") {
+
+		// Before 0.5.4 an element is a uint32 with the bitmap in the lower 16
+		// bits and the id of the first child in the higher 16 bits.
+
+		children.Elts = make([]byte, 4*len(bms))
+		for i, bm := range bms {
+			binary.LittleEndian.PutUint32(children.Elts[i*4:], uint32(bm)|uint32(firsts[i])<<16)
+		}
+
+	} else {
+
+		// Since 0.5.4 elements are bitmaps stored in BMElts.
+
+		words := make([]uint64, (len(bms)+3)/4)
+		n := int32(0)
+		for i, bm := range bms {
+			words[i>>2] |= uint64(bm) << uint(i&3*16)
+			if bm != 0 {
+				n = int32(i*16 + bits.Len16(bm))
+			}
+		}
+
+		// 0x1 is always set along with ArrayFlagIsBitmap since 0.5.4.
+		children.Flags = array.ArrayFlagIsBitmap | 0x1
+		children.EltWidth = 16
+		children.BMElts = &array.Bits{
+			N:         n,
+			Words:     words,
+			RankIndex: bitmap.IndexRank128(words),
+		}
+	}
+
+	return &children.Array32, &steps.Array32, &leaves.Array32, nil
+}
+
+// padIndex appends zero bitmap words and offsets to the index of a, until it
+// covers n elements.
+func padIndex(a *array.Array32, n int) {
+	for len(a.Bitmaps) < (n+63)/64 {
+		a.Bitmaps = append(a.Bitmaps, 0)
+		a.Offsets = append(a.Offsets, 0)
+	}
+}
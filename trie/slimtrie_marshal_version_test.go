@@ -0,0 +1,117 @@
+package trie
+
+import (
+	"testing"
+
+	"github.com/openacid/errors"
+	"github.com/openacid/low/vers"
+	"github.com/openacid/slim/encode"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlimTrie_MarshalVersion_old_data(t *testing.T) {
+
+	testOldData(t,
+		func(t *testing.T,
+			dataSetName, dataOpt, ver string,
+			keys []string,
+			buf []byte) {
+
+			if ver == "0.5.0" {
+				// 0.5.0 is not supported
+				return
+			}
+
+			ta := require.New(t)
+
+			opt := Opt{}
+			switch dataOpt {
+			case "innpref":
+				opt.InnerPrefix = Bool(true)
+			case "allpref":
+				opt.Complete = Bool(true)
+			}
+
+			values := makeI32s(len(keys))
+			st, err := NewSlimTrie(encode.I32{}, keys, values, opt)
+			ta.NoError(err)
+
+			got, err := st.MarshalVersion(ver)
+			ta.NoError(err)
+			ta.Equal(buf, got, "output should be the same as written by %s", ver)
+
+			st2, err := NewSlimTrie(encode.I32{}, nil, nil)
+			ta.NoError(err)
+			err = st2.Unmarshal(got)
+			ta.NoError(err)
+
+			testPresentKeysGRS(t, st2, keys, values)
+		})
+}
+
+func TestSlimTrie_MarshalVersion(t *testing.T) {
+
+	ta := require.New(t)
+
+	keys := getKeys("50kl10")[:5000]
+	values := makeI32s(len(keys))
+
+	for _, opt := range []Opt{{}, {InnerPrefix: Bool(true)}, {Complete: Bool(true)}} {
+
+		st, err := NewSlimTrie(encode.I32{}, keys, values, opt)
+		ta.NoError(err)
+
+		for _, ver := range []string{"0.5.1", "0.5.4", "0.5.9", "0.5.10", "0.5.11", "0.5.12", slimtrieVersion} {
+
+			buf, err := st.MarshalVersion(ver)
+			withPrefix := opt.InnerPrefix != nil || opt.Complete != nil
+			if withPrefix && vers.Check(ver, "<0.5.10") {
+				// prefixes can not be represented before 0.5.10
+				ta.Equal(ErrUnrepresentable, errors.Cause(err), "ver: %s", ver)
+				continue
+			}
+			ta.NoError(err, "ver: %s", ver)
+
+			st2, err := NewSlimTrie(encode.I32{}, nil, nil)
+			ta.NoError(err)
+			err = st2.Unmarshal(buf)
+			ta.NoError(err, "ver: %s", ver)
+
+			testPresentKeysGRS(t, st2, keys, values)
+		}
+	}
+
+	want, err := NewSlimTrie(encode.I32{}, keys, values)
+	ta.NoError(err)
+	b, err := want.Marshal()
+	ta.NoError(err)
+	got, err := want.MarshalVersion(slimtrieVersion)
+	ta.NoError(err)
+	ta.Equal(b, got)
+}
+
+func TestSlimTrie_MarshalVersion_error(t *testing.T) {
+
+	ta := require.New(t)
+
+	st, err := NewSlimTrie(encode.I32{}, []string{"a", "b"}, []int32{1, 2})
+	ta.NoError(err)
+
+	for _, ver := range []string{"0.5.0", "0.4.3", "9.9.9", "foo"} {
+		_, err := st.MarshalVersion(ver)
+		ta.Equal(ErrUnsupportedVersion, errors.Cause(err), "ver: %s", ver)
+	}
+
+	// var-length values
+
+	st, err = NewSlimTrie(encode.String16{}, []string{"a", "b"}, []string{"x", "yy"})
+	ta.NoError(err)
+
+	for _, ver := range []string{"0.5.9", "0.5.10", "0.5.11"} {
+		_, err := st.MarshalVersion(ver)
+		ta.Equal(ErrUnrepresentable, errors.Cause(err), "ver: %s", ver)
+	}
+
+	_, err = st.MarshalVersion("0.5.12")
+	ta.NoError(err)
+}
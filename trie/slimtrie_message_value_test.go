@@ -0,0 +1,130 @@
+package trie
+
+import (
+	"reflect"
+	"testing"
+
+	proto "github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/openacid/slim/encode"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlimTrie_protoValue(t *testing.T) {
+
+	ta := require.New(t)
+
+	keys := []string{"Aaron", "Agatha", "Al", "Albert", "Alexander"}
+	values := []*wrappers.StringValue{}
+	for _, k := range keys {
+		values = append(values, &wrappers.StringValue{Value: "name:" + k})
+	}
+
+	e := encode.Proto(&wrappers.StringValue{})
+
+	st, err := NewSlimTrie(e, keys, values, Opt{Complete: Bool(true)})
+	ta.NoError(err)
+
+	buf, err := st.Marshal()
+	ta.NoError(err)
+
+	st2, err := NewSlimTrie(e, nil, nil)
+	ta.NoError(err)
+	ta.NoError(st2.Unmarshal(buf))
+
+	for _, s := range []*SlimTrie{st, st2} {
+		for i, key := range keys {
+			v, found := s.Get(key)
+			ta.True(found, "Get:%v", key)
+			ta.True(proto.Equal(values[i], v.(*wrappers.StringValue)), "Get:%v", key)
+
+			_, eq, _ := s.Search(key)
+			ta.True(proto.Equal(values[i], eq.(*wrappers.StringValue)), "Search:%v", key)
+		}
+
+		i := 0
+		s.ScanFrom("", true, true, func(k, v []byte) bool {
+			_, m := e.Decode(v)
+			ta.True(proto.Equal(values[i], m.(proto.Message)), "Scan:%s", k)
+			i++
+			return true
+		})
+		ta.Equal(len(keys), i)
+	}
+}
+
+func TestSlimTrie_jsonGobValue(t *testing.T) {
+
+	ta := require.New(t)
+
+	type rec struct {
+		Name string
+		Age  int
+	}
+
+	keys := []string{"Aaron", "Agatha", "Al", "Albert"}
+	values := []rec{{"a", 1}, {"b", 2}, {"c", 3}, {"d", 4}}
+
+	for _, e := range []encode.Encoder{
+		encode.JSON(reflect.TypeOf(rec{})),
+		encode.Gob(reflect.TypeOf(rec{})),
+	} {
+		st, err := NewSlimTrie(e, keys, values, Opt{Complete: Bool(true)})
+		ta.NoError(err)
+
+		for i, key := range keys {
+			v, found := st.Get(key)
+			ta.True(found, "Get:%v", key)
+			ta.Equal(values[i], v, "Get:%v", key)
+		}
+	}
+}
+
+func TestSlimTrie_structValue(t *testing.T) {
+
+	ta := require.New(t)
+
+	type rec struct {
+		Name string
+		Age  int32
+		Tags []uint16
+	}
+
+	keys := []string{"Aaron", "Agatha", "Al", "Albert"}
+	values := []rec{
+		{"a", 1, []uint16{}},
+		{"bb", 2, []uint16{1}},
+		{"", 3, []uint16{2, 3}},
+		{"dddd", 4, []uint16{}},
+	}
+
+	e, err := encode.NewStructEncoder(rec{})
+	ta.NoError(err)
+
+	st, err := NewSlimTrie(e, keys, values, Opt{Complete: Bool(true)})
+	ta.NoError(err)
+
+	buf, err := st.Marshal()
+	ta.NoError(err)
+
+	st2, err := NewSlimTrie(e, nil, nil)
+	ta.NoError(err)
+	ta.NoError(st2.Unmarshal(buf))
+
+	for _, s := range []*SlimTrie{st, st2} {
+		for i, key := range keys {
+			v, found := s.Get(key)
+			ta.True(found, "Get:%v", key)
+			ta.Equal(values[i], v, "Get:%v", key)
+		}
+
+		i := 0
+		s.ScanFrom("", true, true, func(k, v []byte) bool {
+			_, x := e.Decode(v)
+			ta.Equal(values[i], x, "Scan:%s", k)
+			i++
+			return true
+		})
+		ta.Equal(len(keys), i)
+	}
+}
@@ -0,0 +1,146 @@
+package trie
+
+import (
+	"io"
+	"sort"
+
+	"github.com/openacid/errors"
+	"github.com/openacid/low/pbcmpl"
+	"github.com/openacid/low/vers"
+)
+
+// Opt returns the normalized options used to create it.
+// Options that are not specified when creating are filled with default values.
+//
+// If it is loaded from data of a version before 0.5.13, which does not record
+// the options, it returns a zero Opt.
+//
+// Since 0.5.13
+func (st *SlimTrie) Opt() Opt {
+	if st.opt == nil {
+		return Opt{}
+	}
+
+	return *newSlimOpt(st.opt).toOpt()
+}
+
+// ReadMetadata reads the Metadata of a SlimTrie written by Marshal or WriteTo,
+// without reading the rest of the SlimTrie.
+// Thus it is cheap to read only the metadata from the beginning of a file.
+//
+// It is the same as ReadHeader except it does not return Opt.
+//
+// Since 0.5.13
+func ReadMetadata(r io.Reader) (map[string][]byte, error) {
+	_, md, err := ReadHeader(r)
+	return md, err
+}
+
+// ReadHeader reads the Opt and the Metadata of a SlimTrie written by Marshal
+// or WriteTo, without reading the rest of the SlimTrie.
+// The Opt is the same as SlimTrie.Opt returns after loading it.
+//
+// Data of a version before 0.5.13 has neither Opt nor metadata, in which case
+// it returns a zero Opt and a nil map.
+// If the data is not a SlimTrie of a compatible version, it returns an error
+// with cause ErrIncompatible.
+//
+// Since 0.5.13
+func ReadHeader(r io.Reader) (Opt, map[string][]byte, error) {
+
+	_, h, err := pbcmpl.ReadHeader(r)
+	if err != nil {
+		return Opt{}, nil, errors.WithMessage(err, "failed to unmarshal header")
+	}
+
+	ver := h.GetVersion()
+	err = (&SlimTrie{}).checkCompatible(ver)
+	if err != nil {
+		return Opt{}, nil, err
+	}
+
+	if !vers.Check(ver, slimtrieVersion) {
+		return Opt{}, nil, nil
+	}
+
+	sh := &SlimHeader{}
+	err = readBody(r, h, sh)
+	if err != nil {
+		return Opt{}, nil, errors.WithMessage(err, "failed to unmarshal header")
+	}
+
+	opt := Opt{}
+	if o := sh.Opt.toOpt(); o != nil {
+		opt = *o
+	}
+
+	return opt, metadataMap(sh.Metadata), nil
+}
+
+// newSlimOpt converts a normalized Opt to SlimOpt.
+// It returns nil if o is nil.
+func newSlimOpt(o *Opt) *SlimOpt {
+	if o == nil {
+		return nil
+	}
+
+	return &SlimOpt{
+		DedupValue:  o.DedupValue != nil && *o.DedupValue,
+		InnerPrefix: o.InnerPrefix != nil && *o.InnerPrefix,
+		LeafPrefix:  o.LeafPrefix != nil && *o.LeafPrefix,
+		Complete:    o.Complete != nil && *o.Complete,
+	}
+}
+
+// toOpt converts SlimOpt back to Opt.
+// It returns nil if so is nil.
+func (so *SlimOpt) toOpt() *Opt {
+	if so == nil {
+		return nil
+	}
+
+	return &Opt{
+		DedupValue:  Bool(so.DedupValue),
+		InnerPrefix: Bool(so.InnerPrefix),
+		LeafPrefix:  Bool(so.LeafPrefix),
+		Complete:    Bool(so.Complete),
+	}
+}
+
+// metadataEntries converts metadata to entries sorted by key, so that the
+// marshaled data is deterministic.
+func metadataEntries(md map[string][]byte) []*MetadataEntry {
+
+	if len(md) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(md))
+	for k := range md {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	entries := make([]*MetadataEntry, 0, len(keys))
+	for _, k := range keys {
+		entries = append(entries, &MetadataEntry{Key: k, Value: md[k]})
+	}
+
+	return entries
+}
+
+// metadataMap converts entries back to a map.
+// It returns nil if there is no entry.
+func metadataMap(entries []*MetadataEntry) map[string][]byte {
+
+	if len(entries) == 0 {
+		return nil
+	}
+
+	md := make(map[string][]byte, len(entries))
+	for _, e := range entries {
+		md[e.Key] = e.Value
+	}
+
+	return md
+}
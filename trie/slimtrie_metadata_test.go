@@ -0,0 +1,160 @@
+package trie
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/openacid/errors"
+	"github.com/openacid/low/pbcmpl"
+	"github.com/openacid/slim/encode"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlimTrie_Metadata(t *testing.T) {
+
+	ta := require.New(t)
+
+	keys := []string{"abc", "abcd", "abd", "abde", "bc", "bcd", "bcde", "cde"}
+	values := makeI32s(len(keys))
+
+	st, err := NewSlimTrie(encode.I32{}, keys, values, Opt{InnerPrefix: Bool(true)})
+	ta.NoError(err)
+
+	ta.Equal(Opt{
+		DedupValue:  Bool(true),
+		InnerPrefix: Bool(true),
+		LeafPrefix:  Bool(false),
+		Complete:    Bool(false),
+	}, st.Opt())
+
+	md := map[string][]byte{
+		"source":     []byte("s3://bucket/keys.csv"),
+		"build-time": []byte("2020-02-02T02:02:02Z"),
+		"empty":      {},
+	}
+	st.Metadata = md
+
+	buf, err := st.Marshal()
+	ta.NoError(err)
+
+	// marshal again with a new map built in another order
+
+	md2 := map[string][]byte{}
+	for _, k := range []string{"empty", "source", "build-time"} {
+		md2[k] = md[k]
+	}
+	st.Metadata = md2
+	buf2, err := st.Marshal()
+	ta.NoError(err)
+	ta.Equal(buf, buf2, "output should not depend on map order")
+
+	got := &SlimTrie{}
+	err = got.Unmarshal(buf)
+	ta.NoError(err)
+
+	ta.Equal(st.Opt(), got.Opt())
+	ta.Equal(3, len(got.Metadata))
+	for k, v := range md {
+		ta.Equal(string(v), string(got.Metadata[k]), "key: %s", k)
+	}
+	testPresentKeysGRS(t, got, keys, values)
+
+	// ReadMetadata reads only the header
+
+	r := bytes.NewReader(buf)
+	gotMD, err := ReadMetadata(r)
+	ta.NoError(err)
+	ta.Equal(got.Metadata, gotMD)
+
+	_, h, err := pbcmpl.ReadHeader(r)
+	ta.NoError(err)
+	ta.Equal(slimtrieVersion, h.GetVersion(), "the Slim frame should follow")
+	ta.Equal(int64(r.Len()), h.GetBodySize())
+
+	// ReadHeader reads Opt too
+
+	r = bytes.NewReader(buf)
+	gotOpt, gotMD, err := ReadHeader(r)
+	ta.NoError(err)
+	ta.Equal(st.Opt(), gotOpt)
+	ta.Equal(got.Metadata, gotMD)
+
+	_, h, err = pbcmpl.ReadHeader(r)
+	ta.NoError(err)
+	ta.Equal(slimtrieVersion, h.GetVersion(), "the Slim frame should follow")
+}
+
+func TestSlimTrie_Metadata_absent(t *testing.T) {
+
+	ta := require.New(t)
+
+	st, err := NewSlimTrie(encode.I32{}, []string{"a", "b"}, []int32{1, 2})
+	ta.NoError(err)
+
+	buf, err := st.Marshal()
+	ta.NoError(err)
+
+	md, err := ReadMetadata(bytes.NewReader(buf))
+	ta.NoError(err)
+	ta.Nil(md)
+
+	// Unmarshal resets metadata
+
+	got := &SlimTrie{Metadata: map[string][]byte{"foo": []byte("bar")}}
+	err = got.Unmarshal(buf)
+	ta.NoError(err)
+	ta.Nil(got.Metadata)
+
+	// old data has neither metadata nor Opt
+
+	for _, ver := range []string{"0.5.9", "0.5.10"} {
+		fn := "testdata/slimtrie-data-10vl5-" + ver
+		if ver == "0.5.10" {
+			fn = "testdata/slimtrie-data-10vl5-nopref-" + ver
+		}
+		b, err := ioutil.ReadFile(fn)
+		ta.NoError(err)
+
+		md, err := ReadMetadata(bytes.NewReader(b))
+		ta.NoError(err, "ver: %s", ver)
+		ta.Nil(md, "ver: %s", ver)
+
+		opt, md, err := ReadHeader(bytes.NewReader(b))
+		ta.NoError(err, "ver: %s", ver)
+		ta.Equal(Opt{}, opt, "ver: %s", ver)
+		ta.Nil(md, "ver: %s", ver)
+
+		st, err := NewSlimTrie(encode.I32{}, nil, nil)
+		ta.NoError(err)
+		err = st.Unmarshal(b)
+		ta.NoError(err, "ver: %s", ver)
+		ta.Equal(Opt{}, st.Opt(), "ver: %s", ver)
+	}
+}
+
+func TestReadMetadata_error(t *testing.T) {
+
+	ta := require.New(t)
+
+	_, err := ReadMetadata(bytes.NewReader([]byte("foo")))
+	ta.Error(err)
+
+	st, err := NewSlimTrie(encode.I32{}, []string{"a", "b"}, []int32{1, 2})
+	ta.NoError(err)
+
+	buf, err := st.Marshal()
+	ta.NoError(err)
+
+	// clear buf for version
+	for i := 0; i < 16; i++ {
+		buf[i] = 0
+	}
+	copy(buf, []byte("0.6.0"))
+
+	_, err = ReadMetadata(bytes.NewReader(buf))
+	ta.Equal(ErrIncompatible, errors.Cause(err))
+
+	_, _, err = ReadHeader(bytes.NewReader(buf))
+	ta.Equal(ErrIncompatible, errors.Cause(err))
+}
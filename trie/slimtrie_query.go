@@ -7,6 +7,7 @@ import (
 	"github.com/openacid/low/bitmap"
 	"github.com/openacid/low/bitstr"
 	"github.com/openacid/low/bmtree"
+	"github.com/openacid/slim/encode"
 )
 
 type querySession struct {
@@ -61,6 +62,35 @@ func (st *SlimTrie) Get(key string) (interface{}, bool) {
 	return v, true
 }
 
+// GetInto is same as Get() except it stores the value into "dst", which is a
+// pointer to the value type, e.g., a *uint32 for values encoded by
+// encode.U32.
+// If the key is not found, "dst" is not changed.
+//
+// It involves 0 alloc if decoding does not allocate, e.g., with a fixed-size
+// integer encoder, a varint encoder, or a string encoder into a *[]byte.
+// See encode.AppendDecoder.
+//
+// Since 0.5.13
+func (st *SlimTrie) GetInto(key string, dst interface{}) bool {
+
+	eqID := st.GetID(key)
+
+	if eqID == -1 {
+		return false
+	}
+
+	leafI, _ := st.getLeafIndex(eqID)
+
+	ls := st.inner.Leaves
+	if ls == nil {
+		return true
+	}
+
+	encode.DecodeInto(st.encoder, ls.get(leafI), dst)
+	return true
+}
+
 // RangeGet look for a range that contains a key in SlimTrie.
 //
 // A range that contains a key means range-start <= key <= range-end.
@@ -566,17 +596,12 @@ func (st *SlimTrie) getIthLeafBytes(ith int32) []byte {
 		return nil
 	}
 
-	// TODO use FixedSize or bitmap for var-len leaves
-	// TODO it is possible there is a absent leaf
-	size := st.encoder.GetEncodedSize(nil)
-	idx := ith * int32(size)
-
-	return ls.Bytes[idx : idx+int32(size)]
+	return ls.get(ith)
 }
 
 func (st *SlimTrie) getLabels(qr *querySession) []uint64 {
-	bm, _ := st.getInnerBM(qr)
-	return bmtree.Decode(qr.to-qr.from, bm)
+	bm, size := st.getInnerBM(qr)
+	return bmtree.Decode(size, bm)
 }
 
 // getInnerBM retrieves the inner node bitmap cached by a querySession, and the size of bitmap.
@@ -587,7 +612,7 @@ func (st *SlimTrie) getInnerBM(qr *querySession) ([]uint64, int32) {
 	storedBMSize := qr.to - qr.from
 
 	if storedBMSize == ns.ShortSize {
-		return bmtree.Decode(innerSize, []uint64{qr.bm}), innerSize
+		return []uint64{qr.bm}, innerSize
 	}
 
 	// normal or big inner node
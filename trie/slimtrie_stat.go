@@ -7,6 +7,12 @@ type Stat struct {
 	}
 	KeyCnt  int32
 	NodeCnt int32
+
+	// ExpectedFPR is the false positive rate of Get estimated from the
+	// structure, see SlimTrie.ExpectedFPR.
+	//
+	// Since 0.5.13
+	ExpectedFPR float64
 }
 
 // Stat() returns a struct `Stat` describing SlimTrie internal stats. E.g.:
@@ -20,10 +26,14 @@ type Stat struct {
 //	        {Total:8, Inner:6, Leaf:2},
 //	        {Total:14, Inner:6, Leaf:8},
 //	    },
-//	    KeyCnt:  8,
-//	    NodeCnt: 14,
+//	    KeyCnt:      8,
+//	    NodeCnt:     14,
+//	    ExpectedFPR: 0.0750732421875,
 //	}
 //
+// Since 0.5.13 it walks through every node to estimate ExpectedFPR the first
+// time it is called, see SlimTrie.ExpectedFPR.
+//
 // Since 0.5.12
 func (st *SlimTrie) Stat() *Stat {
 
@@ -48,6 +58,7 @@ func (st *SlimTrie) Stat() *Stat {
 	}
 
 	rst.NodeCnt = st.levels[level_cnt-1].total
+	rst.ExpectedFPR = st.ExpectedFPR()
 
 	return rst
 }
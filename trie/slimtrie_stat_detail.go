@@ -0,0 +1,210 @@
+package trie
+
+import (
+	"math/bits"
+
+	"github.com/openacid/low/bitmap"
+)
+
+// Histogram maps a value to the number of its occurrences.
+//
+// Since 0.5.13
+type Histogram map[int32]int32
+
+// ComponentStat is the memory usage of a component of SlimTrie.
+//
+// Since 0.5.13
+type ComponentStat struct {
+	// Name is the name of the field in Slim, such as "Inners".
+	Name string
+
+	// Bytes is the size of data: bitmap words, the short table, or bytes of
+	// prefixes or values.
+	Bytes int64
+
+	// IndexBytes is the size of rank and select indexes of the bitmaps of
+	// this component, which can be rebuilt from data.
+	IndexBytes int64
+}
+
+// StatDetail describes memory usage and the structure of SlimTrie in detail,
+// to find out why a SlimTrie is bigger than expected.
+//
+// Since 0.5.13
+type StatDetail struct {
+	Stat
+
+	// Components is the memory usage of every component, in the order of
+	// NodeTypeBM, Inners, ShortBM, ShortTable, InnerPrefixes, LeafPrefixes
+	// and Leaves.
+	Components []ComponentStat
+
+	// TotalBytes is the sum of Bytes and IndexBytes of all components.
+	TotalBytes int64
+
+	// BitsPerKey is TotalBytes*8 / KeyCnt.
+	BitsPerKey float64
+
+	// InnerCnt is the number of inner nodes, which is BigInnerCnt +
+	// ShortInnerCnt + NormalInnerCnt.
+	InnerCnt int32
+
+	// BigInnerCnt is the number of inner nodes with 8-bit labels.
+	BigInnerCnt int32
+
+	// ShortInnerCnt is the number of inner nodes with 4-bit labels that are
+	// stored as an index into ShortTable.
+	ShortInnerCnt int32
+
+	// NormalInnerCnt is the number of inner nodes with 4-bit labels that are
+	// not short.
+	NormalInnerCnt int32
+
+	// ShortSize is the number of bits of a short inner node.
+	ShortSize int32
+
+	// ShortHitRatio is the ratio of 4-bit inner nodes that are short:
+	// ShortInnerCnt / (ShortInnerCnt + NormalInnerCnt).
+	ShortHitRatio float64
+
+	// FanOut is the histogram of the number of children of inner nodes.
+	FanOut Histogram
+
+	// InnerPrefixBits is the histogram of the length in bit of prefixes of
+	// inner nodes that have a prefix.
+	// Without Opt.InnerPrefix, only the length of a prefix is stored.
+	InnerPrefixBits Histogram
+
+	// LeafPrefixBytes is the histogram of the length in byte of stored leaf
+	// prefixes.
+	LeafPrefixBytes Histogram
+
+	// ValueBytes is the histogram of the size in byte of encoded values.
+	ValueBytes Histogram
+}
+
+// StatDetail returns memory usage by component and histograms of the
+// structure. It walks through every node thus it is much slower than Stat.
+//
+// Since 0.5.13
+func (st *SlimTrie) StatDetail() *StatDetail {
+
+	ns := st.inner
+
+	d := &StatDetail{
+		Stat:            *st.Stat(),
+		BigInnerCnt:     ns.BigInnerCnt,
+		ShortSize:       ns.ShortSize,
+		FanOut:          Histogram{},
+		InnerPrefixBits: Histogram{},
+		LeafPrefixBytes: Histogram{},
+		ValueBytes:      Histogram{},
+	}
+
+	d.Components = []ComponentStat{
+		bitmapStat("NodeTypeBM", ns.NodeTypeBM),
+		bitmapStat("Inners", ns.Inners),
+		bitmapStat("ShortBM", ns.ShortBM),
+		{Name: "ShortTable", Bytes: int64(len(ns.ShortTable)) * 4},
+		vlenArrayStat("InnerPrefixes", ns.InnerPrefixes),
+		vlenArrayStat("LeafPrefixes", ns.LeafPrefixes),
+		vlenArrayStat("Leaves", ns.Leaves),
+	}
+
+	for _, c := range d.Components {
+		d.TotalBytes += c.Bytes + c.IndexBytes
+	}
+
+	if d.KeyCnt > 0 {
+		d.BitsPerKey = float64(d.TotalBytes) * 8 / float64(d.KeyCnt)
+	}
+
+	// empty SlimTrie
+	if ns.NodeTypeBM == nil {
+		return d
+	}
+
+	qr := &querySession{}
+	for _, nid := range bitmap.ToArray(ns.NodeTypeBM.Words) {
+		*qr = querySession{}
+		st.getNode(nid, qr)
+
+		d.InnerCnt++
+		if qr.ithInner >= ns.BigInnerCnt {
+			if qr.to-qr.from == ns.ShortSize {
+				d.ShortInnerCnt++
+			} else {
+				d.NormalInnerCnt++
+			}
+		}
+
+		d.FanOut[st.childCnt(qr)]++
+
+		if qr.innerPrefixLen > 0 {
+			d.InnerPrefixBits[qr.innerPrefixLen]++
+		}
+	}
+
+	if d.ShortInnerCnt+d.NormalInnerCnt > 0 {
+		d.ShortHitRatio = float64(d.ShortInnerCnt) / float64(d.ShortInnerCnt+d.NormalInnerCnt)
+	}
+
+	// N is not set for LeafPrefixes, the lengths are found from positions.
+	if lp := ns.LeafPrefixes; lp != nil && lp.PositionBM != nil {
+		pos := bitmap.ToArray(lp.PositionBM.Words)
+		for i := 1; i < len(pos); i++ {
+			d.LeafPrefixBytes[pos[i]-pos[i-1]]++
+		}
+	}
+
+	if ls := ns.Leaves; ls != nil {
+		for i := int32(0); i < ls.N; i++ {
+			d.ValueBytes[int32(len(ls.get(i)))]++
+		}
+	}
+
+	return d
+}
+
+// childCnt returns the number of children of the inner node loaded in qr.
+func (st *SlimTrie) childCnt(qr *querySession) int32 {
+
+	if qr.to-qr.from == st.inner.ShortSize {
+		return int32(bits.OnesCount64(qr.bm))
+	}
+
+	n := 0
+	for _, w := range bitmap.Slice(st.inner.Inners.Words, qr.from, qr.to) {
+		n += bits.OnesCount64(w)
+	}
+	return int32(n)
+}
+
+func bitmapBytes(b *Bitmap) (int64, int64) {
+	if b == nil {
+		return 0, 0
+	}
+	return int64(len(b.Words)) * 8, int64(len(b.RankIndex)+len(b.SelectIndex)) * 4
+}
+
+func bitmapStat(name string, b *Bitmap) ComponentStat {
+	data, index := bitmapBytes(b)
+	return ComponentStat{Name: name, Bytes: data, IndexBytes: index}
+}
+
+func vlenArrayStat(name string, va *VLenArray) ComponentStat {
+
+	c := ComponentStat{Name: name}
+	if va == nil {
+		return c
+	}
+
+	c.Bytes = int64(len(va.Bytes))
+	for _, b := range []*Bitmap{va.PresenceBM, va.PositionBM} {
+		data, index := bitmapBytes(b)
+		c.Bytes += data
+		c.IndexBytes += index
+	}
+
+	return c
+}
@@ -0,0 +1,109 @@
+package trie
+
+import (
+	"testing"
+
+	"github.com/openacid/slim/benchhelper"
+	"github.com/openacid/slim/encode"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlimTrie_StatDetail(t *testing.T) {
+
+	ta := require.New(t)
+
+	c := statCases["simple"]
+	values := makeI32s(len(c.keys))
+
+	st, err := NewSlimTrie(encode.I32{}, c.keys, values, Opt{Complete: Bool(true)})
+	ta.NoError(err)
+
+	d := st.StatDetail()
+
+	ta.Equal(*st.Stat(), d.Stat)
+	ta.Equal([]ComponentStat{
+		{Name: "NodeTypeBM", Bytes: 8, IndexBytes: 4},
+		{Name: "Inners", Bytes: 16, IndexBytes: 8},
+		{Name: "ShortBM", Bytes: 8, IndexBytes: 4},
+		{Name: "ShortTable", Bytes: 4, IndexBytes: 0},
+		{Name: "InnerPrefixes", Bytes: 25, IndexBytes: 16},
+		{Name: "LeafPrefixes", Bytes: 21, IndexBytes: 16},
+		{Name: "Leaves", Bytes: 40, IndexBytes: 4},
+	}, d.Components)
+	ta.Equal(int64(174), d.TotalBytes)
+	ta.Equal(float64(174), d.BitsPerKey)
+
+	ta.Equal(int32(6), d.InnerCnt)
+	ta.Equal(int32(6), d.NormalInnerCnt)
+
+	// see statCases["simple"].slimStr
+	ta.Equal(Histogram{2: 5, 3: 1}, d.FanOut)
+	ta.Equal(Histogram{4: 1, 8: 2, 12: 1}, d.InnerPrefixBits)
+	ta.Equal(Histogram{1: 3, 2: 1}, d.LeafPrefixBytes)
+	ta.Equal(Histogram{4: 8}, d.ValueBytes)
+
+	t.Run("empty", func(t *testing.T) {
+		ta := require.New(t)
+
+		st, err := NewSlimTrie(encode.I32{}, nil, nil)
+		ta.NoError(err)
+
+		d := st.StatDetail()
+		ta.Equal(int64(0), d.TotalBytes)
+		ta.Equal(float64(0), d.BitsPerKey)
+		ta.Equal(int32(0), d.InnerCnt)
+		ta.Equal(Histogram{}, d.FanOut)
+	})
+}
+
+func TestSlimTrie_StatDetail_random(t *testing.T) {
+
+	ta := require.New(t)
+
+	for _, n := range []int{1, 100, 5000} {
+
+		keys := benchhelper.RandSortedStrings(n, 20, nil)
+		values := make([]string, n)
+		for i := range values {
+			values[i] = keys[i][:i%7]
+		}
+
+		for _, opt := range []Opt{{}, {Complete: Bool(true)}} {
+
+			st, err := NewSlimTrie(encode.String16{}, keys, values, opt)
+			ta.NoError(err)
+
+			d := st.StatDetail()
+			last := d.Levels[d.LevelCnt-1]
+
+			ta.Equal(last.Inner, d.InnerCnt)
+			ta.Equal(d.InnerCnt, d.BigInnerCnt+d.ShortInnerCnt+d.NormalInnerCnt)
+			if d.ShortInnerCnt > 0 {
+				ta.True(d.ShortHitRatio > 0 && d.ShortHitRatio <= 1)
+			}
+
+			// every node except the root has a parent
+			fanIn, inners := int32(0), int32(0)
+			for k, v := range d.FanOut {
+				fanIn += k * v
+				inners += v
+			}
+			ta.Equal(d.InnerCnt, inners)
+			if d.InnerCnt > 0 {
+				ta.Equal(d.NodeCnt-1, fanIn)
+			}
+
+			leaves := int32(0)
+			for _, v := range d.ValueBytes {
+				leaves += v
+			}
+			ta.Equal(d.KeyCnt, leaves)
+
+			total := int64(0)
+			for _, c := range d.Components {
+				total += c.Bytes + c.IndexBytes
+			}
+			ta.Equal(total, d.TotalBytes)
+		}
+	}
+}
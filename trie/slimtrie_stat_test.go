@@ -11,7 +11,12 @@ import (
 type statCase struct {
 	keys    []string
 	slimStr string
-	stat    string
+
+	// stat is of a SlimTrie created with Opt.Complete.
+	stat string
+
+	// minimalStat is of a SlimTrie created with default Opt.
+	minimalStat string
 }
 
 var statCases = map[string]statCase{
@@ -24,8 +29,20 @@ var statCases = map[string]statCase{
     Levels:   {
         {},
     },
-    KeyCnt:  0,
-    NodeCnt: 0,
+    KeyCnt:      0,
+    NodeCnt:     0,
+    ExpectedFPR: 0,
+}
+`),
+		minimalStat: trim(`
+&trie.Stat{
+    LevelCnt: 1,
+    Levels:   {
+        {},
+    },
+    KeyCnt:      0,
+    NodeCnt:     0,
+    ExpectedFPR: 0,
 }
 `),
 	},
@@ -39,8 +56,21 @@ var statCases = map[string]statCase{
         {},
         {Total:1, Inner:0, Leaf:1},
     },
-    KeyCnt:  1,
-    NodeCnt: 1,
+    KeyCnt:      1,
+    NodeCnt:     1,
+    ExpectedFPR: 0,
+}
+`),
+		minimalStat: trim(`
+&trie.Stat{
+    LevelCnt: 2,
+    Levels:   {
+        {},
+        {Total:1, Inner:0, Leaf:1},
+    },
+    KeyCnt:      1,
+    NodeCnt:     1,
+    ExpectedFPR: 1,
 }
 `),
 	},
@@ -81,8 +111,24 @@ var statCases = map[string]statCase{
         {Total:8, Inner:6, Leaf:2},
         {Total:14, Inner:6, Leaf:8},
     },
-    KeyCnt:  8,
-    NodeCnt: 14,
+    KeyCnt:      8,
+    NodeCnt:     14,
+    ExpectedFPR: 0,
+}
+`),
+		minimalStat: trim(`
+&trie.Stat{
+    LevelCnt: 5,
+    Levels:   {
+        {},
+        {Total:1, Inner:1, Leaf:0},
+        {Total:4, Inner:3, Leaf:1},
+        {Total:8, Inner:6, Leaf:2},
+        {Total:14, Inner:6, Leaf:8},
+    },
+    KeyCnt:      8,
+    NodeCnt:     14,
+    ExpectedFPR: 0.0750732421875,
 }
 `),
 	},
@@ -114,7 +160,7 @@ func TestSlimTrie_Stat(t *testing.T) {
 				st, err := NewSlimTrie(encode.I32{}, c.keys, values)
 				ta.NoError(err)
 
-				ta.Equal(c.stat, pretty.Sprint(st.Stat()))
+				ta.Equal(c.minimalStat, pretty.Sprint(st.Stat()))
 			})
 		})
 	}
@@ -76,3 +76,79 @@ func TestSlimTrie_String(t *testing.T) {
 
 	testPresentKeysGet(t, st, keys, values)
 }
+
+func TestSlimTrie_String_shortInner(t *testing.T) {
+
+	ta := require.New(t)
+
+	// 16 inner nodes with the same 2 labels "0001" and "0010" are stored as
+	// short inner nodes.
+	keys := []string{}
+	for c := byte('a'); c < 'a'+16; c++ {
+		keys = append(keys, string([]byte{c, 'a'}), string([]byte{c, 'b'}))
+	}
+	values := makeI32s(len(keys))
+	st, err := NewSlimTrie(encode.I32{}, keys, values, Opt{Complete: Bool(true)})
+	ta.NoError(err)
+
+	ta.Equal(int32(2), st.inner.ShortSize)
+	ta.Equal(int32(16), st.StatDetail().ShortInnerCnt)
+
+	// Labels of a short inner node used to be decoded twice and were printed
+	// as "-0->" and "-1->".
+	want := trim(`
+#000*16
+    -01100001->#001+4*2
+                   -0001->#017=0
+                   -0010->#018=1
+    -01100010->#002+4*2
+                   -0001->#019=2
+                   -0010->#020=3
+    -01100011->#003+4*2
+                   -0001->#021=4
+                   -0010->#022=5
+    -01100100->#004+4*2
+                   -0001->#023=6
+                   -0010->#024=7
+    -01100101->#005+4*2
+                   -0001->#025=8
+                   -0010->#026=9
+    -01100110->#006+4*2
+                   -0001->#027=10
+                   -0010->#028=11
+    -01100111->#007+4*2
+                   -0001->#029=12
+                   -0010->#030=13
+    -01101000->#008+4*2
+                   -0001->#031=14
+                   -0010->#032=15
+    -01101001->#009+4*2
+                   -0001->#033=16
+                   -0010->#034=17
+    -01101010->#010+4*2
+                   -0001->#035=18
+                   -0010->#036=19
+    -01101011->#011+4*2
+                   -0001->#037=20
+                   -0010->#038=21
+    -01101100->#012+4*2
+                   -0001->#039=22
+                   -0010->#040=23
+    -01101101->#013+4*2
+                   -0001->#041=24
+                   -0010->#042=25
+    -01101110->#014+4*2
+                   -0001->#043=26
+                   -0010->#044=27
+    -01101111->#015+4*2
+                   -0001->#045=28
+                   -0010->#046=29
+    -01110000->#016+4*2
+                   -0001->#047=30
+                   -0010->#048=31
+`)
+
+	ta.Equal(want, st.String())
+
+	testPresentKeysGet(t, st, keys, values)
+}
@@ -0,0 +1,419 @@
+package trie
+
+import (
+	"bytes"
+	"encoding/binary"
+	fmt "fmt"
+	"io"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/openacid/errors"
+	"github.com/openacid/low/pbcmpl"
+	"github.com/openacid/low/vers"
+	"github.com/openacid/slim/array"
+	"github.com/openacid/slim/internal/pbstream"
+)
+
+// WriteTo writes the SlimTrie to w section by section.
+// The output is the same as Marshal, but the entire marshaled Slim is never
+// built in memory.
+//
+// Since the SlimHeader, which records checksums and the size of Slim, is
+// written first, Slim is encoded twice: the first time only to calculate
+// checksums and size. Neither of them buffers a section in memory, large
+// fields such as Leaves.Bytes are written directly.
+//
+// It implements io.WriterTo.
+//
+// Since 0.5.13
+func (st *SlimTrie) WriteTo(w io.Writer) (int64, error) {
+	return st.writeTo(w, st.inner, st.newHeader())
+}
+
+// writeTo writes header h and ns to w, after filling checksums of h and ns in
+// h.
+func (st *SlimTrie) writeTo(w io.Writer, ns *Slim, h *SlimHeader) (int64, error) {
+
+	h.Checksums = nil
+	hb, err := proto.Marshal(h)
+	if err != nil {
+		return 0, errors.WithMessage(err, "failed to checksum header")
+	}
+
+	hh := newHeaderHasher()
+	err = hh.addRecords(hb)
+	if err != nil {
+		return 0, errors.WithMessage(err, "failed to checksum header")
+	}
+
+	sh := newSectionHasher()
+	bodySize, err := pbstream.Write(io.Discard, ns, sh.writer)
+	if err != nil {
+		return 0, errors.WithMessage(err, "failed to checksum st.inner")
+	}
+
+	h.Checksums = append(hh.sums, sh.sums...)
+
+	n, err := pbcmpl.Marshal(w, h)
+	if err != nil {
+		return n, errors.WithMessage(err, "failed to marshal header")
+	}
+
+	nn, err := writeFrameHeader(w, ns.GetVersion(), bodySize)
+	n += nn
+	if err != nil {
+		return n, errors.WithMessage(err, "failed to marshal st.inner")
+	}
+
+	nn, err = pbstream.Write(w, ns, nil)
+	n += nn
+	if err != nil {
+		return n, errors.WithMessage(err, "failed to marshal st.inner")
+	}
+
+	return n, nil
+}
+
+// ReadFrom reads a SlimTrie from r section by section.
+// It reads exactly the bytes written by WriteTo or Marshal, thus r can be used
+// to read other data after a SlimTrie.
+//
+// The entire marshaled Slim is never loaded in memory, except for data of
+// versions before 0.5.13.
+// Checksums and structure are verified the same way as Unmarshal does.
+//
+// It implements io.ReaderFrom.
+//
+// Since 0.5.13
+func (st *SlimTrie) ReadFrom(r io.Reader) (int64, error) {
+	return st.readFrom(r, UnmarshalOpt{})
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+// It is the same as Marshal.
+//
+// Since 0.5.13
+func (st *SlimTrie) MarshalBinary() ([]byte, error) {
+	return st.Marshal()
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+// It is the same as Unmarshal.
+// If st has no encoder, the encoder recorded in data is used, as Load does.
+//
+// Since 0.5.13
+func (st *SlimTrie) UnmarshalBinary(data []byte) error {
+	return st.Unmarshal(data)
+}
+
+// frameHeader has the same layout as the header of a pbcmpl frame.
+type frameHeader struct {
+	Version    [16]byte
+	HeaderSize uint64
+	BodySize   uint64
+}
+
+// writeFrameHeader writes the header of a pbcmpl frame, whose body of size
+// bodySize is written later.
+func writeFrameHeader(w io.Writer, ver string, bodySize int64) (int64, error) {
+
+	fh := &frameHeader{BodySize: uint64(bodySize)}
+	fh.HeaderSize = uint64(binary.Size(fh))
+	copy(fh.Version[:], ver)
+
+	b := bytes.NewBuffer(make([]byte, 0, fh.HeaderSize))
+	err := binary.Write(b, binary.LittleEndian, fh)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+
+	n, err := w.Write(b.Bytes())
+	return int64(n), errors.WithStack(err)
+}
+
+// readFrom reads a SlimTrie from r and returns the number of bytes read.
+func (st *SlimTrie) readFrom(r io.Reader, opt UnmarshalOpt) (int64, error) {
+
+	cr := &countingReader{r: r}
+	err := st.read(cr, opt)
+	return cr.n, err
+}
+
+func (st *SlimTrie) read(r io.Reader, opt UnmarshalOpt) error {
+
+	st.inner = &Slim{}
+	st.opt = nil
+	st.fpr = nil
+	st.Metadata = nil
+
+	_, h, err := pbcmpl.ReadHeader(r)
+	if err != nil {
+		return errors.WithMessage(err, "failed to unmarshal header")
+	}
+
+	ver := h.GetVersion()
+	err = st.checkCompatible(ver)
+	if err != nil {
+		return err
+	}
+
+	if vers.Check(ver, slimtrieVersion) {
+
+		// Since 0.5.13 a SlimHeader is written before Slim:
+
+		sh := &SlimHeader{}
+		hh := newHeaderHasher()
+		err := readHeaderBody(r, h, sh, hh)
+		if err != nil {
+			return errors.WithMessage(err, "failed to unmarshal header")
+		}
+
+		err = st.readSlim(r, sh, hh, opt)
+		if err != nil {
+			return err
+		}
+
+		if sh.Compact {
+			err = st.inner.uncompact()
+			if err != nil {
+				return err
+			}
+		}
+
+		// Marshaled rank and select indexes are not trusted: an index that
+		// does not match its bitmap still passes Validate and makes querying
+		// panic. Rebuilding them is cheap compared with decoding.
+		st.inner.indexit()
+
+		err = st.loadEncoder(sh)
+		if err != nil {
+			return err
+		}
+
+		st.opt = sh.Opt.toOpt()
+		st.Metadata = metadataMap(sh.Metadata)
+
+	} else if vers.Check(ver, "==0.5.10", "==0.5.11", "==0.5.12") {
+
+		// 0.5.10, 0.5.11 and 0.5.12 share the same protobuf format:
+
+		err := readBody(r, h, st.inner)
+		if err != nil {
+			return errors.WithMessage(err, "failed to unmarshal inner")
+		}
+
+		err = recoverMalformed(func() {
+			if vers.Check(ver, "<0.5.12") {
+				before000512InnerPrefixTobitstr(st)
+				before000512FixLeafSize(st)
+			}
+
+			// Indexes built by old versions may differ from the current ones.
+			st.inner.indexit()
+		})
+		if err != nil {
+			return err
+		}
+
+	} else {
+
+		// ver: "==1.0.0 || <0.5.10"
+
+		children := &array.Array32{}
+		steps := &array.U16{}
+		leaves := &array.Array{}
+		leaves.EltEncoder = st.encoder
+
+		err = readBody(r, h, children)
+		if err != nil {
+			return errors.WithMessage(err, "failed to unmarshal children")
+		}
+
+		err = readFrame(r, steps)
+		if err != nil {
+			return errors.WithMessage(err, "failed to unmarshal steps")
+		}
+
+		err = readFrame(r, leaves)
+		if err != nil {
+			return errors.WithMessage(err, "failed to unmarshal leaves")
+		}
+
+		// backward compatible:
+
+		err = recoverMalformed(func() {
+			before000510(st, ver, children, steps, leaves)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if !opt.SkipValidate {
+		err = st.validate(opt.DeepValidate)
+		if err != nil {
+			return err
+		}
+	}
+
+	st.init()
+	return nil
+}
+
+// checkCompatible returns an error with cause ErrIncompatible if data of
+// version ver can not be read.
+func (st *SlimTrie) checkCompatible(ver string) error {
+
+	compatible := st.compatibleVersions()
+
+	if !vers.IsCompatible(ver, compatible) {
+		return errors.Wrapf(ErrIncompatible,
+			fmt.Sprintf(`version: "%s", compatible versions:"%s"`,
+				ver,
+				strings.Join(compatible, " || ")))
+	}
+
+	return nil
+}
+
+// readSlim reads the Slim frame record by record and verifies checksums
+// recorded in h, including the checksums of h itself hashed by hh.
+func (st *SlimTrie) readSlim(r io.Reader, h *SlimHeader, hh *sectionHasher, opt UnmarshalOpt) error {
+
+	_, fh, err := pbcmpl.ReadHeader(r)
+	if err != nil {
+		return errors.WithMessage(err, "failed to read inner")
+	}
+
+	bodySize, err := frameBodySize(fh)
+	if err != nil {
+		return errors.WithMessage(err, "failed to read inner")
+	}
+
+	// Every header since 0.5.13 records checksums. An empty list is not
+	// trusted, otherwise removing it would disable verification.
+	verify := !opt.SkipChecksum
+	if verify && len(h.Checksums) == 0 {
+		return errors.Wrapf(ErrCorrupted, "no checksum in header")
+	}
+	sh := newSectionHasher()
+
+	// A corrupted record may fail to unmarshal. The error is returned after
+	// checksums are verified, to report it as corrupted.
+	var unmarshalErr error
+
+	rr := pbstream.NewReader(r, bodySize)
+	for {
+		num, rec, err := rr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if errors.Cause(err) == pbstream.ErrInvalidRecord {
+				return errors.Wrapf(ErrCorrupted, "%v", err)
+			}
+			return errors.WithMessage(err, "failed to read inner")
+		}
+
+		if verify {
+			sh.add(num, rec)
+		}
+
+		if unmarshalErr == nil {
+			unmarshalErr = proto.UnmarshalMerge(rec, st.inner)
+		}
+	}
+
+	if verify {
+		err = verifyChecksums(append(hh.sums, sh.sums...), h.Checksums)
+		if err != nil {
+			return err
+		}
+	}
+
+	if unmarshalErr != nil {
+		return errors.WithMessage(errors.WithStack(unmarshalErr), "failed to unmarshal inner")
+	}
+
+	return nil
+}
+
+// readFrame reads a pbcmpl frame from r into msg.
+// Unlike pbcmpl.Unmarshal it never allocates more than the size of data
+// actually read for a malformed frame.
+func readFrame(r io.Reader, msg proto.Message) error {
+
+	_, h, err := pbcmpl.ReadHeader(r)
+	if err != nil {
+		return err
+	}
+
+	return readBody(r, h, msg)
+}
+
+// readHeaderBody reads the body of the SlimHeader frame, whose frame header is
+// h, into sh, and hashes the records of it with hh.
+func readHeaderBody(r io.Reader, h pbcmpl.Header, sh *SlimHeader, hh *sectionHasher) error {
+
+	bodySize, err := frameBodySize(h)
+	if err != nil {
+		return err
+	}
+
+	body, err := pbstream.ReadN(r, bodySize)
+	if err != nil {
+		return err
+	}
+
+	err = hh.addRecords(body)
+	if err != nil {
+		return err
+	}
+
+	return errors.WithStack(proto.Unmarshal(body, sh))
+}
+
+// readBody reads the body of a pbcmpl frame, whose header is h, into msg.
+func readBody(r io.Reader, h pbcmpl.Header, msg proto.Message) error {
+
+	bodySize, err := frameBodySize(h)
+	if err != nil {
+		return err
+	}
+
+	body, err := pbstream.ReadN(r, bodySize)
+	if err != nil {
+		return err
+	}
+
+	return errors.WithStack(proto.Unmarshal(body, msg))
+}
+
+// frameBodySize checks the header of a pbcmpl frame and returns the size of
+// the body.
+func frameBodySize(h pbcmpl.Header) (int64, error) {
+
+	if h.GetHeaderSize() != int64(binary.Size(&frameHeader{})) {
+		return 0, errors.Wrapf(pbcmpl.ErrInvalidHeaderSize, "header size: %d", h.GetHeaderSize())
+	}
+
+	bodySize := h.GetBodySize()
+	if bodySize < 0 {
+		return 0, errors.Wrapf(io.ErrUnexpectedEOF, "body size: %d", bodySize)
+	}
+
+	return bodySize, nil
+}
+
+// countingReader counts the bytes read from r.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n += int64(n)
+	return n, err
+}
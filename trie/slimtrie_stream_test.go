@@ -0,0 +1,214 @@
+package trie
+
+import (
+	"bytes"
+	"encoding/gob"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	"github.com/openacid/errors"
+	"github.com/openacid/slim/encode"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlimTrie_WriteTo(t *testing.T) {
+
+	ta := require.New(t)
+
+	keySets := map[string][]string{"50kl10": getKeys("50kl10")[:5000]}
+	for name, c := range iterCases {
+		keySets[name] = c.keys
+	}
+
+	for typ, keys := range keySets {
+
+		values := makeI32s(len(keys))
+
+		for _, opt := range []Opt{{}, {Complete: Bool(true)}, {InnerPrefix: Bool(true)}} {
+
+			st, err := NewSlimTrie(encode.I32{}, keys, values, opt)
+			ta.NoError(err, "%s", typ)
+
+			want, err := st.Marshal()
+			ta.NoError(err, "%s", typ)
+
+			w := bytes.NewBuffer(nil)
+			n, err := st.WriteTo(w)
+			ta.NoError(err, "%s", typ)
+			ta.Equal(int64(len(want)), n, "%s", typ)
+			ta.Equal(want, w.Bytes(), "%s", typ)
+		}
+	}
+}
+
+func TestSlimTrie_WriteTo_largeLeaves(t *testing.T) {
+
+	ta := require.New(t)
+
+	// Leaves.Bytes is large enough to be written without buffering.
+	keys := getKeys("50kl10")[:5000]
+
+	st, err := NewSlimTrie(encode.String16{}, keys, keys)
+	ta.NoError(err)
+	ta.Greater(len(st.inner.Leaves.Bytes), 32<<10)
+
+	want, err := st.Marshal()
+	ta.NoError(err)
+
+	w := bytes.NewBuffer(nil)
+	n, err := st.WriteTo(w)
+	ta.NoError(err)
+	ta.Equal(int64(len(want)), n)
+	ta.Equal(want, w.Bytes())
+
+	// Unmarshal decodes records in place, the result must not refer to buf.
+
+	buf := w.Bytes()
+	got := &SlimTrie{}
+	ta.NoError(got.Unmarshal(buf))
+
+	for i := range buf {
+		buf[i] = 0
+	}
+
+	for _, k := range keys {
+		v, found := got.Get(k)
+		ta.True(found)
+		ta.Equal(k, v)
+	}
+}
+
+// failWriter fails after n bytes are written.
+type failWriter struct {
+	n int
+}
+
+func (w *failWriter) Write(p []byte) (int, error) {
+	if len(p) > w.n {
+		n := w.n
+		w.n = 0
+		return n, io.ErrShortWrite
+	}
+	w.n -= len(p)
+	return len(p), nil
+}
+
+func TestSlimTrie_WriteTo_error(t *testing.T) {
+
+	ta := require.New(t)
+
+	st, buf, _ := newChecksumTestTrie(t)
+
+	for _, size := range []int{0, 10, 40, 100, len(buf) - 1} {
+		n, err := st.WriteTo(&failWriter{n: size})
+		ta.Equal(io.ErrShortWrite, errors.Cause(err), "size: %d", size)
+		ta.Equal(int64(size), n, "size: %d", size)
+	}
+}
+
+func TestSlimTrie_ReadFrom(t *testing.T) {
+
+	ta := require.New(t)
+
+	keys := getKeys("50kl10")[:1000]
+	values := makeI32s(len(keys))
+
+	st, err := NewSlimTrie(encode.I32{}, keys, values, Opt{Complete: Bool(true)})
+	ta.NoError(err)
+
+	st2, err := NewSlimTrie(encode.I32{}, keys[:10], values[:10], Opt{Complete: Bool(true)})
+	ta.NoError(err)
+
+	// Two tries followed by other data
+
+	w := bytes.NewBuffer(nil)
+	n1, err := st.WriteTo(w)
+	ta.NoError(err)
+	n2, err := st2.WriteTo(w)
+	ta.NoError(err)
+	w.WriteString("foo")
+
+	// Read with short reads to make sure no extra bytes are read.
+	r := iotest.OneByteReader(bytes.NewReader(w.Bytes()))
+
+	got := &SlimTrie{}
+	n, err := got.ReadFrom(r)
+	ta.NoError(err)
+	ta.Equal(n1, n)
+
+	got2 := &SlimTrie{}
+	n, err = got2.ReadFrom(r)
+	ta.NoError(err)
+	ta.Equal(n2, n)
+
+	rest, err := io.ReadAll(r)
+	ta.NoError(err)
+	ta.Equal("foo", string(rest))
+
+	for i, k := range keys {
+		v, found := got.Get(k)
+		ta.True(found)
+		ta.Equal(values[i], v)
+
+		v, found = got2.Get(k)
+		ta.Equal(i < 10, found, "key: %s", k)
+		if found {
+			ta.Equal(values[i], v)
+		}
+	}
+}
+
+func TestSlimTrie_ReadFrom_corrupted(t *testing.T) {
+
+	ta := require.New(t)
+
+	_, buf, bodyOffset := newChecksumTestTrie(t)
+
+	for i := bodyOffset; i < len(buf); i += 7 {
+		bad := append([]byte{}, buf...)
+		bad[i] ^= 0x10
+
+		st2 := &SlimTrie{encoder: encode.I32{}}
+		_, err := st2.ReadFrom(bytes.NewReader(bad))
+		ta.Equal(ErrCorrupted, errors.Cause(err), "flip byte at: %d", i)
+	}
+
+	for _, l := range []int{0, 10, bodyOffset - 1, bodyOffset + 10, len(buf) - 1} {
+		st2 := &SlimTrie{encoder: encode.I32{}}
+		n, err := st2.ReadFrom(bytes.NewReader(buf[:l]))
+		ta.Error(err, "truncated at: %d", l)
+		ta.Equal(int64(l), n, "truncated at: %d", l)
+	}
+}
+
+func TestSlimTrie_gob(t *testing.T) {
+
+	ta := require.New(t)
+
+	keys := []string{"abc", "abcd", "abd", "abde", "bc", "bcd", "bcde", "cde"}
+	values := []string{"a", "bb", "c", "dd", "e", "ff", "g", "hh"}
+
+	st, err := NewSlimTrie(encode.StringVarint{}, keys, values)
+	ta.NoError(err)
+
+	type doc struct {
+		Name  string
+		Index *SlimTrie
+	}
+
+	buf := bytes.NewBuffer(nil)
+	err = gob.NewEncoder(buf).Encode(&doc{Name: "foo", Index: st})
+	ta.NoError(err)
+
+	got := &doc{}
+	err = gob.NewDecoder(buf).Decode(got)
+	ta.NoError(err)
+
+	ta.Equal("foo", got.Name)
+	for i, k := range keys {
+		v, found := got.Index.Get(k)
+		ta.True(found)
+		ta.Equal(values[i], v)
+	}
+}
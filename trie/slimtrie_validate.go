@@ -0,0 +1,484 @@
+package trie
+
+import (
+	"math"
+	"math/bits"
+
+	"github.com/openacid/errors"
+	"github.com/openacid/slim/encode"
+)
+
+// Validate checks the consistency of the internal structure of a SlimTrie,
+// so that querying it does not go out of bound.
+// Unmarshal calls it by default, since marshaled data could be crafted or
+// truncated.
+//
+// It checks:
+//
+//   - Every bitmap is long enough for the nodes it describes, and its rank or
+//     select index has the expected length and every entry is in bound.
+//   - The size of Inners matches BigInnerCnt, ShortBM and ShortSize.
+//   - ShortTable is large enough for every short inner node, and a short
+//     bitmap maps to a full bitmap with the same number of "1".
+//   - Every inner node has at least one child and a child id is greater than
+//     the parent id.
+//   - Positions of elements in a VLenArray are in bound of its Bytes.
+//   - The number of leaves matches Leaves and LeafPrefixes.
+//
+// It does not check if an index matches its bitmap, nor decode values, see
+// ValidateDeep. Unmarshal always rebuilds indexes from bitmaps before
+// validating, thus indexes of an unmarshaled SlimTrie always match.
+//
+// It returns an error with cause ErrMalformed that tells which component is
+// malformed.
+//
+// Since 0.5.13
+func (st *SlimTrie) Validate() error {
+	return st.validate(false)
+}
+
+// ValidateDeep does what Validate does, and also checks:
+//
+//   - The rank or select index of every bitmap is the same as the one rebuilt
+//     from it.
+//   - Every leaf value can be decoded by the encoder, if there is one.
+//
+// It is much slower than Validate and allocates memory for rebuilding indexes.
+// UnmarshalWithOpt calls it instead of Validate if UnmarshalOpt.DeepValidate
+// is set.
+//
+// Since 0.5.13
+func (st *SlimTrie) ValidateDeep() error {
+	return st.validate(true)
+}
+
+func (st *SlimTrie) validate(deep bool) error {
+
+	ns := st.inner
+	if ns == nil {
+		return errors.Wrapf(ErrMalformed, "no inner data")
+	}
+
+	// ShortSize is used even if there is no short inner node.
+	if ns.ShortSize < 0 || ns.ShortSize > maxShortSize {
+		return errors.Wrapf(ErrMalformed, "ShortSize: %d", ns.ShortSize)
+	}
+
+	if ns.NodeTypeBM == nil {
+		// empty slim
+		if ns.Leaves != nil && ns.Leaves.N != 0 {
+			return errors.Wrapf(ErrMalformed, "Leaves: N: %d, no node", ns.Leaves.N)
+		}
+		return nil
+	}
+
+	innerCnt, nodeCnt, err := validateNodes(ns, deep)
+	if err != nil {
+		return err
+	}
+
+	leafCnt := nodeCnt - innerCnt
+
+	if ns.LeafPrefixes != nil {
+		cnt, err := validateVLenArray("LeafPrefixes", ns.LeafPrefixes, leafCnt, "r64", deep)
+		if err != nil {
+			return err
+		}
+
+		// Querying a leaf prefix always uses PositionBM.
+		if cnt > 0 && ns.LeafPrefixes.PositionBM == nil {
+			return errors.Wrapf(ErrMalformed, "LeafPrefixes.PositionBM: nil")
+		}
+	}
+
+	ls := ns.Leaves
+	if ls != nil {
+		if ls.N != leafCnt {
+			return errors.Wrapf(ErrMalformed, "Leaves: N: %d, leaf count: %d", ls.N, leafCnt)
+		}
+
+		cnt, err := validateVLenArray("Leaves", ls, ls.N, "r64", deep)
+		if err != nil {
+			return err
+		}
+
+		if ls.EltCnt != cnt {
+			return errors.Wrapf(ErrMalformed, "Leaves: EltCnt: %d, present: %d", ls.EltCnt, cnt)
+		}
+
+		if deep && st.encoder != nil {
+			for i := int32(0); i < leafCnt; i++ {
+				err := validateValue(st.encoder, ls.get(i))
+				if err != nil {
+					return errors.Wrapf(ErrMalformed, "Leaves: %d-th value: %v", i, err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateNodes checks NodeTypeBM, Inners, ShortBM, ShortTable and
+// InnerPrefixes.
+// It returns the number of inner nodes and the number of all nodes.
+func validateNodes(ns *Slim, deep bool) (int32, int32, error) {
+
+	ntbm := ns.NodeTypeBM
+
+	innerCnt64 := onesCount(ntbm.Words, 0, int64(len(ntbm.Words))*64)
+	if innerCnt64 > math.MaxInt32 {
+		return 0, 0, errors.Wrapf(ErrMalformed, "NodeTypeBM: too many inner nodes: %d", innerCnt64)
+	}
+	innerCnt := int32(innerCnt64)
+
+	if innerCnt == 0 {
+		// only one leaf
+		err := validateBitmap("NodeTypeBM", ntbm, 1, "r64", deep)
+		return 0, 1, err
+	}
+
+	if ntbm.Words[0]&1 == 0 {
+		return 0, 0, errors.Wrapf(ErrMalformed, "NodeTypeBM: root is not an inner node")
+	}
+
+	bigCnt := ns.BigInnerCnt
+	if bigCnt < 0 || bigCnt > innerCnt {
+		return 0, 0, errors.Wrapf(ErrMalformed, "BigInnerCnt: %d, inner count: %d", bigCnt, innerCnt)
+	}
+
+	// Querying calculates offset of an inner node in int32.
+	if int64(innerSize)*int64(innerCnt)+int64(bigInnerSize-innerSize)*int64(bigCnt) > math.MaxInt32 {
+		return 0, 0, errors.Wrapf(ErrMalformed, "too many inner nodes: %d, BigInnerCnt: %d", innerCnt, bigCnt)
+	}
+
+	shortCnt := int64(0)
+	if innerCnt > bigCnt {
+		err := validateBitmap("ShortBM", ns.ShortBM, int64(innerCnt), "r64", deep)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		if onesCount(ns.ShortBM.Words, 0, int64(bigCnt)) != 0 {
+			return 0, 0, errors.Wrapf(ErrMalformed, "ShortBM: big inner node is short")
+		}
+		shortCnt = onesCount(ns.ShortBM.Words, 0, int64(innerCnt))
+	}
+
+	innersSize := int64(bigInnerSize)*int64(bigCnt) +
+		int64(innerSize)*(int64(innerCnt-bigCnt)-shortCnt) +
+		int64(ns.ShortSize)*shortCnt
+
+	err := validateBitmap("Inners", ns.Inners, innersSize, "r128", deep)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// Every node except the root has a "1" in Inners pointing to it.
+	nodeCnt64 := 1 + onesCount(ns.Inners.Words, 0, innersSize)
+	if nodeCnt64 > MaxNodeCnt {
+		return 0, 0, errors.Wrapf(ErrMalformed, "Inners: too many nodes: %d", nodeCnt64)
+	}
+	nodeCnt := int32(nodeCnt64)
+
+	err = validateBitmap("NodeTypeBM", ntbm, nodeCnt64, "r64", deep)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// Walk through every inner node and its children.
+
+	from := int64(0)
+	childCnt := int64(0)
+	nodeId := int64(-1)
+
+	for i := int32(0); i < innerCnt; i++ {
+
+		nodeId = nextOne(ntbm.Words, nodeId+1)
+
+		var size, cnt int64
+		// whether the 0-th bit of the node bitmap is set.
+		var hasBit0 bool
+
+		if i < bigCnt {
+			size = int64(bigInnerSize)
+			cnt = onesCount(ns.Inners.Words, from, from+size)
+			hasBit0 = getBits(ns.Inners.Words, from, 1) == 1
+		} else if ns.ShortBM.Words[i>>6]&(1<<uint(i&63)) != 0 {
+			size = int64(ns.ShortSize)
+			short := getBits(ns.Inners.Words, from, size)
+			if short >= uint64(len(ns.ShortTable)) {
+				return 0, 0, errors.Wrapf(ErrMalformed, "ShortTable: %d-th inner node: no entry for short bitmap: %x", i, short)
+			}
+
+			full := ns.ShortTable[short]
+			if full>>uint(innerSize) != 0 || bits.OnesCount32(full) != bits.OnesCount64(short) {
+				return 0, 0, errors.Wrapf(ErrMalformed, "ShortTable: %d-th inner node: short bitmap: %x maps to: %x", i, short, full)
+			}
+			cnt = int64(bits.OnesCount64(short))
+			hasBit0 = full&1 == 1
+		} else {
+			size = int64(innerSize)
+			cnt = onesCount(ns.Inners.Words, from, from+size)
+			hasBit0 = getBits(ns.Inners.Words, from, 1) == 1
+		}
+
+		if cnt == 0 {
+			return 0, 0, errors.Wrapf(ErrMalformed, "Inners: %d-th inner node has no child", i)
+		}
+
+		firstChild := childCnt + 1
+		if firstChild <= nodeId {
+			return 0, 0, errors.Wrapf(ErrMalformed, "Inners: %d-th inner node: child id: %d <= parent id: %d", i, firstChild, nodeId)
+		}
+
+		// The 0-th bit is the branch for a key that ends at this node, it
+		// must point to a leaf.
+		if hasBit0 && getBits(ntbm.Words, firstChild, 1) == 1 {
+			return 0, 0, errors.Wrapf(ErrMalformed, "Inners: %d-th inner node: 0-th child: %d is not a leaf", i, firstChild)
+		}
+
+		childCnt += cnt
+		from += size
+	}
+
+	err = validateInnerPrefixes(ns.InnerPrefixes, innerCnt, deep)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return innerCnt, nodeCnt, nil
+}
+
+func validateInnerPrefixes(ips *VLenArray, innerCnt int32, deep bool) error {
+
+	if ips == nil {
+		return errors.Wrapf(ErrMalformed, "InnerPrefixes: nil")
+	}
+
+	if ips.EltCnt == 0 {
+		// querying does not access it
+		return nil
+	}
+
+	cnt, err := validateVLenArray("InnerPrefixes", ips, innerCnt, "r128", deep)
+	if err != nil {
+		return err
+	}
+
+	if ips.EltCnt != cnt {
+		return errors.Wrapf(ErrMalformed, "InnerPrefixes: EltCnt: %d, present: %d", ips.EltCnt, cnt)
+	}
+
+	if ips.PositionBM == nil {
+		// 2-byte prefix length
+		if int64(len(ips.Bytes)) < 2*int64(cnt) {
+			return errors.Wrapf(ErrMalformed, "InnerPrefixes: Bytes: %d, present: %d", len(ips.Bytes), cnt)
+		}
+		return nil
+	}
+
+	// A prefix is a bitstr: the prefix bytes followed by a mask byte.
+	// The mask byte indicates whether the last prefix byte is a full byte or a
+	// 4-bit word.
+
+	ps := ips.PositionBM.Words
+	pos := nextOne(ps, 0)
+	for i := int32(0); i < cnt; i++ {
+		next := nextOne(ps, pos+1)
+		p := ips.Bytes[pos:next]
+
+		if len(p) == 0 || (p[len(p)-1] != 0xff && (p[len(p)-1] != 0xf0 || len(p) == 1)) {
+			return errors.Wrapf(ErrMalformed, "InnerPrefixes: %d-th prefix: %x", i, p)
+		}
+		pos = next
+	}
+
+	return nil
+}
+
+// validateVLenArray checks a VLenArray with n elements.
+// It returns the number of present elements.
+func validateVLenArray(name string, va *VLenArray, n int32, presenceIndex string, deep bool) (int32, error) {
+
+	err := validateBitmap(name+".PresenceBM", va.PresenceBM, int64(n), presenceIndex, deep)
+	if err != nil {
+		return 0, err
+	}
+
+	cnt := int32(onesCount(va.PresenceBM.Words, 0, int64(n)))
+
+	if va.PositionBM == nil {
+		if va.FixedSize < 0 || int64(va.FixedSize)*int64(cnt) > int64(len(va.Bytes)) {
+			return 0, errors.Wrapf(ErrMalformed, "%s: FixedSize: %d, present: %d, Bytes: %d",
+				name, va.FixedSize, cnt, len(va.Bytes))
+		}
+		return cnt, nil
+	}
+
+	err = validateBitmap(name+".PositionBM", va.PositionBM, -1, "s32", deep)
+	if err != nil {
+		return 0, err
+	}
+
+	if cnt == 0 {
+		return 0, nil
+	}
+
+	// The i-th element is between the i-th and the i+1-th "1".
+	ps := va.PositionBM.Words
+	if onesCount(ps, 0, int64(len(ps))*64) < int64(cnt)+1 {
+		return 0, errors.Wrapf(ErrMalformed, "%s.PositionBM: less than %d positions", name, cnt+1)
+	}
+
+	pos := int64(-1)
+	for i := int32(0); i <= cnt; i++ {
+		pos = nextOne(ps, pos+1)
+	}
+	if pos > int64(len(va.Bytes)) {
+		return 0, errors.Wrapf(ErrMalformed, "%s.PositionBM: position: %d out of Bytes: %d", name, pos, len(va.Bytes))
+	}
+
+	return cnt, nil
+}
+
+// validateBitmap checks if a bitmap has at least "n" bits and there is no "1"
+// after the first n bits, and checks if its index of "indexType" has the
+// expected length and every entry is in bound.
+// If n is -1, bitmap size is not checked.
+// If deep is true, it also checks if the index is the same as the one rebuilt
+// with "indexType".
+func validateBitmap(name string, bm *Bitmap, n int64, indexType string, deep bool) error {
+
+	if bm == nil {
+		return errors.Wrapf(ErrMalformed, "%s: nil", name)
+	}
+
+	size := int64(len(bm.Words)) * 64
+	if n >= 0 {
+		if size < n {
+			return errors.Wrapf(ErrMalformed, "%s: size: %d, expected: %d", name, size, n)
+		}
+		if onesCount(bm.Words, n, size) != 0 {
+			return errors.Wrapf(ErrMalformed, "%s: has 1 after %d", name, n)
+		}
+	}
+
+	ones := onesCount(bm.Words, 0, size)
+
+	// Lengths are the same as those built by bitmap.IndexRank64,
+	// bitmap.IndexRank128 and bitmap.IndexSelect32R64.
+	rankLen := len(bm.Words)
+	selectLen := 0
+	switch indexType {
+	case "r128":
+		rankLen = len(bm.Words)/2 + 1
+	case "s32":
+		rankLen = len(bm.Words) + 1
+		selectLen = int((ones + 31) / 32)
+	}
+
+	if len(bm.RankIndex) != rankLen {
+		return errors.Wrapf(ErrMalformed, "%s: RankIndex: %d, expected: %d", name, len(bm.RankIndex), rankLen)
+	}
+	for i, r := range bm.RankIndex {
+		if r < 0 || int64(r) > ones {
+			return errors.Wrapf(ErrMalformed, "%s: RankIndex: %d-th: %d out of [0, %d]", name, i, r, ones)
+		}
+	}
+
+	if len(bm.SelectIndex) != selectLen {
+		return errors.Wrapf(ErrMalformed, "%s: SelectIndex: %d, expected: %d", name, len(bm.SelectIndex), selectLen)
+	}
+	for i, p := range bm.SelectIndex {
+		if p < 0 || int64(p) >= size {
+			return errors.Wrapf(ErrMalformed, "%s: SelectIndex: %d-th: %d out of [0, %d)", name, i, p, size)
+		}
+	}
+
+	if deep {
+		rebuilt := &Bitmap{Words: bm.Words}
+		rebuilt.indexit(indexType)
+
+		if !int32sEqual(bm.RankIndex, rebuilt.RankIndex) {
+			return errors.Wrapf(ErrMalformed, "%s: invalid RankIndex", name)
+		}
+		if !int32sEqual(bm.SelectIndex, rebuilt.SelectIndex) {
+			return errors.Wrapf(ErrMalformed, "%s: invalid SelectIndex", name)
+		}
+	}
+
+	return nil
+}
+
+// validateValue checks if a value can be decoded.
+func validateValue(e encode.Encoder, b []byte) (err error) {
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Errorf("failed to decode: %v", r)
+		}
+	}()
+
+	n := e.GetEncodedSize(b)
+	if n != len(b) {
+		return errors.Errorf("encoded size: %d, actual: %d", n, len(b))
+	}
+
+	e.Decode(b)
+	return nil
+}
+
+// onesCount returns the number of "1" in bit range [from, to).
+func onesCount(words []uint64, from, to int64) int64 {
+
+	cnt := int64(0)
+	for from < to {
+		w := words[from>>6] >> uint(from&63)
+		n := 64 - from&63
+		if to-from < n {
+			n = to - from
+			w &= (1 << uint(n)) - 1
+		}
+		cnt += int64(bits.OnesCount64(w))
+		from += n
+	}
+	return cnt
+}
+
+// getBits returns n bits starting from bit "from". n must be <= 64.
+func getBits(words []uint64, from, n int64) uint64 {
+	var v uint64
+	for i := int64(0); i < n; i++ {
+		j := from + i
+		v |= (words[j>>6] >> uint(j&63) & 1) << uint(i)
+	}
+	return v
+}
+
+// nextOne returns the index of the first "1" at or after bit "from", or the
+// size of the bitmap if there is no such "1".
+func nextOne(words []uint64, from int64) int64 {
+
+	size := int64(len(words)) * 64
+	for from < size {
+		w := words[from>>6] >> uint(from&63)
+		if w != 0 {
+			return from + int64(bits.TrailingZeros64(w))
+		}
+		from += 64 - from&63
+	}
+	return size
+}
+
+func int32sEqual(a, b []int32) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
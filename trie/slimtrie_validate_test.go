@@ -0,0 +1,312 @@
+package trie
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/openacid/errors"
+	"github.com/openacid/slim/encode"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlimTrie_Validate(t *testing.T) {
+
+	ta := require.New(t)
+
+	keys := getKeys("20kl10")[:2000]
+	values := makeI32s(len(keys))
+
+	opts := []Opt{
+		{},
+		{InnerPrefix: Bool(true)},
+		{Complete: Bool(true)},
+	}
+
+	for _, opt := range opts {
+		st, err := NewSlimTrie(encode.I32{}, keys, values, opt)
+		ta.NoError(err)
+		ta.NoError(st.Validate(), "opt: %+v", opt)
+	}
+
+	// empty, single key and no value
+
+	st, err := NewSlimTrie(encode.I32{}, nil, nil)
+	ta.NoError(err)
+	ta.NoError(st.Validate())
+
+	st, err = NewSlimTrie(encode.I32{}, []string{"a"}, []int32{1})
+	ta.NoError(err)
+	ta.NoError(st.Validate())
+
+	st, err = NewSlimTrie(nil, keys, nil, Opt{Complete: Bool(true)})
+	ta.NoError(err)
+	ta.NoError(st.Validate())
+}
+
+func TestSlimTrie_Validate_malformed(t *testing.T) {
+
+	ta := require.New(t)
+
+	keys := getKeys("20kl10")[:2000]
+	values := makeI32s(len(keys))
+
+	st, err := NewSlimTrie(encode.I32{}, keys, values, Opt{Complete: Bool(true)})
+	ta.NoError(err)
+	ta.Greater(onesCount(st.inner.ShortBM.Words, 0, int64(len(st.inner.ShortBM.Words))*64), int64(0))
+
+	cases := []struct {
+		name   string
+		mutate func(ns *Slim)
+		want   string
+	}{
+		{"NodeTypeBM truncated",
+			func(ns *Slim) {
+				ns.NodeTypeBM.Words = ns.NodeTypeBM.Words[:len(ns.NodeTypeBM.Words)-1]
+				ns.NodeTypeBM.indexit("r64")
+			},
+			// less inner nodes thus Inners is too large.
+			"Inners: has 1 after"},
+		{"NodeTypeBM RankIndex truncated",
+			func(ns *Slim) { ns.NodeTypeBM.RankIndex = ns.NodeTypeBM.RankIndex[1:] },
+			"NodeTypeBM: RankIndex: "},
+		{"NodeTypeBM RankIndex out of bound",
+			func(ns *Slim) { ns.NodeTypeBM.RankIndex[1] = 1 << 30 },
+			"NodeTypeBM: RankIndex: 1-th"},
+		{"root is leaf",
+			func(ns *Slim) { ns.NodeTypeBM.Words[0] &^= 1; ns.NodeTypeBM.indexit("r64") },
+			"root is not an inner node"},
+		{"BigInnerCnt",
+			func(ns *Slim) { ns.BigInnerCnt = 1 << 30 },
+			"BigInnerCnt"},
+		{"ShortSize",
+			func(ns *Slim) { ns.ShortSize = maxShortSize + 1 },
+			"ShortSize"},
+		{"Inners truncated",
+			func(ns *Slim) {
+				ns.Inners.Words = ns.Inners.Words[:len(ns.Inners.Words)-1]
+				ns.Inners.indexit("r128")
+			},
+			"Inners: size"},
+		{"ShortTable truncated",
+			func(ns *Slim) { ns.ShortTable = ns.ShortTable[:0] },
+			"ShortTable"},
+		{"ShortTable popcount",
+			func(ns *Slim) {
+				for i := range ns.ShortTable {
+					ns.ShortTable[i] = 0
+				}
+			},
+			"ShortTable"},
+		{"InnerPrefixes EltCnt",
+			func(ns *Slim) { ns.InnerPrefixes.EltCnt++ },
+			"InnerPrefixes: EltCnt"},
+		{"InnerPrefixes Bytes truncated",
+			func(ns *Slim) { ns.InnerPrefixes.Bytes = ns.InnerPrefixes.Bytes[:len(ns.InnerPrefixes.Bytes)-1] },
+			"InnerPrefixes.PositionBM: position"},
+		{"InnerPrefixes invalid prefix",
+			func(ns *Slim) { ns.InnerPrefixes.Bytes[len(ns.InnerPrefixes.Bytes)-1] = 0x0f },
+			"InnerPrefixes: "},
+		{"InnerPrefixes SelectIndex out of bound",
+			func(ns *Slim) { ns.InnerPrefixes.PositionBM.SelectIndex[0] = -1 },
+			"InnerPrefixes.PositionBM: SelectIndex: 0-th"},
+		{"LeafPrefixes truncated",
+			func(ns *Slim) {
+				ns.LeafPrefixes.PresenceBM.Words = ns.LeafPrefixes.PresenceBM.Words[:1]
+				ns.LeafPrefixes.PresenceBM.indexit("r64")
+			},
+			"LeafPrefixes.PresenceBM: size"},
+		{"Leaves N",
+			func(ns *Slim) { ns.Leaves.N++ },
+			"Leaves: N"},
+		{"Leaves EltCnt",
+			func(ns *Slim) { ns.Leaves.EltCnt-- },
+			"Leaves: EltCnt"},
+		{"Leaves Bytes truncated",
+			func(ns *Slim) { ns.Leaves.Bytes = ns.Leaves.Bytes[:len(ns.Leaves.Bytes)-1] },
+			"Leaves: FixedSize"},
+	}
+
+	for _, c := range cases {
+		ns := proto.Clone(st.inner).(*Slim)
+		c.mutate(ns)
+
+		st2 := &SlimTrie{inner: ns, encoder: st.encoder}
+		err := st2.Validate()
+		ta.Equal(ErrMalformed, errors.Cause(err), "case: %s", c.name)
+		ta.Contains(err.Error(), c.want, "case: %s", c.name)
+
+		err = st2.ValidateDeep()
+		ta.Equal(ErrMalformed, errors.Cause(err), "case: %s", c.name)
+		ta.Contains(err.Error(), c.want, "case: %s", c.name)
+	}
+
+	// Only ValidateDeep rebuilds indexes to compare.
+
+	deepCases := []struct {
+		name   string
+		mutate func(ns *Slim)
+		want   string
+	}{
+		{"NodeTypeBM invalid RankIndex",
+			func(ns *Slim) { ns.NodeTypeBM.RankIndex[1]++ },
+			"NodeTypeBM: invalid RankIndex"},
+		{"InnerPrefixes invalid SelectIndex",
+			func(ns *Slim) { ns.InnerPrefixes.PositionBM.SelectIndex[0]++ },
+			"InnerPrefixes.PositionBM: invalid SelectIndex"},
+	}
+
+	for _, c := range deepCases {
+		ns := proto.Clone(st.inner).(*Slim)
+		c.mutate(ns)
+
+		st2 := &SlimTrie{inner: ns, encoder: st.encoder}
+		ta.NoError(st2.Validate(), "case: %s", c.name)
+
+		err := st2.ValidateDeep()
+		ta.Equal(ErrMalformed, errors.Cause(err), "case: %s", c.name)
+		ta.Contains(err.Error(), c.want, "case: %s", c.name)
+	}
+
+	// undecodable value
+
+	st2 := &SlimTrie{inner: st.inner, encoder: encode.I64{}}
+	ta.NoError(st2.Validate())
+
+	err = st2.ValidateDeep()
+	ta.Equal(ErrMalformed, errors.Cause(err))
+	ta.Contains(err.Error(), "Leaves: 0-th value")
+}
+
+func TestSlimTrie_Validate_children(t *testing.T) {
+
+	ta := require.New(t)
+
+	cases := []struct {
+		innerIds []int32
+		nodeCnt  int32
+		labels   []int32
+		want     string
+	}{
+		{[]int32{0}, 1, []int32{}, "no child"},
+		{[]int32{0, 2}, 3, []int32{1, 17 + 1}, "child id: 2 <= parent id: 2"},
+		{[]int32{0, 1}, 3, []int32{1, 17 + 1}, ""},
+	}
+
+	for i, c := range cases {
+		ns := &Slim{
+			NodeTypeBM:    newBM(c.innerIds, c.nodeCnt, "r64"),
+			Inners:        newBM(c.labels, 17*int32(len(c.innerIds)), "r128"),
+			ShortBM:       newBM(nil, int32(len(c.innerIds)), "r64"),
+			InnerPrefixes: &VLenArray{},
+		}
+
+		st := &SlimTrie{inner: ns}
+		err := st.Validate()
+		if c.want == "" {
+			ta.NoError(err, "%d-th: case: %+v", i+1, c)
+		} else {
+			ta.Equal(ErrMalformed, errors.Cause(err), "%d-th: case: %+v", i+1, c)
+			ta.Contains(err.Error(), c.want, "%d-th: case: %+v", i+1, c)
+		}
+	}
+}
+
+func TestSlimTrie_Unmarshal_malformed(t *testing.T) {
+
+	ta := require.New(t)
+
+	keys := getKeys("20kl10")[:2000]
+	values := makeI32s(len(keys))
+
+	st, err := NewSlimTrie(encode.I32{}, keys, values, Opt{Complete: Bool(true)})
+	ta.NoError(err)
+
+	st.inner.Leaves.N++
+
+	buf, err := st.Marshal()
+	ta.NoError(err)
+
+	st2 := &SlimTrie{encoder: encode.I32{}}
+	err = st2.Unmarshal(buf)
+	ta.Equal(ErrMalformed, errors.Cause(err))
+
+	st2 = &SlimTrie{encoder: encode.I32{}}
+	err = st2.UnmarshalWithOpt(buf, UnmarshalOpt{SkipValidate: true})
+	ta.NoError(err)
+}
+
+func TestSlimTrie_Unmarshal_deepValidate(t *testing.T) {
+
+	ta := require.New(t)
+
+	keys := getKeys("20kl10")[:2000]
+
+	st, err := NewSlimTrie(encode.String16{}, keys, keys, Opt{Complete: Bool(true)})
+	ta.NoError(err)
+
+	buf, err := st.Marshal()
+	ta.NoError(err)
+
+	st2 := &SlimTrie{encoder: encode.String16{}}
+	err = st2.UnmarshalWithOpt(buf, UnmarshalOpt{DeepValidate: true})
+	ta.NoError(err)
+	v, found := st2.Get(keys[0])
+	ta.True(found)
+	ta.Equal(keys[0], v)
+
+	// The length of the first value does not match its size.
+	st.inner.Leaves.Bytes[0]++
+
+	buf, err = st.Marshal()
+	ta.NoError(err)
+
+	st2 = &SlimTrie{encoder: encode.String16{}}
+	err = st2.Unmarshal(buf)
+	ta.NoError(err)
+
+	st2 = &SlimTrie{encoder: encode.String16{}}
+	err = st2.UnmarshalWithOpt(buf, UnmarshalOpt{DeepValidate: true})
+	ta.Equal(ErrMalformed, errors.Cause(err))
+	ta.Contains(err.Error(), "Leaves: 0-th value")
+}
+
+func TestSlimTrie_Unmarshal_corruptedIndex(t *testing.T) {
+
+	ta := require.New(t)
+
+	keys := getKeys("20kl10")[:2000]
+	values := makeI32s(len(keys))
+
+	cases := []struct {
+		name string
+		bm   func(ns *Slim) *Bitmap
+	}{
+		{"Inners", func(ns *Slim) *Bitmap { return ns.Inners }},
+		{"NodeTypeBM", func(ns *Slim) *Bitmap { return ns.NodeTypeBM }},
+		{"Leaves.PresenceBM", func(ns *Slim) *Bitmap { return ns.Leaves.PresenceBM }},
+	}
+
+	for _, c := range cases {
+
+		st, err := NewSlimTrie(encode.I32{}, keys, values, Opt{Complete: Bool(true)})
+		ta.NoError(err)
+
+		// In bound but does not match the bitmap. It is marshaled with valid
+		// checksums.
+		bm := c.bm(st.inner)
+		ones := int32(onesCount(bm.Words, 0, int64(len(bm.Words))*64))
+		for i := range bm.RankIndex {
+			bm.RankIndex[i] = ones
+		}
+
+		buf, err := st.Marshal()
+		ta.NoError(err, "case: %s", c.name)
+
+		st2 := &SlimTrie{}
+		err = st2.Unmarshal(buf)
+		ta.NoError(err, "case: %s", c.name)
+		ta.NoError(st2.ValidateDeep(), "case: %s", c.name)
+
+		testPresentKeysGet(t, st2, keys, values)
+	}
+}
@@ -0,0 +1,68 @@
+package trie
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/openacid/slim/encode"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlimTrie_varintValue(t *testing.T) {
+
+	ta := require.New(t)
+
+	keys := []string{
+		"Aaron",
+		"Agatha",
+		"Al",
+		"Albert",
+		"Alexander",
+		"Alison",
+	}
+
+	cases := []struct {
+		encoder encode.Encoder
+		values  interface{}
+	}{
+		{encode.UVarint{}, []uint64{0, 1, 127, 128, 300, 1 << 63}},
+		{encode.Varint{}, []int64{0, -1, 63, -64, 1 << 40, -1 << 62}},
+	}
+
+	for _, c := range cases {
+
+		st, err := NewSlimTrie(c.encoder, keys, c.values, Opt{Complete: Bool(true)})
+		ta.NoError(err)
+
+		buf, err := st.Marshal()
+		ta.NoError(err)
+
+		st2, err := NewSlimTrie(c.encoder, nil, nil)
+		ta.NoError(err)
+		err = st2.Unmarshal(buf)
+		ta.NoError(err)
+
+		for _, s := range []*SlimTrie{st, st2} {
+
+			vals := []interface{}{}
+			s.ScanFrom("", true, true, func(k, v []byte) bool {
+				_, x := c.encoder.Decode(v)
+				ta.Equal(len(v), c.encoder.GetEncodedSize(v))
+				vals = append(vals, x)
+				return true
+			})
+
+			for i, key := range keys {
+				want := getV(reflect.ValueOf(c.values), int32(i))
+
+				v, found := s.Get(key)
+				ta.True(found, "Get:%v", key)
+				ta.Equal(want, v, "Get:%v", key)
+				ta.Equal(want, vals[i], "Scan:%v", key)
+			}
+
+			_, found := s.Get("Alb")
+			ta.False(found)
+		}
+	}
+}
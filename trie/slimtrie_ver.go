@@ -1,3 +1,3 @@
 package trie
 
-const slimtrieVersion = "0.5.12"
+const slimtrieVersion = "0.5.13"